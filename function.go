@@ -1,52 +1,29 @@
 package pendaftaran
 
 import (
+	"context"
 	"log"
 	"net/http"
-	"os"
 	"sync"
+	"time"
 
 	"github.com/ulbithebest/BE-pendaftaran/internal/config"
-	"github.com/ulbithebest/BE-pendaftaran/internal/handler"
 	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
 	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
-
-	"github.com/go-chi/chi/v5"
-	chiMiddleware "github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/server"
+	"github.com/ulbithebest/BE-pendaftaran/internal/storage"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
 )
 
 // Global router instance
 var (
-	router *chi.Mux
+	router http.Handler
 	once   sync.Once
 )
 
-// allowlist for CORS preflight handler (manual path)
-var allowedOrigins = map[string]struct{}{
-	"https://ulbithebest.github.io": {},
-	"http://localhost:5500":         {},
-	"http://127.0.0.1:5500":         {},
-	"http://127.0.0.1:5501":         {},
-	"http://localhost:5501":         {},
-}
-
-func setCORSHeaders(w http.ResponseWriter, r *http.Request) {
-	origin := r.Header.Get("Origin")
-	if _, ok := allowedOrigins[origin]; !ok {
-		return
-	}
-	// Mirror the request origin (required when credentials are used)
-	w.Header().Set("Access-Control-Allow-Origin", origin)
-	w.Header().Set("Vary", "Origin")
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token, X-Requested-With")
-	w.Header().Set("Access-Control-Max-Age", "300")
-}
-
 // initializeApp initializes the application router and database connection
 func initializeApp() {
 	log.Println("🚀 Initializing Cloud Function...")
@@ -68,84 +45,39 @@ func initializeApp() {
 	}
 	config.LoadDatabaseCredentials(credentials)
 
-	// 4. Setup Chi router
-	r := chi.NewRouter()
-
-	// 5. Global middlewares
-	r.Use(chiMiddleware.RealIP)
-	r.Use(middleware.RequestLogMiddleware)
-	r.Use(chiMiddleware.Logger)
-	r.Use(chiMiddleware.Recoverer)
-
-	// 6. Setup CORS
-	corsOptions := cors.Options{
-		AllowedOrigins: []string{
-			"https://ulbithebest.github.io", // GitHub Pages frontend
-			"http://localhost:5500",         // Local dev
-			"http://127.0.0.1:5500",
-			"http://127.0.0.1:5501",
-			"http://localhost:5501",
-		},
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
+	db := repository.Database()
+	if db == nil {
+		log.Println("❌ Database not available, router not initialized")
+		return
 	}
-	r.Use(cors.Handler(corsOptions))
 
-	// 7. Health check
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"healthy","service":"pendaftaran-api"}`))
-	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := repository.EnsureIndexes(ctx, db); err != nil {
+		log.Printf("⚠️ Failed to ensure indexes: %v", err)
+	}
+	cancel()
 
-	// 8. Public routes
-	r.Post("/register", handler.RegisterHandler)
-	r.Post("/login", handler.LoginHandler)
-
-	// 9. Protected routes
-	r.Route("/api", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware)
-
-		// User endpoints
-		r.Get("/user/profile", handler.GetUserProfileHandler)
-		r.Post("/user/registration", handler.SubmitRegistrationHandler)
-		r.Get("/user/my-registration", handler.GetUserRegistrationHandler)
-		r.Get("/info", handler.GetAllInfoHandler)
-
-		// File server (protected)
-		if _, err := os.Stat("./uploads"); err == nil {
-			fileServer := http.FileServer(http.Dir("./uploads"))
-			r.Handle("/uploads/*", http.StripPrefix("/api/uploads/", fileServer))
-		}
-
-		// Admin-only routes
-		r.Route("/admin", func(r chi.Router) {
-			r.Use(middleware.AdminOnlyMiddleware)
-
-			r.Get("/registrations-with-details", handler.GetAllRegistrationsDetailHandler)
-			r.Patch("/registrations/{id}", handler.UpdateRegistrationDetailsHandler)
-			r.Patch("/registrations/bulk-update", handler.BulkUpdateStatusHandler)
-			r.Get("/users", handler.GetAllUsersHandler)
-			r.With(middleware.SuperAdminOnlyMiddleware).Patch("/users/{id}", handler.UpdateUserHandler)
-			r.With(middleware.SuperAdminOnlyMiddleware).Patch("/users/{id}/password", handler.ResetUserPasswordHandler)
-			r.Delete("/registrations/{id}", handler.DeleteRegistrationHandler)
-			r.Post("/info", handler.CreateInfoHandler)
-			r.Put("/info/{id}", handler.UpdateInfoHandler)
-			r.Delete("/info/{id}", handler.DeleteInfoHandler)
-			r.With(middleware.SuperAdminOnlyMiddleware).Get("/logs", handler.GetAppLogsHandler)
-		})
-	})
+	// Rate limit in-process tidak dibagi antar instance; gunakan MongoDB jika RATE_LIMIT_STORE=mongo
+	var rateLimitStore middleware.RateLimitStore
+	if cfg.RateLimitStore == "mongo" {
+		rateLimitStore = repository.NewMongoRateLimitRepository(db)
+	}
 
-	router = r
+	// 4. Setup router (shared with run/main.go)
+	router = server.NewRouter(server.Options{
+		Repositories:   repository.NewMongoRepositories(db),
+		Storage:        storage.NewCloudinaryStorage(),
+		Notifier:       notify.New(cfg),
+		RateLimitStore: rateLimitStore,
+		UploadsDir:     "./uploads",
+	})
 	log.Println("✅ Router initialized successfully")
 }
 
 // URL handles all HTTP requests - entry point for Cloud Function
 func URL(w http.ResponseWriter, r *http.Request) {
 	// Set CORS headers FIRST, before any initialization that might fail
-	setCORSHeaders(w, r)
+	server.SetCORSHeaders(w, r)
 
 	// Handle preflight (OPTIONS) immediately
 	if r.Method == http.MethodOptions {
@@ -159,9 +91,7 @@ func URL(w http.ResponseWriter, r *http.Request) {
 	// Ensure router exists
 	if router == nil {
 		log.Println("❌ Router not initialized")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(`{"error":"Internal Server Error"}`))
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Server belum siap")
 		return
 	}
 
@@ -172,7 +102,3 @@ func URL(w http.ResponseWriter, r *http.Request) {
 func init() {
 	functions.HTTP("Pendaftaran", URL)
 }
-
-
-
-
@@ -26,6 +26,7 @@ type Entry struct {
 	UserRole      string    `json:"user_role,omitempty"`
 	ResponseBytes int       `json:"response_bytes,omitempty"`
 	ContentLength int64     `json:"content_length,omitempty"`
+	RequestID     string    `json:"request_id,omitempty"`
 }
 
 var (
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/o1egl/paseto/v2"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const emailVerificationAudience = "himatif-email-verification"
+
+// GenerateEmailVerificationToken membuat kode verifikasi email yang ditandatangani dan
+// memiliki masa berlaku. Email ikut disimpan agar kode tidak berlaku jika email diganti.
+func GenerateEmailVerificationToken(userID primitive.ObjectID, email string) (string, error) {
+	now := time.Now()
+	cfg := config.GetConfig()
+
+	jsonToken := paseto.JSONToken{
+		Audience:   emailVerificationAudience,
+		Issuer:     "himatif-api",
+		Subject:    userID.Hex(),
+		IssuedAt:   now,
+		Expiration: now.Add(cfg.EmailVerificationTTL),
+		NotBefore:  now,
+	}
+	jsonToken.Set("email", email)
+
+	key, err := getPasetoKey(cfg.PasetoSecretKey)
+	if err != nil {
+		return "", err
+	}
+
+	return paseto.NewV2().Encrypt(key, jsonToken, nil)
+}
+
+// VerifyEmailVerificationToken memverifikasi kode verifikasi email dan mengembalikan user ID serta email-nya
+func VerifyEmailVerificationToken(tokenString string) (primitive.ObjectID, string, error) {
+	key, err := getPasetoKey(config.GetConfig().PasetoSecretKey)
+	if err != nil {
+		return primitive.NilObjectID, "", errors.New("invalid token")
+	}
+
+	var jsonToken paseto.JSONToken
+	if err := paseto.NewV2().Decrypt(tokenString, key, &jsonToken, nil); err != nil {
+		return primitive.NilObjectID, "", errors.New("invalid token")
+	}
+
+	if err := jsonToken.Validate(paseto.ValidAt(time.Now()), paseto.ForAudience(emailVerificationAudience)); err != nil {
+		return primitive.NilObjectID, "", err
+	}
+
+	var email string
+	if err := jsonToken.Get("email", &email); err != nil {
+		return primitive.NilObjectID, "", errors.New("token missing email claim")
+	}
+
+	userID, err := primitive.ObjectIDFromHex(jsonToken.Subject)
+	if err != nil {
+		return primitive.NilObjectID, "", errors.New("invalid user id in token")
+	}
+
+	return userID, email, nil
+}
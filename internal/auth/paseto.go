@@ -12,24 +12,36 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const accessTokenAudience = "himatif-app"
+
 // Kita tetap gunakan struct ini untuk kemudahan di bagian lain aplikasi,
 // meskipun kita tidak akan menyimpannya langsung ke dalam token.
 type PasetoPayload struct {
-	UserID primitive.ObjectID `json:"user_id"`
-	NIM    string             `json:"nim"`
-	Role   string             `json:"role"`
+	UserID       primitive.ObjectID `json:"user_id"`
+	NIM          string             `json:"nim"`
+	Role         string             `json:"role"`
+	TokenID      string             `json:"token_id"`
+	ExpiresAt    time.Time          `json:"expires_at"`
+	TokenVersion int                `json:"token_version"`
 }
 
-// GenerateToken membuat token Paseto baru untuk user (VERSI BARU)
-func GenerateToken(userID primitive.ObjectID, nim, role string) (string, error) {
+// GenerateToken membuat access token Paseto berumur pendek untuk user.
+// Jti berisi ID token unik sehingga token bisa dicabut satu per satu, sedangkan
+// tokenVersion membuat semua token user tidak berlaku saat versinya dinaikkan.
+func GenerateToken(userID primitive.ObjectID, nim, role string, tokenVersion int) (string, *PasetoPayload, error) {
 	now := time.Now()
-	exp := now.Add(24 * time.Hour)
 	cfg := config.GetConfig()
+	exp := now.Add(cfg.AccessTokenTTL)
+
+	tokenID, err := randomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
 
 	jsonToken := paseto.JSONToken{
-		Audience:   "himatif-app",
+		Audience:   accessTokenAudience,
 		Issuer:     "himatif-api",
-		Jti:        nim,
+		Jti:        tokenID,
 		Subject:    userID.Hex(),
 		IssuedAt:   now,
 		Expiration: exp,
@@ -38,13 +50,28 @@ func GenerateToken(userID primitive.ObjectID, nim, role string) (string, error)
 
 	// PERBAIKAN DI SINI: Panggil .Set() secara langsung
 	jsonToken.Set("role", role)
+	jsonToken.Set("nim", nim)
+	jsonToken.Set("ver", tokenVersion)
 
 	key, err := getPasetoKey(cfg.PasetoSecretKey)
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+
+	token, err := paseto.NewV2().Encrypt(key, jsonToken, nil)
+	if err != nil {
+		return "", nil, err
 	}
 
-	return paseto.NewV2().Encrypt(key, jsonToken, nil)
+	payload := &PasetoPayload{
+		UserID:       userID,
+		NIM:          nim,
+		Role:         role,
+		TokenID:      tokenID,
+		ExpiresAt:    exp,
+		TokenVersion: tokenVersion,
+	}
+	return token, payload, nil
 }
 
 // VerifyToken memverifikasi token Paseto (VERSI BARU)
@@ -61,8 +88,8 @@ func VerifyToken(tokenString string) (*PasetoPayload, error) {
 		return nil, errors.New("invalid token")
 	}
 
-	// Validasi klaim waktu
-	if err := jsonToken.Validate(); err != nil {
+	// Validasi klaim waktu dan pastikan ini access token, bukan token verifikasi email
+	if err := jsonToken.Validate(paseto.ValidAt(time.Now()), paseto.ForAudience(accessTokenAudience)); err != nil {
 		return nil, err
 	}
 
@@ -72,6 +99,16 @@ func VerifyToken(tokenString string) (*PasetoPayload, error) {
 		return nil, errors.New("token missing role claim")
 	}
 
+	var nim string
+	if err := jsonToken.Get("nim", &nim); err != nil {
+		return nil, errors.New("token missing nim claim")
+	}
+
+	var tokenVersion int
+	if err := jsonToken.Get("ver", &tokenVersion); err != nil {
+		return nil, errors.New("token missing version claim")
+	}
+
 	// Ambil UserID dari 'Subject' dan konversi kembali ke ObjectID
 	userID, err := primitive.ObjectIDFromHex(jsonToken.Subject)
 	if err != nil {
@@ -81,9 +118,12 @@ func VerifyToken(tokenString string) (*PasetoPayload, error) {
 	// Buat ulang struct PasetoPayload untuk dikembalikan agar
 	// bagian lain dari aplikasi tidak perlu diubah.
 	payload := &PasetoPayload{
-		UserID: userID,
-		NIM:    jsonToken.Jti,
-		Role:   role,
+		UserID:       userID,
+		NIM:          nim,
+		Role:         role,
+		TokenID:      jsonToken.Jti,
+		ExpiresAt:    jsonToken.Expiration,
+		TokenVersion: tokenVersion,
 	}
 
 	return payload, nil
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaqueToken membuat token acak (refresh token, token reset password, dsb)
+// beserta hash yang disimpan di database
+func GenerateOpaqueToken() (token string, hash string, err error) {
+	token, err = randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+// HashToken menghitung hash SHA-256 dari token opaque, sehingga token asli tidak perlu disimpan
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken membuat string acak URL-safe dari n byte crypto/rand
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// temporaryPasswordAlphabet tidak memuat karakter yang mudah tertukar (0/O, 1/l/I)
+const temporaryPasswordAlphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateTemporaryPassword membuat password sementara acak yang mudah diketik ulang,
+// dipakai untuk akun yang dibuat oleh admin (misal impor CSV)
+func GenerateTemporaryPassword(length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		// Bias modulo kecil ini dapat diterima untuk password sementara
+		buf[i] = temporaryPasswordAlphabet[int(b)%len(temporaryPasswordAlphabet)]
+	}
+	return string(buf), nil
+}
@@ -3,10 +3,19 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// RateLimit adalah batas request dalam format "<requests>/<durasi>", misal "10/1m"
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
 // Config menampung semua variabel konfigurasi aplikasi
 type Config struct {
 	MongoURI            string
@@ -16,6 +25,33 @@ type Config struct {
 	CloudinaryCloudName string
 	CloudinaryApiKey    string
 	CloudinaryApiSecret string
+	AccessTokenTTL      time.Duration
+	RefreshTokenTTL     time.Duration
+	PasswordResetTTL    time.Duration
+	PasswordResetURL    string
+	NotifyLogFile       string
+
+	EmailVerificationTTL     time.Duration
+	EmailVerificationURL     string
+	RequireEmailVerification bool
+	SMTPHost                 string
+	SMTPPort                 string
+	SMTPUsername             string
+	SMTPPassword             string
+	SMTPFrom                 string
+
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginLockoutDuration  time.Duration
+	LoginBackoffBase      time.Duration
+
+	RateLimitStore  string // "memory" (default) atau "mongo" untuk deployment multi-instance
+	RateLimitAuth   RateLimit
+	RateLimitAPI    RateLimit
+	RateLimitUpload RateLimit
+
+	// Jumlah reverse proxy tepercaya di depan server. 0 berarti header X-Forwarded-For/X-Real-IP diabaikan.
+	TrustedProxyHops int
 }
 
 var appConfig *Config
@@ -28,6 +64,75 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getDurationWithDefault mengambil environment variable berformat durasi (misal "15m"), fallback ke default value
+func getDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("⚠️ Invalid duration for %s (%q), using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getIntWithDefault mengambil environment variable berupa bilangan bulat positif, fallback ke default value
+func getIntWithDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("⚠️ Invalid integer for %s (%q), using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getRateLimitWithDefault mengambil environment variable berformat "<requests>/<durasi>" (misal "10/1m").
+// Nilai "0" atau "off" mematikan rate limit untuk grup tersebut.
+func getRateLimitWithDefault(key string, defaultValue RateLimit) RateLimit {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	if value == "0" || strings.EqualFold(value, "off") {
+		return RateLimit{}
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) == 2 {
+		requests, errRequests := strconv.Atoi(strings.TrimSpace(parts[0]))
+		per, errPer := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if errRequests == nil && errPer == nil && requests > 0 && per > 0 {
+			return RateLimit{Requests: requests, Per: per}
+		}
+	}
+
+	log.Printf("⚠️ Invalid rate limit for %s (%q), using default %d/%s", key, value, defaultValue.Requests, defaultValue.Per)
+	return defaultValue
+}
+
+// getBoolWithDefault mengambil environment variable boolean ("true", "1", dst), fallback ke default value
+func getBoolWithDefault(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("⚠️ Invalid boolean for %s (%q), using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
 // LoadConfig memuat konfigurasi dari file .env dan environment variables
 func LoadConfig() {
 	// Coba load .env file (untuk development lokal)
@@ -40,6 +145,33 @@ func LoadConfig() {
 		MongoURI:     getEnvWithDefault("MONGO_URI", ""),
 		DatabaseName: getEnvWithDefault("MONGO_DATABASE", "himatif"),
 		ServerPort:   getEnvWithDefault("SERVER_PORT", ":8080"),
+
+		AccessTokenTTL:  getDurationWithDefault("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDurationWithDefault("REFRESH_TOKEN_TTL", 7*24*time.Hour),
+
+		PasswordResetTTL: getDurationWithDefault("PASSWORD_RESET_TTL", time.Hour),
+		PasswordResetURL: getEnvWithDefault("PASSWORD_RESET_URL", ""),
+		NotifyLogFile:    getEnvWithDefault("NOTIFY_LOG_FILE", ""),
+
+		EmailVerificationTTL:     getDurationWithDefault("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		EmailVerificationURL:     getEnvWithDefault("EMAIL_VERIFICATION_URL", ""),
+		RequireEmailVerification: getBoolWithDefault("REQUIRE_EMAIL_VERIFICATION", false),
+		SMTPHost:                 getEnvWithDefault("SMTP_HOST", ""),
+		SMTPPort:                 getEnvWithDefault("SMTP_PORT", "587"),
+		SMTPUsername:             getEnvWithDefault("SMTP_USERNAME", ""),
+		SMTPFrom:                 getEnvWithDefault("SMTP_FROM", ""),
+
+		LoginMaxAttempts:      getIntWithDefault("LOGIN_MAX_ATTEMPTS", 5),
+		LoginMaxAttemptsPerIP: getIntWithDefault("LOGIN_MAX_ATTEMPTS_PER_IP", 30),
+		LoginLockoutDuration:  getDurationWithDefault("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LoginBackoffBase:      getDurationWithDefault("LOGIN_BACKOFF_BASE", time.Second),
+
+		RateLimitStore:  getEnvWithDefault("RATE_LIMIT_STORE", "memory"),
+		RateLimitAuth:   getRateLimitWithDefault("RATE_LIMIT_AUTH", RateLimit{Requests: 10, Per: time.Minute}),
+		RateLimitAPI:    getRateLimitWithDefault("RATE_LIMIT_API", RateLimit{Requests: 120, Per: time.Minute}),
+		RateLimitUpload: getRateLimitWithDefault("RATE_LIMIT_UPLOAD", RateLimit{Requests: 5, Per: time.Hour}),
+
+		TrustedProxyHops: getIntWithDefault("TRUSTED_PROXY_HOPS", 0),
 	}
 
 	// Validasi konfigurasi penting untuk koneksi database
@@ -62,6 +194,7 @@ func LoadDatabaseCredentials(credentials map[string]string) {
 	appConfig.CloudinaryCloudName = getCredentialWithFallback(credentials, "CLOUDINARY_CLOUD_NAME", "")
 	appConfig.CloudinaryApiKey = getCredentialWithFallback(credentials, "CLOUDINARY_API_KEY", "")
 	appConfig.CloudinaryApiSecret = getCredentialWithFallback(credentials, "CLOUDINARY_API_SECRET", "")
+	appConfig.SMTPPassword = getCredentialWithFallback(credentials, "SMTP_PASSWORD", "")
 
 	// Validasi credentials yang wajib ada
 	if appConfig.PasetoSecretKey == "" {
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format file ekspor yang didukung
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Formats adalah semua format yang didukung, untuk validasi query
+var Formats = []string{FormatCSV, FormatXLSX}
+
+// Writer menulis tabel baris demi baris ke sebuah file ekspor.
+// Nilai sel boleh berupa string, int, int64, float64, atau nil untuk sel kosong.
+type Writer interface {
+	WriteRow(values []interface{}) error
+	// Close menyelesaikan file; tidak menutup io.Writer tujuan
+	Close() error
+}
+
+// ContentType mengembalikan MIME type untuk format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewWriter membuat Writer sesuai format (csv atau xlsx)
+func NewWriter(format string, w io.Writer, sheetName string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w)
+	case FormatXLSX:
+		return NewXLSXWriter(w, sheetName)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// utf8BOM membuat Excel membaca CSV sebagai UTF-8 sehingga nama dengan huruf non-ASCII tidak rusak
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter membuat Writer CSV (dipisah koma, diawali BOM UTF-8)
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = escapeFormula(v)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return fmt.Errorf("unsupported cell type %T", value)
+		}
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// escapeFormula mencegah teks isian pendaftar (misal "=HYPERLINK(...)") dijalankan sebagai
+// rumus saat CSV dibuka di spreadsheet
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
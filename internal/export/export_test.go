@@ -0,0 +1,173 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVWriterQuotesAndEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewCSVWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]interface{}{
+		{"Nama", "Catatan", "Nilai"},
+		{"Budi, S.Kom", `kata "kutip"` + "\nbaris baru", 87.5},
+		{"=HYPERLINK(\"http://contoh\")", "+62812", -3},
+		{"@SUM(A1)", "-1+1", nil},
+		{"\tTab", "biasa", int64(7)},
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), utf8BOM) {
+		t.Fatal("CSV tidak diawali BOM UTF-8")
+	}
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes()[len(utf8BOM):])).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV tidak bisa dibaca ulang: %v", err)
+	}
+	want := [][]string{
+		{"Nama", "Catatan", "Nilai"},
+		{"Budi, S.Kom", `kata "kutip"` + "\nbaris baru", "87.5"},
+		{"'=HYPERLINK(\"http://contoh\")", "'+62812", "-3"},
+		{"'@SUM(A1)", "'-1+1", ""},
+		{"'\tTab", "biasa", "7"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("jumlah baris %d, ingin %d", len(records), len(want))
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("sel [%d][%d] = %q, ingin %q", i, j, records[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestCSVWriterRejectsUnsupportedType(t *testing.T) {
+	writer, err := NewCSVWriter(io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteRow([]interface{}{true}); err == nil {
+		t.Error("tipe bool seharusnya ditolak")
+	}
+}
+
+// xlsxSheet adalah bagian sheet1.xml yang diperiksa test
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Style  string `xml:"s,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXWriterProducesValidWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewXLSXWriter(&buf, "Pendaftaran: 2026/2027")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]interface{}{
+		{"Nama", "Nilai", "Catatan"},
+		{"Budi & <Ani>", 87.5, nil},
+		{"=1+1", 3, "baris\nbaru"},
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("XLSX bukan arsip zip yang valid: %v", err)
+	}
+	parts := make(map[string][]byte)
+	for _, file := range archive.File {
+		f, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[file.Name] = content
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		content, ok := parts[name]
+		if !ok {
+			t.Fatalf("bagian %s tidak ada", name)
+		}
+		// Setiap bagian harus XML yang well-formed
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s bukan XML yang valid: %v", name, err)
+			}
+		}
+	}
+
+	if workbook := string(parts["xl/workbook.xml"]); !strings.Contains(workbook, `name="Pendaftaran  2026 2027"`) {
+		t.Errorf("nama sheet tidak dibersihkan: %s", workbook)
+	}
+
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("jumlah baris %d, ingin 3", len(sheet.Rows))
+	}
+	header := sheet.Rows[0].Cells
+	if len(header) != 3 || header[0].Style != "1" || header[0].Inline != "Nama" {
+		t.Errorf("judul kolom tidak ditulis tebal: %+v", header)
+	}
+	first := sheet.Rows[1].Cells
+	if len(first) != 2 || first[0].Ref != "A2" || first[0].Inline != "Budi & <Ani>" || first[1].Ref != "B2" || first[1].Value != "87.5" {
+		t.Errorf("baris 2 = %+v", first)
+	}
+	second := sheet.Rows[2].Cells
+	if second[0].Type != "inlineStr" || second[0].Inline != "=1+1" {
+		t.Errorf("teks berawalan = harus disimpan sebagai teks biasa, bukan rumus: %+v", second[0])
+	}
+	if second[1].Value != "3" || second[2].Inline != "baris\nbaru" {
+		t.Errorf("baris 3 = %+v", second)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	cases := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, want := range cases {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, ingin %s", index, got, want)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// WithdrawRegistrationHandler mengundurkan diri dari pendaftaran user pada periode saat ini.
+// Alasan (opsional) dicatat pada riwayat pendaftaran dan slot wawancara yang dipesan dilepas.
+func (h *Handler) WithdrawRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && err != io.EOF {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	var errs validation.Errors
+	validation.Var(&errs, "reason", "Alasan", payload.Reason, "max=1000")
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	ctx := r.Context()
+	registration, err := h.userRegistration(ctx, actor.UserID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+	if registration.Status == model.RegistrationStatusWithdrawn ||
+		!model.CanTransitionRegistration(registration.Status, model.RegistrationStatusWithdrawn) {
+		response.Error(w, r, http.StatusConflict, response.CodeRegistrationLocked,
+			"Pendaftaran sudah selesai diproses sehingga tidak bisa dibatalkan")
+		return
+	}
+
+	status := model.RegistrationStatusWithdrawn
+	now := time.Now()
+	if err := h.registrations.Update(ctx, registration.ID, repository.RegistrationUpdate{Status: &status, UpdatedAt: now}); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membatalkan pendaftaran")
+		return
+	}
+	if registration.InterviewSlotID != nil || registration.InterviewSchedule != "" || registration.InterviewLocation != "" {
+		if err := h.clearInterviewSlot(ctx, registration); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal melepas jadwal wawancara")
+			return
+		}
+	}
+
+	event := newRegistrationEvent(actor, registration.ID, model.RegistrationEventStatusChanged, primitive.NewDateTimeFromTime(now))
+	event.OldStatus = registration.Status
+	event.NewStatus = status
+	event.Note = payload.Reason
+	h.recordEvents(ctx, event)
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Pendaftaran berhasil dibatalkan", "status": status})
+}
+
+// DeleteAccountHandler menghapus akun user yang sedang login beserta semua pendaftarannya, catatan,
+// nilai wawancara, riwayat, berkas unggahan, dan sesi login. Wajib menyertakan password.
+func (h *Handler) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	var payload struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.users.FindByID(ctx, actor.UserID)
+	if err != nil {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	// Akun panitia dihapus lewat super admin agar catatan dan penilaiannya tetap terjaga
+	if user.Role != "user" {
+		response.Error(w, r, http.StatusForbidden, response.CodeAccountNotDeletable, "Akun admin tidak bisa dihapus sendiri")
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(payload.Password)); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeCurrentPasswordIncorrect, "Password tidak sesuai")
+		return
+	}
+
+	registrations, err := h.registrations.ListByUser(ctx, user.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+	// Pendaftaran dihapus lebih dulu; jika gagal, akun masih ada sehingga user bisa mencoba lagi.
+	// Berbeda dengan penghapusan oleh admin, riwayatnya ikut dihapus karena pemilik data meminta dihapus.
+	for i := range registrations {
+		if err := h.deleteRegistration(ctx, &registrations[i]); err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus data pendaftaran")
+			return
+		}
+		if err := h.events.DeleteByRegistration(ctx, registrations[i].ID); err != nil {
+			log.Printf("failed to delete history of registration %s: %v", registrations[i].ID.Hex(), err)
+		}
+	}
+	if err := h.users.Delete(ctx, user.ID); err != nil && err != repository.ErrNotFound {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus akun")
+		return
+	}
+
+	if err := h.tokens.DeleteUserRefreshTokens(ctx, user.ID); err != nil {
+		log.Printf("failed to delete sessions of user %s: %v", user.ID.Hex(), err)
+	}
+	if err := h.tokens.RevokeAccessToken(ctx, actor.TokenID, actor.ExpiresAt); err != nil {
+		log.Printf("failed to revoke access token of user %s: %v", user.ID.Hex(), err)
+	}
+	if err := h.passwordResets.DeleteByUser(ctx, user.ID); err != nil {
+		log.Printf("failed to delete password resets of user %s: %v", user.ID.Hex(), err)
+	}
+	if err := h.loginAttempts.Delete(ctx, loginNIMKey(user.NIM)); err != nil {
+		log.Printf("failed to delete login attempts of user %s: %v", user.ID.Hex(), err)
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Akun dan seluruh data pendaftaran berhasil dihapus"})
+}
+
+// userDataRegistration adalah satu pendaftaran pada arsip data user: data my-registration ditambah
+// slot wawancara yang dipesan dan penilaian wawancaranya
+type userDataRegistration struct {
+	*model.Registration
+	Timeline        []registrationTimelineEntry `json:"timeline"`
+	Notes           []applicantNote             `json:"notes"`
+	InterviewSlot   *applicantSlot              `json:"interview_slot,omitempty"`
+	InterviewScores []interviewScoreEntry       `json:"interview_scores"`
+}
+
+// ExportUserDataHandler mengunduh semua data yang tersimpan tentang user yang sedang login sebagai file JSON:
+// profil, pendaftaran dari semua periode beserta URL berkasnya, riwayat status, catatan untuk pendaftar,
+// slot wawancara yang dipesan, dan penilaian wawancara.
+func (h *Handler) ExportUserDataHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.users.FindByID(ctx, actor.UserID)
+	if err != nil {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	user.Password = ""
+
+	registrations, err := h.registrations.ListByUser(ctx, user.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+	registrationIDs := make([]primitive.ObjectID, len(registrations))
+	for i := range registrations {
+		registrationIDs[i] = registrations[i].ID
+	}
+	scores, err := h.scores.ListByRegistrations(ctx, registrationIDs)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil penilaian wawancara")
+		return
+	}
+	userName := h.userNames(ctx)
+	scoresByRegistration := make(map[primitive.ObjectID][]interviewScoreEntry)
+	for _, score := range scores {
+		scoresByRegistration[score.RegistrationID] = append(scoresByRegistration[score.RegistrationID],
+			interviewScoreEntry{InterviewScore: score, InterviewerName: userName(score.InterviewerID)})
+	}
+
+	archive := make([]userDataRegistration, len(registrations))
+	for i := range registrations {
+		events, err := h.events.ListByRegistration(ctx, registrations[i].ID)
+		if err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil riwayat pendaftaran")
+			return
+		}
+		notes, err := h.notes.ListByRegistration(ctx, registrations[i].ID)
+		if err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil catatan pendaftaran")
+			return
+		}
+		archive[i] = userDataRegistration{
+			Registration:    &registrations[i],
+			Timeline:        registrationTimeline(events),
+			Notes:           applicantNotes(notes),
+			InterviewScores: scoresByRegistration[registrations[i].ID],
+		}
+		if archive[i].InterviewScores == nil {
+			archive[i].InterviewScores = []interviewScoreEntry{}
+		}
+
+		if slotID := registrations[i].InterviewSlotID; slotID != nil {
+			slot, err := h.interviewSlots.FindByID(ctx, *slotID)
+			if err != nil && err != repository.ErrNotFound {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+				return
+			}
+			if slot != nil {
+				booked := newApplicantSlot(slot)
+				archive[i].InterviewSlot = &booked
+			}
+		}
+	}
+
+	now := time.Now()
+	filename := "data-" + user.NIM + "-" + now.In(localTimeZone).Format("20060102") + ".json"
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"exported_at":   primitive.NewDateTimeFromTime(now),
+		"user":          user,
+		"registrations": archive,
+	})
+}
+
+// deleteRegistration menghapus pendaftaran beserta catatan, nilai wawancara, kursi slot wawancara,
+// dan berkas unggahannya. Riwayat tidak ikut dihapus; hanya penghapusan akun yang menghapusnya.
+// Hanya kegagalan menghapus pendaftaran yang dikembalikan; kegagalan membersihkan data turunannya dicatat ke log.
+func (h *Handler) deleteRegistration(ctx context.Context, registration *model.Registration) error {
+	if err := h.registrations.Delete(ctx, registration.ID); err != nil {
+		return err
+	}
+
+	regID := registration.ID
+	if err := h.notes.DeleteByRegistration(ctx, regID); err != nil {
+		log.Printf("failed to delete notes of registration %s: %v", regID.Hex(), err)
+	}
+	if err := h.scores.DeleteByRegistration(ctx, regID); err != nil {
+		log.Printf("failed to delete interview scores of registration %s: %v", regID.Hex(), err)
+	}
+	if registration.InterviewSlotID != nil {
+		if err := h.interviewSlots.Release(ctx, *registration.InterviewSlotID, regID, time.Now()); err != nil {
+			log.Printf("failed to release interview slot of registration %s: %v", regID.Hex(), err)
+		}
+	}
+	for _, spec := range registrationFiles {
+		if url := *spec.url(registration); url != "" {
+			if err := h.storage.Delete(ctx, url); err != nil {
+				log.Printf("failed to delete %s of registration %s (%s): %v", spec.field, regID.Hex(), url, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeleteRegistrationHandlerKeepsHistory(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	admin := env.createUser(t, "900001", "admin")
+	user := env.createUser(t, "714220001", "user")
+	reg := env.submitRegistration(t, user)
+
+	path := "/api/admin/registrations/" + reg.ID.Hex()
+	rec := env.serve(t, "DELETE", "/api/admin/registrations/{id}", path, env.h.DeleteRegistrationHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	ctx := context.Background()
+	if _, err := env.repos.Registrations.FindByID(ctx, reg.ID); err != repository.ErrNotFound {
+		t.Errorf("pendaftaran masih ada: %v", err)
+	}
+	if env.stored(reg.CvUrl) {
+		t.Error("berkas pendaftaran tidak dihapus")
+	}
+
+	events, _ := env.repos.Events.ListByRegistration(ctx, reg.ID)
+	if len(events) != 2 || events[0].Type != model.RegistrationEventSubmitted {
+		t.Fatalf("riwayat %+v, ingin submitted lalu deleted", events)
+	}
+	deleted := events[1]
+	if deleted.Type != model.RegistrationEventDeleted || deleted.ActorID != admin.ID || deleted.OldStatus != model.RegistrationStatusPending {
+		t.Errorf("event penghapusan tidak sesuai: %+v", deleted)
+	}
+
+	// Riwayat tetap bisa dibuka walaupun pendaftarannya sudah tidak ada
+	rec = env.serve(t, "GET", "/api/admin/registrations/{id}/history", path+"/history", env.h.GetRegistrationHistoryHandler, admin, nil)
+	var history []registrationHistoryEntry
+	decode(t, rec, &history)
+	if rec.Code != http.StatusOK || len(history) != 2 {
+		t.Errorf("riwayat setelah dihapus: status %d, %d event", rec.Code, len(history))
+	}
+}
+
+func TestDeleteAccountHandlerPurgesHistory(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+	reg := env.submitRegistration(t, user)
+
+	rec := env.serve(t, "DELETE", "/api/user/account", "/api/user/account", env.h.DeleteAccountHandler, user,
+		map[string]string{"password": testPassword})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	ctx := context.Background()
+	if _, err := env.repos.Users.FindByID(ctx, user.ID); err != repository.ErrNotFound {
+		t.Errorf("akun masih ada: %v", err)
+	}
+	if events, _ := env.repos.Events.ListByRegistration(ctx, reg.ID); len(events) != 0 {
+		t.Errorf("riwayat masih tersimpan setelah akun dihapus: %+v", events)
+	}
+	if env.stored(reg.CvUrl) {
+		t.Error("berkas pendaftaran tidak dihapus")
+	}
+}
+
+func TestExportUserDataHandlerIncludesInterview(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	interviewer := env.createUser(t, "900001", "admin")
+	user := env.createUser(t, "714220001", "user")
+	reg := env.submitRegistration(t, user)
+
+	ctx := context.Background()
+	startsAt := time.Now().Add(24 * time.Hour)
+	slots := []model.InterviewSlot{{
+		PeriodID: period.ID, StartsAt: primitive.NewDateTimeFromTime(startsAt), EndsAt: primitive.NewDateTimeFromTime(startsAt.Add(30 * time.Minute)),
+		Location: "Ruang 101", Interviewers: []primitive.ObjectID{interviewer.ID}, Capacity: 2,
+	}}
+	if err := env.repos.InterviewSlots.CreateMany(ctx, slots); err != nil {
+		t.Fatal(err)
+	}
+	slotID := slots[0].ID
+	if err := env.repos.InterviewSlots.Book(ctx, slotID, reg.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repos.Registrations.SetInterviewSlot(ctx, reg.ID, &slotID, "besok", "Ruang 101", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	score := &model.InterviewScore{RegistrationID: reg.ID, InterviewerID: interviewer.ID, Division: "Web", Total: 87.5, Comment: "Komunikatif"}
+	if err := env.repos.Scores.Upsert(ctx, score); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := env.serve(t, "GET", "/api/user/data-export", "/api/user/data-export", env.h.ExportUserDataHandler, user, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var archive struct {
+		Registrations []struct {
+			InterviewSlot *struct {
+				ID       primitive.ObjectID `json:"id"`
+				Location string             `json:"location"`
+			} `json:"interview_slot"`
+			InterviewScores []struct {
+				Division        string  `json:"division"`
+				Total           float64 `json:"total"`
+				Comment         string  `json:"comment"`
+				InterviewerName string  `json:"interviewer_name"`
+			} `json:"interview_scores"`
+		} `json:"registrations"`
+	}
+	decode(t, rec, &archive)
+	if len(archive.Registrations) != 1 {
+		t.Fatalf("jumlah pendaftaran %d, ingin 1", len(archive.Registrations))
+	}
+	exported := archive.Registrations[0]
+	if exported.InterviewSlot == nil || exported.InterviewSlot.ID != slotID || exported.InterviewSlot.Location != "Ruang 101" {
+		t.Errorf("slot wawancara tidak ikut diekspor: %+v", exported.InterviewSlot)
+	}
+	if len(exported.InterviewScores) != 1 || exported.InterviewScores[0].Total != 87.5 ||
+		exported.InterviewScores[0].Comment != "Komunikatif" || exported.InterviewScores[0].InterviewerName != interviewer.Name {
+		t.Errorf("penilaian wawancara tidak ikut diekspor: %+v", exported.InterviewScores)
+	}
+}
@@ -2,326 +2,706 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/ulbithebest/BE-pendaftaran/internal/config"
-	"github.com/ulbithebest/BE-pendaftaran/internal/model" // <-- PERBAIKAN 1: Tambahkan import model
+	"github.com/ulbithebest/BE-pendaftaran/internal/applog"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
 	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func GetAllRegistrationsDetailHandler(w http.ResponseWriter, r *http.Request) {
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("registrations")
-
-	pipeline := mongo.Pipeline{
-		bson.D{{Key: "$lookup", Value: bson.D{
-			{Key: "from", Value: "users"}, {Key: "localField", Value: "user_id"},
-			{Key: "foreignField", Value: "_id"}, {Key: "as", Value: "userDetails"},
-		}}},
-		bson.D{{Key: "$unwind", Value: "$userDetails"}},
-		bson.D{{Key: "$project", Value: bson.D{
-			{Key: "_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "division1", Value: 1},
-			{Key: "division2", Value: 1}, {Key: "motivation", Value: 1}, {Key: "vision_mission", Value: 1},
-			{Key: "interview_schedule", Value: 1}, {Key: "interview_location", Value: 1},
-			{Key: "cv_url", Value: 1}, {Key: "certificate_url", Value: 1}, {Key: "optional_certificate_url", Value: 1}, {Key: "formal_photo_url", Value: 1}, {Key: "status", Value: 1},
-			{Key: "note", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "name", Value: "$userDetails.name"},
-			{Key: "nim", Value: "$userDetails.nim"},
-		}}},
-	}
-
-	cursor, err := collection.Aggregate(context.TODO(), pipeline)
-	if err != nil {
-		http.Error(w, `{"error": "Failed to fetch registrations"}`, http.StatusInternalServerError)
+// Batas ukuran halaman daftar pendaftar
+const (
+	defaultRegistrationsPerPage = 50
+	maxRegistrationsPerPage     = 200
+)
+
+// pagination adalah keterangan halaman pada respons daftar
+type pagination struct {
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// GetAllRegistrationsDetailHandler mengembalikan satu halaman pendaftar sesuai filter pada query
+// (lihat registrationFilter) beserta jumlah seluruh pendaftar yang cocok
+func (h *Handler) GetAllRegistrationsDetailHandler(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.registrationFilter(w, r)
+	if !ok {
 		return
 	}
-	defer cursor.Close(context.TODO())
 
-	var results []model.RegistrationDetail
-	if err = cursor.All(context.TODO(), &results); err != nil {
-		http.Error(w, `{"error": "Failed to decode registrations"}`, http.StatusInternalServerError)
+	query := r.URL.Query()
+	var errs validation.Errors
+	filter.Page = queryInt(&errs, query.Get("page"), "page", "Halaman", 1, 1, 0)
+	filter.PerPage = queryInt(&errs, query.Get("per_page"), "per_page", "Jumlah per halaman", defaultRegistrationsPerPage, 1, maxRegistrationsPerPage)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
 		return
 	}
-	if results == nil {
-		results = []model.RegistrationDetail{}
+
+	results, total, err := h.registrations.ListDetails(r.Context(), filter)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": results,
+		"pagination": pagination{
+			Page:       filter.Page,
+			PerPage:    filter.PerPage,
+			Total:      total,
+			TotalPages: (total + int64(filter.PerPage) - 1) / int64(filter.PerPage),
+		},
+	})
 }
 
-func UpdateRegistrationDetailsHandler(w http.ResponseWriter, r *http.Request) {
+// registrationFilter membaca filter dan urutan daftar pendaftar dari query:
+//   - period: kosong berarti periode saat ini, "all" untuk semua periode, atau ID periode
+//   - status: satu atau beberapa status dipisah koma
+//   - division: nama divisi pada pilihan 1 maupun 2
+//   - q: sebagian nama atau NIM
+//   - submitted_from, submitted_to: tanggal kirim (YYYY-MM-DD dalam WIB, inklusif) atau waktu RFC 3339
+//   - sort: submitted (default), updated_at, name, nim, status, atau score; order: desc (default) atau asc
+//
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) registrationFilter(w http.ResponseWriter, r *http.Request) (repository.RegistrationFilter, bool) {
+	var filter repository.RegistrationFilter
+	periodID, ok := h.periodFilter(w, r)
+	if !ok {
+		return filter, false
+	}
+	filter.PeriodID = periodID
+
+	query := r.URL.Query()
+	var errs validation.Errors
+
+	if value := strings.TrimSpace(query.Get("status")); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if _, ok := model.FindRegistrationStatus(status); !ok {
+				errs.Add("status", validation.CodeInvalidOption, "Status "+status+" tidak dikenal")
+				continue
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if name := strings.TrimSpace(query.Get("division")); name != "" {
+		// Pakai penulisan nama dari katalog karena pilihan pada pendaftaran disimpan persis seperti itu
+		filter.Division = name
+		division, err := h.divisions.FindByName(r.Context(), name)
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+			return filter, false
+		}
+		if err == nil {
+			filter.Division = division.Name
+		}
+	}
+
+	filter.Search = strings.TrimSpace(query.Get("q"))
+	validation.Var(&errs, "q", "Kata kunci", filter.Search, "max=100")
+
+	filter.SubmittedFrom = queryTime(&errs, query.Get("submitted_from"), "submitted_from", false)
+	filter.SubmittedBefore = queryTime(&errs, query.Get("submitted_to"), "submitted_to", true)
+	if !filter.SubmittedFrom.IsZero() && !filter.SubmittedBefore.IsZero() && !filter.SubmittedBefore.After(filter.SubmittedFrom) {
+		errs.Add("submitted_to", "INVALID_RANGE", "Tanggal akhir harus setelah tanggal awal")
+	}
+
+	filter.Sort = query.Get("sort")
+	if filter.Sort == "" {
+		filter.Sort = repository.RegistrationSortSubmitted
+	}
+	validation.Var(&errs, "sort", "Urutan", filter.Sort, "oneof="+strings.Join(repository.RegistrationSorts, " "))
+	switch query.Get("order") {
+	case "", "desc":
+		filter.Descending = true
+	case "asc":
+	default:
+		errs.Add("order", validation.CodeInvalidOption, "Arah urutan harus asc atau desc")
+	}
+
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return filter, false
+	}
+	return filter, true
+}
+
+// queryInt membaca bilangan bulat dari query; kosong berarti defaultValue dan max 0 berarti tanpa batas atas
+func queryInt(errs *validation.Errors, value, field, label string, defaultValue, min, max int) int {
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil:
+		errs.Add(field, "INVALID_NUMBER", label+" harus berupa angka")
+	case n < min:
+		errs.Add(field, validation.CodeMinValue, fmt.Sprintf("%s tidak boleh kurang dari %d", label, min))
+	case max > 0 && n > max:
+		errs.Add(field, validation.CodeMaxValue, fmt.Sprintf("%s tidak boleh lebih dari %d", label, max))
+	}
+	return n
+}
+
+// queryTime membaca waktu RFC 3339 atau tanggal YYYY-MM-DD (WIB) dari query.
+// Untuk batas akhir (end), tanggal berarti sampai akhir hari tersebut.
+func queryTime(errs *validation.Errors, value, field string, end bool) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		if end {
+			// ID pendaftaran menyimpan waktu dalam detik, jadi detik batas akhir ikut disertakan
+			return t.Truncate(time.Second).Add(time.Second)
+		}
+		return t
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, localTimeZone)
+	if err != nil {
+		errs.Add(field, validation.CodeInvalidDate, "Tanggal harus berformat YYYY-MM-DD atau RFC 3339")
+		return time.Time{}
+	}
+	if end {
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// UpdateRegistrationDetailsHandler memperbarui status dan jadwal wawancara satu pendaftaran.
+// Perubahan status harus mengikuti alur pada model.RegistrationStatuses.
+func (h *Handler) UpdateRegistrationDetailsHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
 	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Invalid registration ID"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
 		return
 	}
 
 	var payload model.Registration
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("registrations")
-	updateFields := bson.M{}
+	ctx := r.Context()
+	existing, err := h.registrations.FindByID(ctx, regID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+
+	update := repository.RegistrationUpdate{UpdatedAt: time.Now()}
 
+	status := existing.Status
 	if payload.Status != "" {
-		updateFields["status"] = payload.Status
+		if !validateRegistrationStatus(w, r, payload.Status) {
+			return
+		}
+		if !model.CanTransitionRegistration(existing.Status, payload.Status) {
+			writeInvalidTransition(w, r, existing.Status, payload.Status)
+			return
+		}
+		status = payload.Status
+		update.Status = &payload.Status
 	}
 
-	if payload.Status == "interview" {
-		updateFields["interview_schedule"] = payload.InterviewSchedule
-		updateFields["interview_location"] = payload.InterviewLocation
-	} else {
-		updateFields["interview_schedule"] = ""
-		updateFields["interview_location"] = ""
+	// Jadwal teks bebas hanya diisi selama tahap wawancara dan pendaftaran belum memesan slot;
+	// jadwal dari slot dikelola lewat endpoint slot wawancara.
+	schedule, location := existing.InterviewSchedule, existing.InterviewLocation
+	if status == model.RegistrationStatusInterview && existing.InterviewSlotID == nil {
+		schedule, location = strings.TrimSpace(payload.InterviewSchedule), strings.TrimSpace(payload.InterviewLocation)
+		update.InterviewSchedule = &schedule
+		update.InterviewLocation = &location
 	}
 
-	updateFields["updated_at"] = primitive.NewDateTimeFromTime(time.Now())
-
-	update := bson.M{"$set": updateFields}
-
-	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": regID}, update)
+	err = h.registrations.Update(ctx, regID, update)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
 	if err != nil {
-		http.Error(w, `{"error": "Failed to update registration"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui pendaftaran")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Registration updated successfully"})
+	// Keluar dari tahap wawancara melepas slot dan jadwalnya; status akhir tetap menyimpan jadwal sebagai arsip
+	if status != existing.Status && releasesInterview(status) && (existing.InterviewSlotID != nil || schedule != "" || location != "") {
+		if err := h.clearInterviewSlot(ctx, existing); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal melepas jadwal wawancara")
+			return
+		}
+		schedule, location = "", ""
+	}
+
+	// Catat ke riwayat hanya jika status atau jadwal wawancara benar-benar berubah
+	event := newRegistrationEvent(actor, regID, model.RegistrationEventStatusChanged, primitive.NewDateTimeFromTime(update.UpdatedAt))
+	event.NewStatus = status
+	event.Note = strings.TrimSpace(payload.Note)
+	event.InterviewSchedule = schedule
+	event.InterviewLocation = location
+	switch {
+	case status != existing.Status:
+		event.OldStatus = existing.Status
+		h.recordEvents(ctx, event)
+	case event.InterviewSchedule != existing.InterviewSchedule || event.InterviewLocation != existing.InterviewLocation:
+		event.Type = model.RegistrationEventInterviewChanged
+		h.recordEvents(ctx, event)
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Registration updated successfully"})
 }
 
-// FITUR BARU: Handler untuk update status beberapa pendaftar sekaligus
-func BulkUpdateStatusHandler(w http.ResponseWriter, r *http.Request) {
+// BulkUpdateStatusHandler mengubah status beberapa pendaftar sekaligus. Jika ada ID yang tidak
+// ditemukan atau satu saja perubahan yang tidak sesuai alur status, tidak ada pendaftaran yang diubah.
+// Pendaftaran yang statusnya diubah admin lain di tengah proses dilewati dan dilaporkan dengan 409.
+func (h *Handler) BulkUpdateStatusHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
 	var payload struct {
 		IDs    []string `json:"ids"`
 		Status string   `json:"status"`
+		Note   string   `json:"note"` // dicatat pada riwayat setiap pendaftaran yang berubah
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
 	if len(payload.IDs) == 0 {
-		http.Error(w, `{"error": "No registration IDs provided"}`, http.StatusBadRequest)
+		var errs validation.Errors
+		errs.Add("ids", validation.CodeRequired, "Pilih minimal satu pendaftaran")
+		writeValidationErrors(w, r, errs)
+		return
+	}
+	if !validateRegistrationStatus(w, r, payload.Status) {
 		return
 	}
 
 	// Konversi string IDs menjadi BSON ObjectIDs
 	var objectIDs []primitive.ObjectID
+	requested := make(map[primitive.ObjectID]bool, len(payload.IDs))
 	for _, idStr := range payload.IDs {
 		id, err := primitive.ObjectIDFromHex(idStr)
 		if err != nil {
-			http.Error(w, `{"error": "Invalid ID format in list"}`, http.StatusBadRequest)
+			response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "Format ID pada daftar tidak valid")
 			return
 		}
-		objectIDs = append(objectIDs, id)
+		if !requested[id] {
+			requested[id] = true
+			objectIDs = append(objectIDs, id)
+		}
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("registrations")
+	ctx := r.Context()
+	registrations, err := h.registrations.FindByIDs(ctx, objectIDs)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
 
-	// Filter untuk mencari semua dokumen dengan ID yang ada di dalam array
-	filter := bson.M{"_id": bson.M{"$in": objectIDs}}
+	found := make(map[primitive.ObjectID]bool, len(registrations))
+	for _, reg := range registrations {
+		found[reg.ID] = true
+	}
+	var missing []primitive.ObjectID
+	for _, id := range objectIDs {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		response.ErrorWithDetails(w, r, http.StatusNotFound, response.CodeRegistrationNotFound,
+			fmt.Sprintf("%d pendaftaran tidak ditemukan", len(missing)),
+			map[string]interface{}{"ids": missing})
+		return
+	}
 
-	// Data yang akan di-update
-	update := bson.M{
-		"$set": bson.M{
-			"status":     payload.Status,
-			"updated_at": primitive.NewDateTimeFromTime(time.Now()),
-		},
+	type rejectedTransition struct {
+		ID   primitive.ObjectID `json:"id"`
+		From string             `json:"from"`
+		To   string             `json:"to"`
+	}
+	var rejected []rejectedTransition
+	for _, reg := range registrations {
+		if !model.CanTransitionRegistration(reg.Status, payload.Status) {
+			rejected = append(rejected, rejectedTransition{ID: reg.ID, From: reg.Status, To: payload.Status})
+		}
+	}
+	if len(rejected) > 0 {
+		response.ErrorWithDetails(w, r, http.StatusBadRequest, response.CodeInvalidTransition,
+			fmt.Sprintf("%d pendaftaran tidak bisa diubah ke status %s", len(rejected), payload.Status),
+			map[string]interface{}{"registrations": rejected})
+		return
+	}
+
+	// Hanya pendaftaran yang masih berada pada status yang sudah diperiksa di atas yang diubah.
+	// Pendaftaran yang sudah berstatus tujuan tidak perlu diubah.
+	var pending []primitive.ObjectID
+	var allowedFrom []string
+	seenStatus := make(map[string]bool)
+	for _, reg := range registrations {
+		if reg.Status == payload.Status {
+			continue
+		}
+		pending = append(pending, reg.ID)
+		if !seenStatus[reg.Status] {
+			seenStatus[reg.Status] = true
+			allowedFrom = append(allowedFrom, reg.Status)
+		}
+	}
+
+	now := time.Now()
+	var updated []model.Registration
+	if len(pending) > 0 {
+		updated, err = h.registrations.BulkUpdateStatus(ctx, pending, allowedFrom, payload.Status, now)
+		if err != nil && len(updated) == 0 {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui status pendaftaran")
+			return
+		}
+	}
+
+	// Riwayat memakai status lama yang dikembalikan repository, bukan data yang dibaca di awal
+	var events []model.RegistrationEvent
+	updatedIDs := make([]primitive.ObjectID, 0, len(updated))
+	updatedSet := make(map[primitive.ObjectID]bool, len(updated))
+	for i := range updated {
+		reg := &updated[i]
+		updatedIDs = append(updatedIDs, reg.ID)
+		updatedSet[reg.ID] = true
+		if releasesInterview(payload.Status) && (reg.InterviewSlotID != nil || reg.InterviewSchedule != "" || reg.InterviewLocation != "") {
+			if err := h.clearInterviewSlot(ctx, reg); err != nil {
+				log.Printf("failed to release interview of registration %s: %v", reg.ID.Hex(), err)
+			}
+		}
+		event := newRegistrationEvent(actor, reg.ID, model.RegistrationEventStatusChanged, primitive.NewDateTimeFromTime(now))
+		event.OldStatus = reg.Status
+		event.NewStatus = payload.Status
+		event.Note = strings.TrimSpace(payload.Note)
+		events = append(events, event)
 	}
+	h.recordEvents(ctx, events...)
 
-	// Lakukan operasi UpdateMany
-	result, err := collection.UpdateMany(context.TODO(), filter, update)
 	if err != nil {
-		http.Error(w, `{"error": "Failed to bulk update registrations"}`, http.StatusInternalServerError)
+		log.Printf("bulk status update stopped after %d registrations: %v", len(updated), err)
+	}
+	var notUpdated []primitive.ObjectID
+	for _, id := range pending {
+		if !updatedSet[id] {
+			notUpdated = append(notUpdated, id)
+		}
+	}
+	if len(notUpdated) > 0 {
+		response.ErrorWithDetails(w, r, http.StatusConflict, response.CodeStatusChanged,
+			fmt.Sprintf("%d pendaftaran berhasil diperbarui, %d tidak diperbarui karena statusnya berubah atau gagal disimpan. Muat ulang data lalu coba lagi.",
+				len(updated), len(notUpdated)),
+			map[string]interface{}{"updated": updatedIDs, "not_updated": notUpdated})
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	response.JSON(w, http.StatusOK, map[string]interface{}{
 		"message":      "Bulk update successful",
-		"updatedCount": result.ModifiedCount,
+		"updatedCount": len(updated),
 	})
 }
 
-func GetAllUsersHandler(w http.ResponseWriter, r *http.Request) {
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("users")
-
-	// Opsi untuk tidak menyertakan field password demi keamanan
-	opts := options.Find().SetProjection(bson.M{"password": 0})
+// GetRegistrationStatusesHandler menjelaskan alur status pendaftaran untuk UI admin
+func (h *Handler) GetRegistrationStatusesHandler(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"initial":  model.RegistrationStatusPending,
+		"statuses": model.RegistrationStatuses,
+	})
+}
 
-	cursor, err := collection.Find(context.TODO(), bson.D{}, opts)
-	if err != nil {
-		http.Error(w, `{"error": "Failed to fetch users"}`, http.StatusInternalServerError)
-		return
+// validateRegistrationStatus memastikan status termasuk alur pendaftaran.
+// Mengembalikan false jika respons error sudah dikirim.
+func validateRegistrationStatus(w http.ResponseWriter, r *http.Request, status string) bool {
+	var errs validation.Errors
+	validation.Var(&errs, "status", "Status", status, "required,oneof="+strings.Join(model.RegistrationStatusValues(), " "))
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return false
 	}
-	defer cursor.Close(context.TODO())
+	return true
+}
 
-	var users []model.User
-	if err = cursor.All(context.TODO(), &users); err != nil {
-		http.Error(w, `{"error": "Failed to decode users"}`, http.StatusInternalServerError)
-		return
+func writeInvalidTransition(w http.ResponseWriter, r *http.Request, from, to string) {
+	allowed := []string{}
+	if status, ok := model.FindRegistrationStatus(from); ok {
+		allowed = status.Next
 	}
+	response.ErrorWithDetails(w, r, http.StatusBadRequest, response.CodeInvalidTransition,
+		fmt.Sprintf("Status tidak bisa diubah dari %s ke %s", from, to),
+		map[string]interface{}{"from": from, "to": to, "allowed": allowed})
+}
 
-	if users == nil {
-		users = []model.User{}
+func (h *Handler) GetAllUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// Repository tidak menyertakan field password demi keamanan
+	users, err := h.users.List(r.Context())
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data user")
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(users)
+	response.JSON(w, http.StatusOK, users)
 }
 
-func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	userID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Invalid user ID"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID user tidak valid")
 		return
 	}
 
 	var payload model.User
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
+	trimUserFields(&payload)
 
-	if payload.Name == "" || payload.NIM == "" || payload.Email == "" || payload.PhoneNumber == "" {
-		http.Error(w, `{"error": "Nama, NIM, email, dan nomor telepon wajib diisi"}`, http.StatusBadRequest)
+	if errs := validation.Struct(&payload); len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
 		return
 	}
 
-	if len(payload.PhoneNumber) < 10 || len(payload.PhoneNumber) > 13 {
-		http.Error(w, `{"error": "Nomor telepon harus antara 10 hingga 13 digit."}`, http.StatusBadRequest)
+	exists, err := h.users.ExistsByNIM(r.Context(), payload.NIM, userID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa NIM")
+		return
+	}
+	if exists {
+		response.Error(w, r, http.StatusConflict, response.CodeNIMAlreadyRegistered, "NIM sudah terdaftar")
 		return
 	}
 
-	if payload.Role != "user" && payload.Role != "admin" && payload.Role != "super_admin" {
-		http.Error(w, `{"error": "Role tidak valid"}`, http.StatusBadRequest)
+	existingUser, err := h.users.FindByID(r.Context(), userID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui user")
 		return
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("users")
+	payload.ID = userID
+	err = h.users.Update(r.Context(), &payload)
+	if err == repository.ErrDuplicate {
+		response.Error(w, r, http.StatusConflict, response.CodeNIMAlreadyRegistered, "NIM sudah terdaftar")
+		return
+	}
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui user")
+		return
+	}
 
-	var existingUser model.User
-	err = collection.FindOne(context.TODO(), bson.M{
-		"nim": payload.NIM,
-		"_id": bson.M{"$ne": userID},
-	}).Decode(&existingUser)
-	if err != nil && err != mongo.ErrNoDocuments {
-		http.Error(w, `{"error": "Failed to validate NIM"}`, http.StatusInternalServerError)
+	// Email baru harus diverifikasi ulang
+	if !strings.EqualFold(existingUser.Email, payload.Email) {
+		if err := h.users.SetEmailVerified(r.Context(), userID, nil); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui user")
+			return
+		}
+	}
+
+	// Role dan NIM ikut tertanam di token, jadi token lama harus langsung tidak berlaku
+	if existingUser.Role != payload.Role || existingUser.NIM != payload.NIM {
+		if err := h.users.IncrementTokenVersion(r.Context(), userID); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengakhiri sesi user")
+			return
+		}
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "User updated successfully"})
+}
+
+func (h *Handler) ResetUserPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID user tidak valid")
 		return
 	}
-	if err == nil {
-		http.Error(w, `{"error": "NIM already registered"}`, http.StatusConflict)
+
+	var payload struct {
+		NewPassword string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"name":         payload.Name,
-			"nim":          payload.NIM,
-			"birth_place":  payload.BirthPlace,
-			"birth_date":   payload.BirthDate,
-			"email":        payload.Email,
-			"phone_number": payload.PhoneNumber,
-			"role":         payload.Role,
-		},
+	var errs validation.Errors
+	validatePassword(&errs, "new_password", "Password baru", payload.NewPassword)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
 	}
 
-	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": userID}, update)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		http.Error(w, `{"error": "Failed to update user"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memproses password")
 		return
 	}
-	if result.MatchedCount == 0 {
-		http.Error(w, `{"error": "User not found"}`, http.StatusNotFound)
+
+	err = h.users.UpdatePassword(r.Context(), userID, string(hashedPassword))
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mereset password")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "User updated successfully"})
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Password user berhasil diperbarui"})
 }
 
-func ResetUserPasswordHandler(w http.ResponseWriter, r *http.Request) {
+// SetUserDisabledHandler menonaktifkan atau mengaktifkan kembali akun user (Super admin only).
+// Sesi user yang dinonaktifkan langsung berakhir pada request berikutnya.
+func (h *Handler) SetUserDisabledHandler(w http.ResponseWriter, r *http.Request) {
 	userID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Invalid user ID"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID user tidak valid")
 		return
 	}
 
 	var payload struct {
-		NewPassword string `json:"new_password"`
+		Disabled *bool `json:"disabled"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Disabled == nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
-	if len(payload.NewPassword) < 6 {
-		http.Error(w, `{"error": "Password baru minimal 6 karakter"}`, http.StatusBadRequest)
+	err = h.users.SetDisabled(r.Context(), userID, *payload.Disabled)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui status akun user")
 		return
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
+	message := "Akun user berhasil diaktifkan"
+	if *payload.Disabled {
+		message = "Akun user berhasil dinonaktifkan"
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// UnlockUserLoginHandler membuka kunci login user yang terkena lockout (Super admin only)
+func (h *Handler) UnlockUserLoginHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Failed to hash password"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID user tidak valid")
 		return
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("users")
-	result, err := collection.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{
-		"$set": bson.M{
-			"password": string(hashedPassword),
-		},
-	})
+	user, err := h.users.FindByID(r.Context(), userID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
 	if err != nil {
-		http.Error(w, `{"error": "Failed to reset password"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuka kunci login user")
 		return
 	}
-	if result.MatchedCount == 0 {
-		http.Error(w, `{"error": "User not found"}`, http.StatusNotFound)
+
+	if err := h.resetLoginFailures(r.Context(), user.NIM); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuka kunci login user")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Password user berhasil diperbarui"})
+	if payload, ok := middleware.GetPayloadFromContext(r.Context()); ok {
+		applog.Add(applog.Entry{
+			Timestamp: time.Now(),
+			Level:     "info",
+			Message:   fmt.Sprintf("Login unlock: nim:%s dibuka oleh %s", user.NIM, payload.NIM),
+			UserID:    payload.UserID.Hex(),
+			UserNIM:   payload.NIM,
+			UserRole:  payload.Role,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Kunci login user berhasil dibuka"})
 }
 
 // DeleteRegistrationHandler menghapus data pendaftaran berdasarkan ID
-func DeleteRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
 	// Mengambil ID dari parameter URL
 	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Invalid registration ID"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
 		return
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("registrations")
-
-	// Menghapus satu dokumen yang cocok dengan ID
-	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": regID})
+	ctx := r.Context()
+	registration, err := h.registrations.FindByID(ctx, regID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
 	if err != nil {
-		http.Error(w, `{"error": "Failed to delete registration"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
 		return
 	}
 
+	// Menghapus pendaftaran beserta data turunan dan berkasnya; riwayatnya tetap disimpan
+	err = h.deleteRegistration(ctx, registration)
 	// Jika tidak ada dokumen yang terhapus (mungkin ID tidak ditemukan)
-	if result.DeletedCount == 0 {
-		http.Error(w, `{"error": "Registration not found"}`, http.StatusNotFound)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus pendaftaran")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Registration deleted successfully"})
-}
-
+	event := newRegistrationEvent(actor, registration.ID, model.RegistrationEventDeleted, primitive.NewDateTimeFromTime(time.Now()))
+	event.OldStatus = registration.Status
+	h.recordEvents(ctx, event)
 
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Registration deleted successfully"})
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const bulkUpdatePath = "/api/admin/registrations/bulk-update"
+
+func (e *testEnv) bulkUpdate(t *testing.T, actor *model.User, ids []primitive.ObjectID, status string) (int, response.Code, map[string]interface{}) {
+	t.Helper()
+	hexIDs := make([]string, len(ids))
+	for i, id := range ids {
+		hexIDs[i] = id.Hex()
+	}
+	rec := e.serve(t, "PATCH", bulkUpdatePath, bulkUpdatePath, e.h.BulkUpdateStatusHandler, actor,
+		map[string]interface{}{"ids": hexIDs, "status": status, "note": "rapat panitia"})
+	var body map[string]interface{}
+	decode(t, rec, &body)
+	var code response.Code
+	if errBody, ok := body["error"].(map[string]interface{}); ok {
+		code = response.Code(errBody["code"].(string))
+	}
+	return rec.Code, code, body
+}
+
+func (e *testEnv) status(t *testing.T, id primitive.ObjectID) string {
+	t.Helper()
+	registration, err := e.repos.Registrations.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registration.Status
+}
+
+func TestBulkUpdateStatusHandler(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	first := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+	second := env.createRegistration(t, env.createUser(t, "714220002", "user"), model.RegistrationStatusPending)
+
+	status, _, body := env.bulkUpdate(t, admin, []primitive.ObjectID{first.ID, second.ID}, model.RegistrationStatusDocumentReview)
+	if status != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %v", status, body)
+	}
+	if body["updatedCount"] != float64(2) {
+		t.Errorf("updatedCount %v, ingin 2", body["updatedCount"])
+	}
+
+	for _, id := range []primitive.ObjectID{first.ID, second.ID} {
+		if got := env.status(t, id); got != model.RegistrationStatusDocumentReview {
+			t.Errorf("status %s = %s, ingin %s", id.Hex(), got, model.RegistrationStatusDocumentReview)
+		}
+		events, _ := env.repos.Events.ListByRegistration(context.Background(), id)
+		if len(events) != 1 || events[0].OldStatus != model.RegistrationStatusPending ||
+			events[0].NewStatus != model.RegistrationStatusDocumentReview || events[0].ActorID != admin.ID {
+			t.Errorf("riwayat %s tidak tercatat dengan benar: %+v", id.Hex(), events)
+		}
+	}
+}
+
+func TestBulkUpdateStatusHandlerInvalidTransition(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	pending := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+	rejected := env.createRegistration(t, env.createUser(t, "714220002", "user"), model.RegistrationStatusRejected)
+
+	// rejected adalah status akhir, sehingga tidak ada yang boleh berubah
+	status, code, body := env.bulkUpdate(t, admin, []primitive.ObjectID{pending.ID, rejected.ID}, model.RegistrationStatusDocumentReview)
+	if status != http.StatusBadRequest || code != response.CodeInvalidTransition {
+		t.Fatalf("status %d %s, ingin 400 %s: %v", status, code, response.CodeInvalidTransition, body)
+	}
+	if got := env.status(t, pending.ID); got != model.RegistrationStatusPending {
+		t.Errorf("pendaftaran lain ikut berubah menjadi %s", got)
+	}
+	events, _ := env.repos.Events.ListByRegistration(context.Background(), pending.ID)
+	if len(events) != 0 {
+		t.Errorf("riwayat tercatat padahal tidak ada perubahan: %+v", events)
+	}
+}
+
+func TestUpdateUserHandlerLegacyUserWithoutBirthData(t *testing.T) {
+	env := newTestEnv(t)
+	superAdmin := env.createUser(t, "900001", "super_admin")
+	legacy := env.createUser(t, "714220001", "user")
+	legacy.BirthPlace, legacy.BirthDate = "", ""
+	if err := env.repos.Users.Update(context.Background(), legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/api/admin/users/" + legacy.ID.Hex()
+	rec := env.serve(t, "PATCH", "/api/admin/users/{id}", path, env.h.UpdateUserHandler, superAdmin, map[string]interface{}{
+		"name": "Nama Baru", "nim": legacy.NIM, "email": legacy.Email, "phone_number": legacy.PhoneNumber, "role": "user",
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	updated, _ := env.repos.Users.FindByID(context.Background(), legacy.ID)
+	if updated.Name != "Nama Baru" {
+		t.Errorf("nama %q, ingin Nama Baru", updated.Name)
+	}
+}
+
+func TestUpdateUserHandlerTrimsNIMBeforeDuplicateCheck(t *testing.T) {
+	env := newTestEnv(t)
+	superAdmin := env.createUser(t, "900001", "super_admin")
+	env.createUser(t, "714220001", "user")
+	other := env.createUser(t, "714220002", "user")
+
+	path := "/api/admin/users/" + other.ID.Hex()
+	rec := env.serve(t, "PATCH", "/api/admin/users/{id}", path, env.h.UpdateUserHandler, superAdmin, map[string]interface{}{
+		"name": other.Name, "nim": " 714220001 ", "email": other.Email, "phone_number": other.PhoneNumber, "role": "user",
+	})
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeNIMAlreadyRegistered {
+		t.Fatalf("status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeNIMAlreadyRegistered)
+	}
+	if updated, _ := env.repos.Users.FindByID(context.Background(), other.ID); updated.NIM != "714220002" {
+		t.Errorf("NIM berubah menjadi %q", updated.NIM)
+	}
+}
+
+func TestBulkUpdateStatusHandlerUnknownID(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	reg := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+	unknown := primitive.NewObjectID()
+
+	status, code, body := env.bulkUpdate(t, admin, []primitive.ObjectID{reg.ID, unknown}, model.RegistrationStatusDocumentReview)
+	if status != http.StatusNotFound || code != response.CodeRegistrationNotFound {
+		t.Fatalf("status %d %s, ingin 404 %s: %v", status, code, response.CodeRegistrationNotFound, body)
+	}
+	details := body["error"].(map[string]interface{})["details"].(map[string]interface{})
+	if ids := details["ids"].([]interface{}); len(ids) != 1 || ids[0] != unknown.Hex() {
+		t.Errorf("ID yang tidak ditemukan %v, ingin [%s]", ids, unknown.Hex())
+	}
+	if got := env.status(t, reg.ID); got != model.RegistrationStatusPending {
+		t.Errorf("pendaftaran ikut berubah menjadi %s", got)
+	}
+}
+
+// changingRegistrations mengubah status pendaftaran tertentu tepat setelah FindByIDs, seolah admin
+// lain memprosesnya saat bulk update sedang berjalan
+type changingRegistrations struct {
+	repository.RegistrationRepository
+	id     primitive.ObjectID
+	status string
+}
+
+func (r *changingRegistrations) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Registration, error) {
+	registrations, err := r.RegistrationRepository.FindByIDs(ctx, ids)
+	if err == nil {
+		err = r.RegistrationRepository.Update(ctx, r.id, repository.RegistrationUpdate{Status: &r.status})
+	}
+	return registrations, err
+}
+
+func TestBulkUpdateStatusHandlerConcurrentChange(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	first := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+	second := env.createRegistration(t, env.createUser(t, "714220002", "user"), model.RegistrationStatusPending)
+	env.repos.Registrations = &changingRegistrations{env.repos.Registrations, second.ID, model.RegistrationStatusDocumentReview}
+	env.h = New(Dependencies{Repositories: env.repos, Storage: env.storage, Notifier: env.notifier})
+
+	status, code, body := env.bulkUpdate(t, admin, []primitive.ObjectID{first.ID, second.ID}, model.RegistrationStatusRejected)
+	if status != http.StatusConflict || code != response.CodeStatusChanged {
+		t.Fatalf("status %d %s, ingin 409 %s: %v", status, code, response.CodeStatusChanged, body)
+	}
+	details := body["error"].(map[string]interface{})["details"].(map[string]interface{})
+	if ids := details["not_updated"].([]interface{}); len(ids) != 1 || ids[0] != second.ID.Hex() {
+		t.Errorf("not_updated %v, ingin [%s]", ids, second.ID.Hex())
+	}
+	if ids := details["updated"].([]interface{}); len(ids) != 1 || ids[0] != first.ID.Hex() {
+		t.Errorf("updated %v, ingin [%s]", ids, first.ID.Hex())
+	}
+
+	if got := env.status(t, first.ID); got != model.RegistrationStatusRejected {
+		t.Errorf("status pendaftaran pertama %s, ingin %s", got, model.RegistrationStatusRejected)
+	}
+	if got := env.status(t, second.ID); got != model.RegistrationStatusDocumentReview {
+		t.Errorf("perubahan admin lain tertimpa menjadi %s", got)
+	}
+	if events, _ := env.repos.Events.ListByRegistration(context.Background(), second.ID); len(events) != 0 {
+		t.Errorf("riwayat tercatat untuk pendaftaran yang tidak diubah: %+v", events)
+	}
+}
+
+const registrationsPath = "/api/admin/registrations-with-details"
+
+// registrationPage adalah respons GetAllRegistrationsDetailHandler
+type registrationPage struct {
+	Data       []model.RegistrationDetail `json:"data"`
+	Pagination pagination                 `json:"pagination"`
+}
+
+// listRegistrations mengambil satu halaman pendaftar dengan query tersebut
+func (e *testEnv) listRegistrations(t *testing.T, admin *model.User, query string) registrationPage {
+	t.Helper()
+	rec := e.serve(t, "GET", registrationsPath, registrationsPath+"?"+query, e.h.GetAllRegistrationsDetailHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: status %d, ingin 200: %s", query, rec.Code, rec.Body.String())
+	}
+	var page registrationPage
+	decode(t, rec, &page)
+	return page
+}
+
+func nims(details []model.RegistrationDetail) []string {
+	results := make([]string, len(details))
+	for i, detail := range details {
+		results[i] = detail.NIM
+	}
+	return results
+}
+
+func TestGetAllRegistrationsDetailHandler(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	period := env.openPeriod(t)
+	statuses := []string{
+		model.RegistrationStatusPending, model.RegistrationStatusInterview, model.RegistrationStatusPending,
+		model.RegistrationStatusRejected, model.RegistrationStatusInterview,
+	}
+	for i, status := range statuses {
+		env.registerIn(t, env.createUser(t, fmt.Sprintf("71422000%d", i+1), "user"), period.ID, status)
+	}
+	// Pendaftar Data saja dan pendaftar periode lain
+	dataOnly := &model.Registration{
+		UserID: env.createUser(t, "714220006", "user").ID, PeriodID: period.ID, Division1: "Data", Motivation: "m", VisionMission: "v",
+		Status: model.RegistrationStatusPending,
+	}
+	if err := env.repos.Registrations.Create(context.Background(), dataOnly); err != nil {
+		t.Fatal(err)
+	}
+	env.registerIn(t, env.createUser(t, "714220007", "user"), primitive.NewObjectID(), model.RegistrationStatusPending)
+
+	page := env.listRegistrations(t, admin, "status=pending,interview&division=web&sort=nim&order=asc&per_page=2&page=2")
+	if got := nims(page.Data); len(got) != 2 || got[0] != "714220003" || got[1] != "714220005" {
+		t.Errorf("halaman 2 %v, ingin [714220003 714220005]", got)
+	}
+	if want := (pagination{Page: 2, PerPage: 2, Total: 4, TotalPages: 2}); page.Pagination != want {
+		t.Errorf("pagination %+v, ingin %+v", page.Pagination, want)
+	}
+
+	page = env.listRegistrations(t, admin, "sort=nim")
+	if got := nims(page.Data); page.Pagination.Total != 6 || got[0] != "714220006" || got[5] != "714220001" {
+		t.Errorf("periode saat ini %v (total %d), ingin enam pendaftar urut NIM menurun", got, page.Pagination.Total)
+	}
+	if page = env.listRegistrations(t, admin, "period=all"); page.Pagination.Total != 7 {
+		t.Errorf("semua periode: total %d, ingin 7", page.Pagination.Total)
+	}
+	if page = env.listRegistrations(t, admin, "page=5"); len(page.Data) != 0 || page.Pagination.Total != 6 {
+		t.Errorf("halaman di luar jangkauan %+v, ingin kosong dengan total 6", page)
+	}
+}
+
+func TestGetAllRegistrationsDetailHandlerValidatesQuery(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	env.openPeriod(t)
+
+	rec := env.serve(t, "GET", registrationsPath, registrationsPath+"?status=diterima&sort=umur&order=naik&submitted_from=2026-09-01&submitted_to=2026-08-01",
+		env.h.GetAllRegistrationsDetailHandler, admin, nil)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	for _, field := range []string{"status", "sort", "order", "submitted_to"} {
+		if !hasFieldError(t, rec, field) {
+			t.Errorf("ingin kesalahan pada %s: %s", field, rec.Body.String())
+		}
+	}
+
+	rec = env.serve(t, "GET", registrationsPath, registrationsPath+"?page=0&per_page=500", env.h.GetAllRegistrationsDetailHandler, admin, nil)
+	if rec.Code != http.StatusUnprocessableEntity || !hasFieldError(t, rec, "page") || !hasFieldError(t, rec, "per_page") {
+		t.Errorf("status %d, ingin 422 pada page dan per_page: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sessionResponse adalah pasangan access token dan refresh token yang dikirim ke client
+type sessionResponse struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	Role             string    `json:"role"`
+}
+
+// issueSession membuat access token baru dan refresh token baru dengan ID refreshID
+func (h *Handler) issueSession(ctx context.Context, user *model.User, refreshID primitive.ObjectID) (*sessionResponse, error) {
+	token, payload, err := auth.GenerateToken(user.ID, user.NIM, user.Role, user.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refreshExpiresAt := now.Add(config.GetConfig().RefreshTokenTTL)
+	err = h.tokens.CreateRefreshToken(ctx, &model.RefreshToken{
+		ID:           refreshID,
+		UserID:       user.ID,
+		TokenHash:    refreshHash,
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    primitive.NewDateTimeFromTime(refreshExpiresAt),
+		CreatedAt:    primitive.NewDateTimeFromTime(now),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionResponse{
+		Token:            token,
+		ExpiresAt:        payload.ExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		Role:             user.Role,
+	}, nil
+}
+
+// RefreshTokenHandler menukar refresh token yang masih berlaku dengan pasangan token baru (rotasi).
+// Refresh token lama langsung dicabut; jika token yang sudah dicabut dipakai lagi,
+// semua sesi user tersebut ikut dicabut karena token kemungkinan bocor.
+func (h *Handler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || strings.TrimSpace(payload.RefreshToken) == "" {
+		response.Error(w, r, http.StatusBadRequest, response.CodeRefreshTokenRequired, "Refresh token wajib diisi")
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+
+	stored, err := h.tokens.FindRefreshToken(ctx, auth.HashToken(payload.RefreshToken))
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusUnauthorized, response.CodeRefreshTokenInvalid, "Refresh token tidak valid")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memvalidasi refresh token")
+		return
+	}
+
+	if stored.ExpiresAt.Time().Before(now) {
+		response.Error(w, r, http.StatusUnauthorized, response.CodeRefreshTokenExpired, "Refresh token sudah kedaluwarsa")
+		return
+	}
+
+	newRefreshID := primitive.NewObjectID()
+	err = h.tokens.RevokeRefreshToken(ctx, stored.ID, newRefreshID, now)
+	if err == repository.ErrNotFound {
+		log.Printf("refresh token reuse detected for user %s, revoking all sessions", stored.UserID.Hex())
+		if err := h.tokens.RevokeUserRefreshTokens(ctx, stored.UserID, now); err != nil {
+			log.Printf("failed to revoke sessions for user %s: %v", stored.UserID.Hex(), err)
+		}
+		response.Error(w, r, http.StatusUnauthorized, response.CodeRefreshTokenInvalid, "Refresh token tidak valid")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui refresh token")
+		return
+	}
+
+	// Ambil data user terbaru agar perubahan role ikut masuk ke token baru.
+	// Refresh token yang terbit sebelum role/password/status akun berubah ikut ditolak.
+	user, err := h.users.FindByID(ctx, stored.UserID)
+	if err != nil || user.Disabled || user.TokenVersion != stored.TokenVersion {
+		response.Error(w, r, http.StatusUnauthorized, response.CodeRefreshTokenInvalid, "Refresh token tidak valid")
+		return
+	}
+
+	session, err := h.issueSession(ctx, user, newRefreshID)
+	if err != nil {
+		log.Printf("token generation failed for NIM %s: %v", user.NIM, err)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat token")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, session)
+}
+
+// LogoutHandler mencabut refresh token yang dikirim dan, jika ada, access token di header Authorization
+func (h *Handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || strings.TrimSpace(payload.RefreshToken) == "" {
+		response.Error(w, r, http.StatusBadRequest, response.CodeRefreshTokenRequired, "Refresh token wajib diisi")
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+
+	stored, err := h.tokens.FindRefreshToken(ctx, auth.HashToken(payload.RefreshToken))
+	if err != nil && err != repository.ErrNotFound {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mencabut refresh token")
+		return
+	}
+	if err == nil {
+		// Token yang sudah dicabut tidak perlu dicabut lagi
+		err = h.tokens.RevokeRefreshToken(ctx, stored.ID, primitive.NilObjectID, now)
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mencabut refresh token")
+			return
+		}
+	}
+
+	// Access token bersifat opsional, karena bisa saja sudah kedaluwarsa saat logout
+	if parts := strings.Split(r.Header.Get("Authorization"), " "); len(parts) == 2 && parts[0] == "Bearer" {
+		if accessPayload, err := auth.VerifyToken(parts[1]); err == nil {
+			if err := h.tokens.RevokeAccessToken(ctx, accessPayload.TokenID, accessPayload.ExpiresAt); err != nil {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mencabut access token")
+				return
+			}
+		}
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Logout successful"})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// login masuk sebagai user dengan password test dan mengembalikan sesinya
+func (e *testEnv) login(t *testing.T, user *model.User) sessionResponse {
+	t.Helper()
+	rec := e.serve(t, "POST", "/login", "/login", e.h.LoginHandler, nil, map[string]string{"NPM": user.NIM, "password": testPassword})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var session sessionResponse
+	decode(t, rec, &session)
+	return session
+}
+
+// refresh menukar refresh token lewat RefreshTokenHandler
+func (e *testEnv) refresh(t *testing.T, refreshToken string) *httptest.ResponseRecorder {
+	t.Helper()
+	return e.serve(t, "POST", "/auth/refresh", "/auth/refresh", e.h.RefreshTokenHandler, nil, map[string]string{"refresh_token": refreshToken})
+}
+
+func TestRefreshTokenHandlerRotates(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+	session := env.login(t, user)
+
+	rec := env.refresh(t, session.RefreshToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var rotated sessionResponse
+	decode(t, rec, &rotated)
+	if rotated.Token == "" || rotated.RefreshToken == "" || rotated.RefreshToken == session.RefreshToken || rotated.Role != "user" {
+		t.Fatalf("sesi baru tidak valid: %+v", rotated)
+	}
+
+	old, _ := env.repos.Tokens.FindRefreshToken(context.Background(), auth.HashToken(session.RefreshToken))
+	if old.RevokedAt == nil || old.ReplacedBy.IsZero() {
+		t.Errorf("refresh token lama tidak dicabut: %+v", old)
+	}
+}
+
+func TestRefreshTokenHandlerReuseRevokesFamily(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+	session := env.login(t, user)
+
+	var rotated sessionResponse
+	decode(t, env.refresh(t, session.RefreshToken), &rotated)
+
+	// Token lama dipakai lagi: dianggap bocor sehingga token hasil rotasinya ikut dicabut
+	rec := env.refresh(t, session.RefreshToken)
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != response.CodeRefreshTokenInvalid {
+		t.Fatalf("pemakaian ulang: status %d %s, ingin 401 %s", rec.Code, errorCode(t, rec), response.CodeRefreshTokenInvalid)
+	}
+	rec = env.refresh(t, rotated.RefreshToken)
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != response.CodeRefreshTokenInvalid {
+		t.Errorf("token hasil rotasi: status %d %s, ingin 401 %s", rec.Code, errorCode(t, rec), response.CodeRefreshTokenInvalid)
+	}
+}
+
+func TestRefreshTokenHandlerExpired(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+	token, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = env.repos.Tokens.CreateRefreshToken(context.Background(), &model.RefreshToken{
+		ID: primitive.NewObjectID(), UserID: user.ID, TokenHash: hash,
+		ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute)),
+		CreatedAt: primitive.NewDateTimeFromTime(time.Now().Add(-time.Hour)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := env.refresh(t, token)
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != response.CodeRefreshTokenExpired {
+		t.Errorf("status %d %s, ingin 401 %s", rec.Code, errorCode(t, rec), response.CodeRefreshTokenExpired)
+	}
+	if rec := env.refresh(t, "tidak-dikenal"); rec.Code != http.StatusUnauthorized || errorCode(t, rec) != response.CodeRefreshTokenInvalid {
+		t.Errorf("token tidak dikenal: status %d %s, ingin 401 %s", rec.Code, errorCode(t, rec), response.CodeRefreshTokenInvalid)
+	}
+}
+
+func TestLogoutHandlerRevokesTokens(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+	session := env.login(t, user)
+
+	req := httptest.NewRequest("POST", "/auth/logout", strings.NewReader(`{"refresh_token":"`+session.RefreshToken+`"}`))
+	req.Header.Set("Authorization", "Bearer "+session.Token)
+	rec := env.serveRequest(t, "/auth/logout", req, env.h.LogoutHandler, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := env.refresh(t, session.RefreshToken); rec.Code != http.StatusUnauthorized {
+		t.Errorf("refresh setelah logout: status %d, ingin 401", rec.Code)
+	}
+
+	// Access token yang sama ditolak AuthMiddleware
+	protected := middleware.AuthMiddleware(env.repos.Tokens, env.repos.Users)(http.HandlerFunc(env.h.GetUserProfileHandler))
+	req = httptest.NewRequest("GET", "/api/user/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+session.Token)
+	rec = httptest.NewRecorder()
+	protected.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != response.CodeTokenRevoked {
+		t.Errorf("access token setelah logout: status %d %s, ingin 401 %s", rec.Code, errorCode(t, rec), response.CodeTokenRevoked)
+	}
+}
+
+func TestLogoutHandlerRequiresRefreshToken(t *testing.T) {
+	env := newTestEnv(t)
+
+	rec := env.serve(t, "POST", "/auth/logout", "/auth/logout", env.h.LogoutHandler, nil, map[string]string{})
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != response.CodeRefreshTokenRequired {
+		t.Errorf("status %d %s, ingin 400 %s", rec.Code, errorCode(t, rec), response.CodeRefreshTokenRequired)
+	}
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetDivisionsHandler mengembalikan divisi aktif untuk mengisi pilihan pada form pendaftaran
+func (h *Handler) GetDivisionsHandler(w http.ResponseWriter, r *http.Request) {
+	divisions, err := h.divisions.List(r.Context(), true)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+		return
+	}
+
+	// Koordinator dan rubrik wawancara hanya relevan untuk admin
+	for i := range divisions {
+		divisions[i].Coordinators = nil
+		divisions[i].Rubric = nil
+	}
+
+	response.JSON(w, http.StatusOK, divisions)
+}
+
+// GetAllDivisionsHandler mengembalikan semua divisi termasuk yang tidak aktif (Admin only)
+func (h *Handler) GetAllDivisionsHandler(w http.ResponseWriter, r *http.Request) {
+	divisions, err := h.divisions.List(r.Context(), false)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, divisions)
+}
+
+// CreateDivisionHandler menambahkan divisi baru ke katalog (Admin only). Divisi baru aktif secara default.
+func (h *Handler) CreateDivisionHandler(w http.ResponseWriter, r *http.Request) {
+	division := model.Division{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&division); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	division.ID = primitive.NilObjectID
+	division.Name = strings.TrimSpace(division.Name)
+	division.Rubric = nil // diatur lewat endpoint rubrik
+	division.CreatedAt = now
+	division.UpdatedAt = now
+
+	if !h.validateDivision(w, r, &division) {
+		return
+	}
+
+	if err := h.divisions.Create(r.Context(), &division); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat divisi")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, division)
+}
+
+// UpdateDivisionHandler memperbarui divisi (Admin only). Field yang tidak dikirim tidak berubah.
+// Jika nama berubah, pilihan divisi pada pendaftaran yang sudah ada ikut diganti.
+func (h *Handler) UpdateDivisionHandler(w http.ResponseWriter, r *http.Request) {
+	divisionID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID divisi tidak valid")
+		return
+	}
+
+	existing, err := h.divisions.FindByID(r.Context(), divisionID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeDivisionNotFound, "Divisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui divisi")
+		return
+	}
+
+	division := *existing
+	if err := json.NewDecoder(r.Body).Decode(&division); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	division.ID = existing.ID
+	division.Name = strings.TrimSpace(division.Name)
+	division.Rubric = existing.Rubric
+	division.CreatedAt = existing.CreatedAt
+	division.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	if !h.validateDivision(w, r, &division) {
+		return
+	}
+
+	err = h.divisions.Update(r.Context(), &division)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeDivisionNotFound, "Divisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui divisi")
+		return
+	}
+
+	if division.Name != existing.Name {
+		if err := h.registrations.RenameDivision(r.Context(), existing.Name, division.Name); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui pilihan divisi pada pendaftaran")
+			return
+		}
+	}
+
+	response.JSON(w, http.StatusOK, division)
+}
+
+// DeleteDivisionHandler menghapus divisi yang belum dipilih pendaftar mana pun (Admin only).
+// Divisi yang sudah dipilih sebaiknya dinonaktifkan saja.
+func (h *Handler) DeleteDivisionHandler(w http.ResponseWriter, r *http.Request) {
+	divisionID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID divisi tidak valid")
+		return
+	}
+
+	division, err := h.divisions.FindByID(r.Context(), divisionID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeDivisionNotFound, "Divisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus divisi")
+		return
+	}
+
+	used, err := h.registrations.CountByDivision(r.Context(), division.Name)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus divisi")
+		return
+	}
+	if used > 0 {
+		response.ErrorWithDetails(w, r, http.StatusConflict, response.CodeDivisionInUse,
+			"Divisi sudah dipilih pendaftar. Nonaktifkan divisi alih-alih menghapusnya.",
+			map[string]int64{"registrations": used})
+		return
+	}
+
+	err = h.divisions.Delete(r.Context(), divisionID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeDivisionNotFound, "Divisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus divisi")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Divisi berhasil dihapus"})
+}
+
+// validateDivision memvalidasi isian divisi, keunikan nama, dan koordinatornya.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) validateDivision(w http.ResponseWriter, r *http.Request, division *model.Division) bool {
+	errs := validation.Struct(division)
+
+	if division.Coordinators == nil {
+		division.Coordinators = []primitive.ObjectID{}
+	}
+	if err := h.checkAdmins(r.Context(), &errs, "coordinators", "INVALID_COORDINATOR", "Koordinator", division.Coordinators); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa koordinator divisi")
+		return false
+	}
+
+	if !errs.Has("name") {
+		other, err := h.divisions.FindByName(r.Context(), division.Name)
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa nama divisi")
+			return false
+		}
+		if err == nil && other.ID != division.ID {
+			response.Error(w, r, http.StatusConflict, response.CodeDivisionNameTaken, "Nama divisi sudah dipakai")
+			return false
+		}
+	}
+
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return false
+	}
+	return true
+}
+
+// checkAdmins memastikan setiap ID pada ids adalah user admin/super_admin dan mencatat
+// yang tidak valid ke errs dengan kode dan label yang diberikan
+func (h *Handler) checkAdmins(ctx context.Context, errs *validation.Errors, field, code, label string, ids []primitive.ObjectID) error {
+	for _, id := range ids {
+		user, err := h.users.FindByID(ctx, id)
+		if err != nil && err != repository.ErrNotFound {
+			return err
+		}
+		if err == repository.ErrNotFound || (user.Role != "admin" && user.Role != "super_admin") {
+			errs.Add(field, code, label+" "+id.Hex()+" bukan admin")
+		}
+	}
+	return nil
+}
+
+// activeDivision mencari divisi aktif berdasarkan nama. Divisi yang tidak ada atau tidak aktif
+// dikembalikan sebagai repository.ErrNotFound.
+func (h *Handler) activeDivision(ctx context.Context, name string) (*model.Division, error) {
+	division, err := h.divisions.FindByName(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+	if !division.Active {
+		return nil, repository.ErrNotFound
+	}
+	return division, nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	divisionsPath = "/api/admin/divisions"
+	divisionPath  = "/api/admin/divisions/{id}"
+)
+
+func TestDivisionHandlers(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	env.openPeriod(t)
+	ctx := context.Background()
+
+	rec := env.serve(t, "POST", divisionsPath, divisionsPath, env.h.CreateDivisionHandler, admin,
+		map[string]interface{}{"name": " Game ", "description": "Pengembangan game", "quota": 5, "coordinators": []string{admin.ID.Hex()}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	var game model.Division
+	decode(t, rec, &game)
+	if game.Name != "Game" || !game.Active || game.Quota != 5 {
+		t.Errorf("divisi baru %+v, ingin Game aktif dengan kuota 5", game)
+	}
+
+	// Nonaktifkan Data: katalog publik hanya berisi divisi aktif tanpa koordinator
+	data, _ := env.repos.Divisions.FindByName(ctx, "Data")
+	rec = env.serve(t, "PUT", divisionPath, "/api/admin/divisions/"+data.ID.Hex(), env.h.UpdateDivisionHandler, admin, map[string]bool{"active": false})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	rec = env.serve(t, "GET", "/api/divisions", "/api/divisions", env.h.GetDivisionsHandler, nil, nil)
+	var public []model.Division
+	decode(t, rec, &public)
+	names := map[string]bool{}
+	for _, division := range public {
+		names[division.Name] = true
+		if len(division.Coordinators) != 0 {
+			t.Errorf("koordinator %s ikut tampil di katalog publik", division.Name)
+		}
+	}
+	if len(public) != 2 || !names["Web"] || !names["Game"] {
+		t.Errorf("katalog publik %v, ingin Web dan Game", names)
+	}
+
+	// Mengganti nama ikut mengganti pilihan pada pendaftaran yang sudah ada
+	user := env.createUser(t, "714220001", "user")
+	registration := env.createRegistration(t, user, model.RegistrationStatusPending)
+	web, _ := env.repos.Divisions.FindByName(ctx, "Web")
+	rec = env.serve(t, "PUT", divisionPath, "/api/admin/divisions/"+web.ID.Hex(), env.h.UpdateDivisionHandler, admin, map[string]string{"name": "Web Dev"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rename: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	if updated, _ := env.repos.Registrations.FindByID(ctx, registration.ID); updated.Division1 != "Web Dev" {
+		t.Errorf("pilihan divisi pendaftaran %q, ingin Web Dev", updated.Division1)
+	}
+}
+
+func TestCreateDivisionHandlerRejectsInvalidInput(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	applicant := env.createUser(t, "714220001", "user")
+	env.openPeriod(t)
+
+	// Nama divisi unik tanpa membedakan huruf besar/kecil
+	rec := env.serve(t, "POST", divisionsPath, divisionsPath, env.h.CreateDivisionHandler, admin, map[string]string{"name": "web"})
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeDivisionNameTaken {
+		t.Errorf("nama ganda: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeDivisionNameTaken)
+	}
+
+	rec = env.serve(t, "POST", divisionsPath, divisionsPath, env.h.CreateDivisionHandler, admin,
+		map[string]interface{}{"name": "Game", "quota": -1, "coordinators": []string{applicant.ID.Hex()}})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "quota") || !hasFieldError(t, rec, "coordinators") {
+		t.Errorf("ingin kesalahan pada quota dan coordinators: %s", rec.Body.String())
+	}
+}
+
+func TestDeleteDivisionHandler(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	env.openPeriod(t)
+	ctx := context.Background()
+	env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+
+	web, _ := env.repos.Divisions.FindByName(ctx, "Web")
+	rec := env.serve(t, "DELETE", divisionPath, "/api/admin/divisions/"+web.ID.Hex(), env.h.DeleteDivisionHandler, admin, nil)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeDivisionInUse {
+		t.Errorf("divisi terpakai: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeDivisionInUse)
+	}
+
+	unused := &model.Division{Name: "Game", Active: true}
+	if err := env.repos.Divisions.Create(ctx, unused); err != nil {
+		t.Fatal(err)
+	}
+	rec = env.serve(t, "DELETE", divisionPath, "/api/admin/divisions/"+unused.ID.Hex(), env.h.DeleteDivisionHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	rec = env.serve(t, "DELETE", divisionPath, "/api/admin/divisions/"+primitive.NewObjectID().Hex(), env.h.DeleteDivisionHandler, admin, nil)
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != response.CodeDivisionNotFound {
+		t.Errorf("divisi tidak ada: status %d %s, ingin 404 %s", rec.Code, errorCode(t, rec), response.CodeDivisionNotFound)
+	}
+}
+
+func TestSubmitRegistrationHandlerValidatesDivisions(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+	ctx := context.Background()
+	data, _ := env.repos.Divisions.FindByName(ctx, "Data")
+	data.Active = false
+	if err := env.repos.Divisions.Update(ctx, data); err != nil {
+		t.Fatal(err)
+	}
+
+	answers := map[string]string{"division1": "Game", "division2": "Data", "motivation": "Belajar", "vision_mission": "Berkontribusi"}
+	req := registrationRequest(t, "POST", answers, "cv", "certificate", "formal_photo")
+	rec := env.serveRequest(t, "/api/user/registration", req, env.h.SubmitRegistrationHandler, user)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "division1") || !hasFieldError(t, rec, "division2") {
+		t.Errorf("divisi tidak dikenal dan nonaktif harus ditolak: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+)
+
+// sendVerificationEmail membuat kode verifikasi baru dan mengirimkannya ke email user
+func (h *Handler) sendVerificationEmail(ctx context.Context, user *model.User) error {
+	token, err := auth.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.GetConfig()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Halo %s,\n\n", user.Name)
+	b.WriteString("Terima kasih telah mendaftar di sistem pendaftaran HIMATIF.\n")
+	if cfg.EmailVerificationURL != "" {
+		fmt.Fprintf(&b, "Buka tautan berikut untuk memverifikasi email Anda:\n%s?token=%s\n\n", cfg.EmailVerificationURL, token)
+	} else {
+		fmt.Fprintf(&b, "Gunakan kode berikut untuk memverifikasi email Anda:\n%s\n\n", token)
+	}
+	fmt.Fprintf(&b, "Kode berlaku selama %s.\n", cfg.EmailVerificationTTL)
+
+	return h.notifier.Send(ctx, notify.Message{
+		To:      user.Email,
+		Subject: "Verifikasi Email Pendaftaran HIMATIF",
+		Body:    b.String(),
+	})
+}
+
+// VerifyEmailHandler memverifikasi email user memakai kode yang dikirim saat registrasi
+func (h *Handler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	var errs validation.Errors
+	validation.Var(&errs, "token", "Kode verifikasi", body.Token, "required")
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	userID, email, err := auth.VerifyEmailVerificationToken(strings.TrimSpace(body.Token))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeVerificationTokenInvalid, "Kode verifikasi tidak valid atau sudah kedaluwarsa")
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memverifikasi email")
+		return
+	}
+
+	// Kode untuk email lama tidak berlaku setelah email diganti
+	if !strings.EqualFold(user.Email, email) {
+		response.Error(w, r, http.StatusBadRequest, response.CodeVerificationTokenInvalid, "Kode verifikasi tidak valid atau sudah kedaluwarsa")
+		return
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		if err := h.users.SetEmailVerified(ctx, user.ID, &now); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memverifikasi email")
+			return
+		}
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Email berhasil diverifikasi"})
+}
+
+// ResendVerificationEmailHandler mengirim ulang kode verifikasi ke email user yang sedang login
+func (h *Handler) ResendVerificationEmailHandler(w http.ResponseWriter, r *http.Request) {
+	payload, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	user, err := h.users.FindByID(r.Context(), payload.UserID)
+	if err != nil {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+
+	if user.EmailVerifiedAt != nil {
+		response.Error(w, r, http.StatusConflict, response.CodeEmailAlreadyVerified, "Email sudah diverifikasi")
+		return
+	}
+
+	if err := h.sendVerificationEmail(r.Context(), user); err != nil {
+		log.Printf("failed to send verification email for NIM %s: %v", user.NIM, err)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeNotificationFailed, "Gagal mengirim email verifikasi")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Email verifikasi telah dikirim ulang"})
+}
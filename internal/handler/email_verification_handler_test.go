@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+)
+
+// withEmailVerification mengganti kebijakan dan masa berlaku verifikasi email selama satu test.
+// Kode dikirim tanpa tautan agar bisa dibaca langsung dari pesan.
+func withEmailVerification(t *testing.T, required bool, ttl time.Duration) {
+	t.Helper()
+	cfg := config.GetConfig()
+	previousRequired, previousTTL, previousURL := cfg.RequireEmailVerification, cfg.EmailVerificationTTL, cfg.EmailVerificationURL
+	cfg.RequireEmailVerification, cfg.EmailVerificationTTL, cfg.EmailVerificationURL = required, ttl, ""
+	t.Cleanup(func() {
+		cfg.RequireEmailVerification, cfg.EmailVerificationTTL, cfg.EmailVerificationURL = previousRequired, previousTTL, previousURL
+	})
+}
+
+// verifyEmail mengirim kode verifikasi ke VerifyEmailHandler
+func (e *testEnv) verifyEmail(t *testing.T, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	return e.serve(t, "POST", "/verify-email", "/verify-email", e.h.VerifyEmailHandler, nil, map[string]string{"token": token})
+}
+
+func TestVerifyEmailHandler(t *testing.T) {
+	env := newTestEnv(t)
+	withEmailVerification(t, true, time.Hour)
+
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, registerBody("714220001"))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register: status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	messages := env.notifier.Messages()
+	if len(messages) != 1 || messages[0].To != "714220001@example.com" {
+		t.Fatalf("kode verifikasi tidak terkirim: %+v", messages)
+	}
+	_, rest, _ := strings.Cut(messages[0].Body, "email Anda:\n")
+	code, _, _ := strings.Cut(rest, "\n")
+
+	ctx := context.Background()
+	user, _ := env.repos.Users.FindByNIM(ctx, "714220001")
+	if user.EmailVerifiedAt != nil {
+		t.Fatal("email sudah terverifikasi sebelum kode dipakai")
+	}
+	// Pendaftaran ditolak selama email belum diverifikasi
+	req := registrationRequest(t, "POST", registrationAnswers, "cv", "certificate", "formal_photo")
+	rec = env.serveRequest(t, "/api/user/registration", req, env.h.SubmitRegistrationHandler, user)
+	if rec.Code != http.StatusForbidden || errorCode(t, rec) != response.CodeEmailNotVerified {
+		t.Errorf("submit: status %d %s, ingin 403 %s", rec.Code, errorCode(t, rec), response.CodeEmailNotVerified)
+	}
+
+	if rec := env.verifyEmail(t, code); rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	if user, _ = env.repos.Users.FindByID(ctx, user.ID); user.EmailVerifiedAt == nil {
+		t.Error("email belum ditandai terverifikasi")
+	}
+}
+
+func TestVerifyEmailHandlerRejectsInvalidCodes(t *testing.T) {
+	env := newTestEnv(t)
+	withEmailVerification(t, true, -time.Minute)
+	user := env.createUser(t, "714220001", "user")
+
+	expired, err := auth.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.GetConfig().EmailVerificationTTL = time.Hour
+	oldEmail, err := auth.GenerateEmailVerificationToken(user.ID, "lama@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, code := range map[string]string{"kedaluwarsa": expired, "email lama": oldEmail, "bukan kode": "abc"} {
+		rec := env.verifyEmail(t, code)
+		if rec.Code != http.StatusBadRequest || errorCode(t, rec) != response.CodeVerificationTokenInvalid {
+			t.Errorf("%s: status %d %s, ingin 400 %s", name, rec.Code, errorCode(t, rec), response.CodeVerificationTokenInvalid)
+		}
+	}
+	if user, _ := env.repos.Users.FindByID(context.Background(), user.ID); user.EmailVerifiedAt != nil {
+		t.Error("email terverifikasi dengan kode yang tidak valid")
+	}
+}
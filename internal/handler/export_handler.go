@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/export"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+)
+
+// exportBatchSize adalah jumlah pendaftaran yang diambil per query saat ekspor,
+// agar data tidak dimuat ke memori sekaligus
+const exportBatchSize = 500
+
+// exportTimeLayout adalah format waktu pada file ekspor (WIB)
+const exportTimeLayout = "2006-01-02 15:04"
+
+// exportColumn adalah satu kolom file ekspor pendaftaran
+type exportColumn struct {
+	Key    string
+	Header string
+	Value  func(detail *model.RegistrationDetail) interface{}
+}
+
+// exportColumns adalah kolom yang tersedia, berurutan seperti pada file jika query columns kosong
+var exportColumns = []exportColumn{
+	{"registration_id", "ID Pendaftaran", func(d *model.RegistrationDetail) interface{} { return d.ID.Hex() }},
+	{"submitted_at", "Waktu Daftar", func(d *model.RegistrationDetail) interface{} {
+		return d.ID.Timestamp().In(localTimeZone).Format(exportTimeLayout)
+	}},
+	{"name", "Nama", func(d *model.RegistrationDetail) interface{} { return d.Name }},
+	{"nim", "NIM", func(d *model.RegistrationDetail) interface{} { return d.NIM }},
+	{"email", "Email", func(d *model.RegistrationDetail) interface{} { return d.Email }},
+	{"phone_number", "No. Telepon", func(d *model.RegistrationDetail) interface{} { return d.PhoneNumber }},
+	{"division1", "Pilihan Divisi 1", func(d *model.RegistrationDetail) interface{} { return d.Division1 }},
+	{"division2", "Pilihan Divisi 2", func(d *model.RegistrationDetail) interface{} { return d.Division2 }},
+	{"status", "Status", func(d *model.RegistrationDetail) interface{} {
+		if status, ok := model.FindRegistrationStatus(d.Status); ok {
+			return status.Label
+		}
+		return d.Status
+	}},
+	{"placed_division", "Divisi Penempatan", func(d *model.RegistrationDetail) interface{} { return d.PlacedDivision }},
+	{"interview_schedule", "Jadwal Wawancara", func(d *model.RegistrationDetail) interface{} { return d.InterviewSchedule }},
+	{"interview_location", "Lokasi Wawancara", func(d *model.RegistrationDetail) interface{} { return d.InterviewLocation }},
+	{"score_average", "Rata-rata Nilai", func(d *model.RegistrationDetail) interface{} {
+		if d.ScoreAverage == nil {
+			return nil
+		}
+		return *d.ScoreAverage
+	}},
+	{"score_count", "Jumlah Penilai", func(d *model.RegistrationDetail) interface{} { return d.ScoreCount }},
+	{"motivation", "Motivasi", func(d *model.RegistrationDetail) interface{} { return d.Motivation }},
+	{"vision_mission", "Visi Misi", func(d *model.RegistrationDetail) interface{} { return d.VisionMission }},
+	{"note", "Catatan", func(d *model.RegistrationDetail) interface{} { return d.Note }},
+	{"cv_url", "CV", func(d *model.RegistrationDetail) interface{} { return d.CvUrl }},
+	{"certificate_url", "Sertifikat", func(d *model.RegistrationDetail) interface{} { return d.CertificateUrl }},
+	{"optional_certificate_url", "Sertifikat Tambahan", func(d *model.RegistrationDetail) interface{} { return d.OptionalCertificateUrl }},
+	{"formal_photo_url", "Foto Formal", func(d *model.RegistrationDetail) interface{} { return d.FormalPhotoUrl }},
+	{"updated_at", "Terakhir Diubah", func(d *model.RegistrationDetail) interface{} {
+		if d.UpdatedAt == 0 {
+			return nil
+		}
+		return d.UpdatedAt.Time().In(localTimeZone).Format(exportTimeLayout)
+	}},
+}
+
+// ExportRegistrationsHandler mengunduh daftar pendaftar sebagai CSV atau XLSX.
+// Filter sama dengan GetAllRegistrationsDetailHandler; query columns (dipisah koma) memilih dan
+// mengurutkan kolom. Baris selalu diurutkan berdasarkan waktu kirim (query order tetap berlaku) karena
+// data diambil per batch dengan paginasi keyset pada ID, sehingga pendaftaran yang masuk atau dihapus
+// selama ekspor tidak membuat baris terlewat atau tertulis dua kali.
+func (h *Handler) ExportRegistrationsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.registrationFilter(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var errs validation.Errors
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	validation.Var(&errs, "format", "Format", format, "oneof="+strings.Join(export.Formats, " "))
+	columns := selectExportColumns(&errs, query.Get("columns"))
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	// Halaman pertama diambil sebelum header dikirim agar kegagalan masih bisa dijawab dengan JSON
+	filter.Sort = repository.RegistrationSortSubmitted
+	filter.Page, filter.PerPage = 1, exportBatchSize
+	details, _, err := h.registrations.ListDetails(r.Context(), filter)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+
+	filename := fmt.Sprintf("pendaftaran-%s.%s", time.Now().In(localTimeZone).Format("20060102-1504"), format)
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	// Setelah header terkirim, kegagalan hanya bisa dicatat dan file akan terpotong
+	writer, err := export.NewWriter(format, w, "Pendaftaran")
+	if err != nil {
+		log.Printf("Gagal memulai ekspor pendaftaran: %v", err)
+		return
+	}
+	row := make([]interface{}, len(columns))
+	for i, column := range columns {
+		row[i] = column.Header
+	}
+	if err := writer.WriteRow(row); err != nil {
+		log.Printf("Gagal menulis ekspor pendaftaran: %v", err)
+		return
+	}
+
+	for {
+		for i := range details {
+			for j, column := range columns {
+				row[j] = column.Value(&details[i])
+			}
+			if err := writer.WriteRow(row); err != nil {
+				log.Printf("Gagal menulis ekspor pendaftaran: %v", err)
+				return
+			}
+		}
+		if len(details) < filter.PerPage {
+			break
+		}
+		filter.AfterID = details[len(details)-1].ID
+		details, _, err = h.registrations.ListDetails(r.Context(), filter)
+		if err != nil {
+			log.Printf("Gagal mengambil data pendaftaran setelah %s untuk ekspor: %v", filter.AfterID.Hex(), err)
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Gagal menyelesaikan ekspor pendaftaran: %v", err)
+	}
+}
+
+// selectExportColumns membaca query columns; kosong berarti semua kolom
+func selectExportColumns(errs *validation.Errors, value string) []exportColumn {
+	if strings.TrimSpace(value) == "" {
+		return exportColumns
+	}
+
+	var columns []exportColumn
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		found := false
+		for _, column := range exportColumns {
+			if column.Key == key {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs.Add("columns", validation.CodeInvalidOption, "Kolom "+key+" tidak dikenal")
+		}
+	}
+	return columns
+}
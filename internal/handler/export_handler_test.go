@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+)
+
+const exportPath = "/api/admin/registrations/export"
+
+// deletingRegistrations menghapus pendaftaran tertentu setelah batch pertama ekspor diambil,
+// seolah pendaftar mengundurkan diri saat file sedang diunduh
+type deletingRegistrations struct {
+	repository.RegistrationRepository
+	remove *model.Registration
+	calls  int
+}
+
+func (r *deletingRegistrations) ListDetails(ctx context.Context, filter repository.RegistrationFilter) ([]model.RegistrationDetail, int64, error) {
+	details, total, err := r.RegistrationRepository.ListDetails(ctx, filter)
+	r.calls++
+	if err == nil && r.calls == 1 {
+		err = r.RegistrationRepository.Delete(ctx, r.remove.ID)
+	}
+	return details, total, err
+}
+
+func TestExportRegistrationsHandlerKeysetPagination(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	ctx := context.Background()
+
+	// Lebih dari satu batch; user dibuat langsung tanpa hash password agar cepat
+	count := exportBatchSize + 20
+	registrations := make([]*model.Registration, count)
+	for i := range registrations {
+		user := &model.User{Name: fmt.Sprintf("User %d", i), NIM: fmt.Sprintf("7142%05d", i), Role: "user"}
+		if err := env.repos.Users.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		registrations[i] = env.createRegistration(t, user, model.RegistrationStatusPending)
+	}
+	repo := &deletingRegistrations{RegistrationRepository: env.repos.Registrations, remove: registrations[0]}
+	env.repos.Registrations = repo
+	env.h = New(Dependencies{Repositories: env.repos, Storage: env.storage, Notifier: env.notifier})
+
+	rec := env.serve(t, "GET", exportPath, exportPath+"?columns=registration_id&sort=name&order=asc", env.h.ExportRegistrationsHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	if repo.calls < 2 {
+		t.Fatalf("ListDetails dipanggil %d kali, ingin lebih dari satu batch", repo.calls)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(rec.Body.Bytes(), []byte{0xEF, 0xBB, 0xBF}))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]int)
+	for _, record := range records[1:] {
+		seen[record[0]]++
+	}
+	for i, registration := range registrations {
+		if n := seen[registration.ID.Hex()]; n != 1 {
+			t.Errorf("pendaftaran ke-%d tertulis %d kali, ingin 1", i, n)
+		}
+	}
+	if len(records)-1 != count {
+		t.Errorf("jumlah baris %d, ingin %d", len(records)-1, count)
+	}
+	// Urutan selalu waktu kirim walaupun sort=name
+	if records[1][0] != registrations[0].ID.Hex() || records[count][0] != registrations[count-1].ID.Hex() {
+		t.Errorf("baris tidak diurutkan berdasarkan waktu kirim")
+	}
+}
+
+func TestExportRegistrationsHandlerInvalidFormat(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+
+	rec := env.serve(t, "GET", exportPath, exportPath+"?format=pdf&columns=registration_id,unknown", env.h.ExportRegistrationsHandler, admin, nil)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "format") || !hasFieldError(t, rec, "columns") {
+		t.Errorf("ingin kesalahan pada format dan columns: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/storage"
+)
+
+// Dependencies berisi semua dependensi yang dibutuhkan handler
+type Dependencies struct {
+	Repositories *repository.Repositories
+	Storage      storage.FileStorage
+	Notifier     notify.Notifier
+}
+
+// Handler menampung semua HTTP handler aplikasi beserta dependensinya
+type Handler struct {
+	users          repository.UserRepository
+	registrations  repository.RegistrationRepository
+	events         repository.RegistrationEventRepository
+	notes          repository.RegistrationNoteRepository
+	interviewSlots repository.InterviewSlotRepository
+	scores         repository.InterviewScoreRepository
+	informations   repository.InformationRepository
+	divisions      repository.DivisionRepository
+	periods        repository.RecruitmentPeriodRepository
+	tokens         repository.TokenRepository
+	passwordResets repository.PasswordResetRepository
+	loginAttempts  repository.LoginAttemptRepository
+	storage        storage.FileStorage
+	notifier       notify.Notifier
+}
+
+// New membuat Handler dari dependensi yang diberikan
+func New(deps Dependencies) *Handler {
+	return &Handler{
+		users:          deps.Repositories.Users,
+		registrations:  deps.Repositories.Registrations,
+		events:         deps.Repositories.Events,
+		notes:          deps.Repositories.Notes,
+		interviewSlots: deps.Repositories.InterviewSlots,
+		scores:         deps.Repositories.Scores,
+		informations:   deps.Repositories.Informations,
+		divisions:      deps.Repositories.Divisions,
+		periods:        deps.Repositories.Periods,
+		tokens:         deps.Repositories.Tokens,
+		passwordResets: deps.Repositories.PasswordResets,
+		loginAttempts:  deps.Repositories.LoginAttempts,
+		storage:        deps.Storage,
+		notifier:       deps.Notifier,
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "rahasia1"
+
+func TestMain(m *testing.M) {
+	os.Setenv("PASETO_SECRET_KEY", "R4nd0mS3cr3tK3yF0rP4s3t0Appl1c4t")
+	os.Setenv("REQUIRE_EMAIL_VERIFICATION", "false")
+	config.GetConfig()
+	config.LoadDatabaseCredentials(map[string]string{})
+	os.Exit(m.Run())
+}
+
+// testEnv adalah Handler yang memakai repository, storage, dan notifier in-memory
+type testEnv struct {
+	h        *Handler
+	repos    *repository.Repositories
+	storage  *storage.MemoryStorage
+	notifier *notify.MemoryNotifier
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		repos:    repository.NewMemoryRepositories(),
+		storage:  storage.NewMemoryStorage(),
+		notifier: notify.NewMemoryNotifier(),
+	}
+	env.h = New(Dependencies{Repositories: env.repos, Storage: env.storage, Notifier: env.notifier})
+	return env
+}
+
+// createUser menyimpan user dengan password testPassword
+func (e *testEnv) createUser(t *testing.T, nim, role string) *model.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &model.User{
+		Name: "User " + nim, NIM: nim, Email: nim + "@example.com", PhoneNumber: "081234567890",
+		BirthPlace: "Bandung", BirthDate: "2004-02-01", Password: string(hash), Role: role,
+	}
+	if err := e.repos.Users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// createRegistration menyimpan pendaftaran milik user dengan status tertentu
+func (e *testEnv) createRegistration(t *testing.T, user *model.User, status string) *model.Registration {
+	t.Helper()
+	registration := &model.Registration{
+		UserID: user.ID, Division1: "Web", Division2: "Data", Motivation: "m", VisionMission: "v", Status: status,
+	}
+	if err := e.repos.Registrations.Create(context.Background(), registration); err != nil {
+		t.Fatal(err)
+	}
+	return registration
+}
+
+// openPeriod membuat periode rekrutmen aktif yang sedang dibuka beserta divisi Web dan Data
+func (e *testEnv) openPeriod(t *testing.T) *model.RecruitmentPeriod {
+	t.Helper()
+	ctx := context.Background()
+	for _, name := range []string{"Web", "Data"} {
+		if err := e.repos.Divisions.Create(ctx, &model.Division{Name: name, Active: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	period := &model.RecruitmentPeriod{
+		Name: "Open Recruitment", AcademicYear: "2026/2027", Status: model.PeriodStatusActive,
+		OpensAt: primitive.NewDateTimeFromTime(now.Add(-time.Hour)), ClosesAt: primitive.NewDateTimeFromTime(now.Add(time.Hour)),
+	}
+	if err := e.repos.Periods.Create(ctx, period); err != nil {
+		t.Fatal(err)
+	}
+	return period
+}
+
+// serve menjalankan handler pada pattern route chi. Jika actor tidak nil, request membawa token
+// milik actor dan melewati AuthMiddleware seperti pada router. Body berupa string dikirim apa adanya,
+// selain itu di-encode sebagai JSON.
+func (e *testEnv) serve(t *testing.T, method, pattern, path string, fn http.HandlerFunc, actor *model.User, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	return e.serveRequest(t, pattern, req, fn, actor)
+}
+
+// serveRequest seperti serve, untuk request yang sudah disusun sendiri (misal multipart)
+func (e *testEnv) serveRequest(t *testing.T, pattern string, req *http.Request, fn http.HandlerFunc, actor *model.User) *httptest.ResponseRecorder {
+	t.Helper()
+	var h http.Handler = fn
+	if actor != nil {
+		token, _, err := auth.GenerateToken(actor.ID, actor.NIM, actor.Role, actor.TokenVersion)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		h = middleware.AuthMiddleware(e.repos.Tokens, e.repos.Users)(h)
+	}
+
+	router := chi.NewRouter()
+	router.Method(req.Method, pattern, h)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// errorCode mengambil kode error dari envelope respons
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) response.Code {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code response.Code `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("respons bukan JSON: %s", rec.Body.String())
+	}
+	return body.Error.Code
+}
+
+// decode membaca body respons JSON ke v
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("respons bukan JSON: %v: %s", err, rec.Body.String())
+	}
+}
+
+// hasFieldError mengecek apakah respons validasi memuat kesalahan untuk field tertentu
+func hasFieldError(t *testing.T, rec *httptest.ResponseRecorder, field string) bool {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Details []struct {
+				Field string `json:"field"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	decode(t, rec, &body)
+	for _, detail := range body.Error.Details {
+		if detail.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUser(t, "714220001", "user")
+	register := middleware.RequestIDMiddleware(http.HandlerFunc(env.h.RegisterHandler))
+
+	cases := []struct {
+		name      string
+		requestID string
+		body      map[string]interface{}
+		status    int
+		code      response.Code
+	}{
+		{name: "NIM sudah terdaftar", requestID: "req-123", body: registerBody("714220001"), status: http.StatusConflict, code: response.CodeNIMAlreadyRegistered},
+		// Request ID dari client yang tidak valid diganti dengan ID baru
+		{name: "validasi", requestID: "bukan id valid!", body: map[string]interface{}{"nim": "x"}, status: http.StatusUnprocessableEntity, code: response.CodeValidationFailed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest("POST", "/register", bytes.NewReader(raw))
+			req.Header.Set(middleware.RequestIDHeader, tc.requestID)
+			rec := httptest.NewRecorder()
+			register.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("status %d, ingin %d: %s", rec.Code, tc.status, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+				t.Errorf("Content-Type %q, ingin JSON", ct)
+			}
+			var body struct {
+				Error struct {
+					Code    response.Code `json:"code"`
+					Message string        `json:"message"`
+					Details []struct {
+						Field string `json:"field"`
+						Code  string `json:"code"`
+					} `json:"details"`
+					RequestID string `json:"request_id"`
+				} `json:"error"`
+			}
+			decode(t, rec, &body)
+			if body.Error.Code != tc.code || body.Error.Message == "" {
+				t.Errorf("envelope %+v, ingin kode %s beserta pesan", body.Error, tc.code)
+			}
+			header := rec.Header().Get(middleware.RequestIDHeader)
+			if body.Error.RequestID == "" || body.Error.RequestID != header {
+				t.Errorf("request_id %q tidak sama dengan header %q", body.Error.RequestID, header)
+			}
+			if tc.code == response.CodeValidationFailed {
+				if header == tc.requestID {
+					t.Errorf("request ID tidak valid dari client dipakai ulang: %q", header)
+				}
+				if len(body.Error.Details) == 0 || body.Error.Details[0].Field == "" || body.Error.Details[0].Code == "" {
+					t.Errorf("detail validasi tidak lengkap: %s", rec.Body.String())
+				}
+			} else if header != tc.requestID {
+				t.Errorf("request ID %q, ingin %q dari client", header, tc.requestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	maxImportFileSize = 2 << 20 // 2MB
+	// maxImportRows dibatasi karena setiap baris memerlukan hash bcrypt
+	maxImportRows           = 500
+	temporaryPasswordLength = 10
+)
+
+// Kolom berkas impor, sama dengan nama field JSON pada RegisterHandler dan form pendaftaran
+var (
+	importUserColumns         = []string{"name", "nim", "birth_place", "birth_date", "email", "phone_number"}
+	importRegistrationColumns = []string{"division1", "division2", "motivation", "vision_mission"}
+)
+
+// importRow adalah satu baris berkas impor yang sudah divalidasi
+type importRow struct {
+	Line         int                 `json:"line"`
+	Name         string              `json:"name"`
+	NIM          string              `json:"nim"`
+	Registration bool                `json:"registration"`
+	Status       string              `json:"status,omitempty"`
+	UserID       *primitive.ObjectID `json:"user_id,omitempty"`
+	// TemporaryPassword hanya ditampilkan sekali, saat impor benar-benar dijalankan
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+
+	user           model.User
+	registration   model.Registration
+	registrationID *primitive.ObjectID // pendaftaran yang sudah tersimpan, untuk rollback
+}
+
+// ImportUsersHandler mengimpor pendaftar dari berkas CSV (Super admin only).
+// Baris pertama adalah judul kolom; kolom registrasi bersifat opsional. Setiap baris divalidasi
+// seperti RegisterHandler. Dengan dry_run=true hanya hasil validasi per baris yang dikembalikan;
+// tanpa dry_run, tidak ada yang disimpan jika masih ada baris yang salah. Jika penyimpanan gagal
+// di tengah jalan, baris yang sudah tersimpan dihapus kembali.
+func (h *Handler) ImportUsersHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+1<<20)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodePayloadTooLarge, "Ukuran berkas melebihi batas")
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var errs validation.Errors
+		errs.Add("file", "FILE_REQUIRED", "Berkas CSV wajib diunggah")
+		writeValidationErrors(w, r, errs)
+		return
+	}
+	defer file.Close()
+	if header.Size > maxImportFileSize {
+		response.Error(w, r, http.StatusBadRequest, response.CodePayloadTooLarge, "Ukuran berkas melebihi 2MB")
+		return
+	}
+
+	records, errs := readImportCSV(file)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+	columns, ignored, errs := importColumns(records[0])
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	ctx := r.Context()
+	withRegistrations := columns["division1"] >= 0
+	var period *model.RecruitmentPeriod
+	if withRegistrations {
+		if period, ok = h.importPeriod(w, r); !ok {
+			return
+		}
+	}
+
+	rows := make([]importRow, 0, len(records)-1)
+	errs = nil
+	seen := make(map[string]int)
+	for i, record := range records[1:] {
+		line := i + 2
+		value := func(column string) string {
+			if index := columns[column]; index >= 0 && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		row := importRow{Line: line}
+		row.user = model.User{
+			Name:        value("name"),
+			NIM:         value("nim"),
+			BirthPlace:  value("birth_place"),
+			BirthDate:   value("birth_date"),
+			Email:       value("email"),
+			PhoneNumber: value("phone_number"),
+			Role:        "user",
+		}
+		row.Name, row.NIM = row.user.Name, row.user.NIM
+
+		var rowErrs validation.Errors
+		rowErrs = append(rowErrs, validateNewUser(&row.user)...)
+		if !rowErrs.Has("nim") {
+			if first, ok := seen[row.NIM]; ok {
+				rowErrs.Add("nim", "DUPLICATE_NIM", fmt.Sprintf("NIM sama dengan baris %d", first))
+			} else {
+				seen[row.NIM] = line
+				exists, err := h.users.ExistsByNIM(ctx, row.NIM, primitive.NilObjectID)
+				if err != nil {
+					response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa NIM")
+					return
+				}
+				if exists {
+					rowErrs.Add("nim", string(response.CodeNIMAlreadyRegistered), "NIM sudah terdaftar")
+				}
+			}
+		}
+
+		// Baris tanpa isian pendaftaran sama sekali hanya membuat akun
+		if withRegistrations && (value("division1") != "" || value("division2") != "" || value("motivation") != "" || value("vision_mission") != "") {
+			row.Registration = true
+			row.registration = model.Registration{
+				PeriodID:      period.ID,
+				Division1:     value("division1"),
+				Division2:     value("division2"),
+				Motivation:    value("motivation"),
+				VisionMission: value("vision_mission"),
+				Status:        value("status"),
+			}
+			if row.registration.Status == "" {
+				row.registration.Status = model.RegistrationStatusPending
+			}
+			regErrs, err := h.validateRegistration(ctx, &row.registration)
+			if err != nil {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa pilihan divisi")
+				return
+			}
+			if _, ok := model.FindRegistrationStatus(row.registration.Status); !ok {
+				regErrs.Add("status", validation.CodeInvalidOption, "Status "+row.registration.Status+" tidak dikenal")
+			}
+			rowErrs = append(rowErrs, regErrs...)
+			row.Status = row.registration.Status
+		}
+
+		for _, fieldErr := range rowErrs {
+			errs.Add(fmt.Sprintf("rows[%d].%s", line, fieldErr.Field), fieldErr.Code, fmt.Sprintf("Baris %d: %s", line, fieldErr.Message))
+		}
+		rows = append(rows, row)
+	}
+
+	result := map[string]interface{}{
+		"dry_run":         dryRun,
+		"total":           len(rows),
+		"ignored_columns": ignored,
+	}
+	if dryRun {
+		if errs == nil {
+			errs = validation.Errors{}
+		}
+		result["valid"] = len(errs) == 0
+		result["errors"] = errs
+		result["rows"] = rows
+		response.JSON(w, http.StatusOK, result)
+		return
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if err := h.createImportedUser(ctx, actor, row); err != nil {
+			log.Printf("Gagal mengimpor baris %d (NIM %s): %v", row.Line, row.NIM, err)
+			// Baris yang gagal bisa saja sudah menyimpan akunnya, jadi ikut di-rollback
+			remaining := h.rollbackImport(ctx, rows[:i+1])
+			if len(remaining) == 0 {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError,
+					fmt.Sprintf("Gagal mengimpor baris %d; tidak ada baris yang disimpan", row.Line))
+				return
+			}
+			// Akun yang gagal dihapus tetap bisa dipakai, jadi password sementaranya harus ikut dikembalikan
+			response.ErrorWithDetails(w, r, http.StatusInternalServerError, response.CodeInternalError,
+				fmt.Sprintf("Gagal mengimpor baris %d; %d baris tidak bisa dibatalkan dan tetap tersimpan", row.Line, len(remaining)),
+				map[string]interface{}{"rows": remaining})
+			return
+		}
+	}
+
+	// Email verifikasi baru dikirim setelah semua baris tersimpan agar tidak ada email untuk akun yang di-rollback
+	for i := range rows {
+		user := rows[i].user
+		user.ID = *rows[i].UserID
+		if err := h.sendVerificationEmail(ctx, &user); err != nil {
+			log.Printf("failed to send verification email for NIM %s: %v", user.NIM, err)
+		}
+	}
+
+	result["imported"] = len(rows)
+	result["rows"] = rows
+	response.JSON(w, http.StatusCreated, result)
+}
+
+// createImportedUser menyimpan user (dan pendaftarannya) dari satu baris yang sudah valid
+func (h *Handler) createImportedUser(ctx context.Context, actor *auth.PasetoPayload, row *importRow) error {
+	password, err := auth.GenerateTemporaryPassword(temporaryPasswordLength)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user := row.user
+	user.Password = string(hashedPassword)
+	if err := h.users.Create(ctx, &user); err != nil {
+		return err
+	}
+	row.UserID = &user.ID
+	row.TemporaryPassword = password
+
+	if !row.Registration {
+		return nil
+	}
+	registration := row.registration
+	registration.UserID = user.ID
+	registration.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	if err := h.registrations.Create(ctx, &registration); err != nil {
+		return err
+	}
+	row.registrationID = &registration.ID
+
+	event := newRegistrationEvent(actor, registration.ID, model.RegistrationEventImported, registration.UpdatedAt)
+	event.NewStatus = registration.Status
+	h.recordEvents(ctx, event)
+	return nil
+}
+
+// rollbackImport menghapus akun dan pendaftaran yang sudah tersimpan dari rows.
+// Baris yang akunnya gagal dihapus dikembalikan beserta password sementaranya.
+func (h *Handler) rollbackImport(ctx context.Context, rows []importRow) []importRow {
+	var remaining []importRow
+	for _, row := range rows {
+		if row.registrationID != nil {
+			if err := h.registrations.Delete(ctx, *row.registrationID); err != nil && err != repository.ErrNotFound {
+				log.Printf("failed to roll back imported registration of NIM %s: %v", row.NIM, err)
+				remaining = append(remaining, row)
+				continue
+			}
+			if err := h.events.DeleteByRegistration(ctx, *row.registrationID); err != nil {
+				log.Printf("failed to delete history of rolled back registration %s: %v", row.registrationID.Hex(), err)
+			}
+		}
+		if row.UserID != nil {
+			if err := h.users.Delete(ctx, *row.UserID); err != nil && err != repository.ErrNotFound {
+				log.Printf("failed to roll back imported user NIM %s: %v", row.NIM, err)
+				remaining = append(remaining, row)
+			}
+		}
+	}
+	return remaining
+}
+
+// importPeriod menentukan periode untuk pendaftaran hasil impor: query period, atau periode saat ini.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) importPeriod(w http.ResponseWriter, r *http.Request) (*model.RecruitmentPeriod, bool) {
+	var period *model.RecruitmentPeriod
+	var err error
+	if value := r.URL.Query().Get("period"); value != "" {
+		periodID, parseErr := primitive.ObjectIDFromHex(value)
+		if parseErr != nil {
+			response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID periode tidak valid")
+			return nil, false
+		}
+		period, err = h.periods.FindByID(r.Context(), periodID)
+	} else {
+		period, err = h.currentPeriod(r.Context())
+	}
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodePeriodNotFound, "Periode rekrutmen tidak ditemukan")
+		return nil, false
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil periode rekrutmen")
+		return nil, false
+	}
+	return period, true
+}
+
+// readImportCSV membaca seluruh baris berkas impor. Pemisah titik koma (ekspor Excel berbahasa
+// Indonesia) dikenali dari baris judul; BOM UTF-8 di awal berkas diabaikan.
+func readImportCSV(file io.Reader) ([][]string, validation.Errors) {
+	var errs validation.Errors
+	data, err := io.ReadAll(file)
+	if err != nil {
+		errs.Add("file", "INVALID_CSV", "Gagal membaca berkas CSV")
+		return nil, errs
+	}
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	if header, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		csvReader.Comma = ';'
+	}
+	csvReader.FieldsPerRecord = -1
+
+	var records [][]string
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs.Add("file", "INVALID_CSV", "Berkas CSV tidak valid: "+err.Error())
+			return nil, errs
+		}
+		// Baris kosong (misal di akhir berkas hasil ekspor spreadsheet) dilewati
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	switch {
+	case len(records) < 2:
+		errs.Add("file", "EMPTY_FILE", "Berkas CSV harus berisi baris judul dan minimal satu baris data")
+	case len(records)-1 > maxImportRows:
+		errs.Add("file", "TOO_MANY_ROWS", fmt.Sprintf("Berkas CSV maksimal berisi %d baris data", maxImportRows))
+	}
+	return records, errs
+}
+
+// importColumns memetakan nama kolom ke indeksnya (-1 jika tidak ada) dan mengembalikan kolom yang diabaikan
+func importColumns(header []string) (map[string]int, []string, validation.Errors) {
+	var errs validation.Errors
+	columns := make(map[string]int)
+	for _, name := range append(append(append([]string{}, importUserColumns...), importRegistrationColumns...), "status") {
+		columns[name] = -1
+	}
+
+	ignored := []string{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		index, known := columns[name]
+		switch {
+		case !known:
+			ignored = append(ignored, name)
+		case index >= 0:
+			errs.Add("file", "DUPLICATE_COLUMN", "Kolom "+name+" muncul lebih dari sekali")
+		default:
+			columns[name] = i
+		}
+	}
+
+	for _, name := range importUserColumns {
+		if columns[name] < 0 {
+			errs.Add("file", "MISSING_COLUMN", "Kolom "+name+" wajib ada")
+		}
+	}
+	// Kolom pendaftaran harus lengkap jika salah satunya dipakai
+	present := 0
+	for _, name := range importRegistrationColumns {
+		if columns[name] >= 0 {
+			present++
+		}
+	}
+	if present > 0 && present < len(importRegistrationColumns) {
+		errs.Add("file", "MISSING_COLUMN", "Kolom pendaftaran harus lengkap: "+strings.Join(importRegistrationColumns, ", "))
+	}
+	if columns["status"] >= 0 && present == 0 {
+		errs.Add("file", "MISSING_COLUMN", "Kolom status hanya dipakai bersama kolom pendaftaran")
+	}
+	return columns, ignored, errs
+}
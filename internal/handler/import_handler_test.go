@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const importCSV = `name,nim,birth_place,birth_date,email,phone_number,division1,division2,motivation,vision_mission
+Budi,714220001,Bandung,2004-02-01,budi@example.com,081234567890,Web,Data,Belajar,Berkontribusi
+Sari,714220002,Bandung,2004-03-01,sari@example.com,081234567891,Data,Web,Belajar,Berkontribusi
+Andi,714220003,Bandung,2004-04-01,andi@example.com,081234567892,Web,Data,Belajar,Berkontribusi
+`
+
+func (e *testEnv) importCSV(t *testing.T, actor *model.User, csv string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "pendaftar.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(csv))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/admin/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return e.serveRequest(t, "/api/admin/import", req, e.h.ImportUsersHandler, actor)
+}
+
+// failingRegistrations menolak Create ke-failAt dan mencatat ID pendaftaran yang berhasil dibuat
+type failingRegistrations struct {
+	repository.RegistrationRepository
+	failAt  int
+	creates int
+	created []primitive.ObjectID
+}
+
+func (r *failingRegistrations) Create(ctx context.Context, registration *model.Registration) error {
+	r.creates++
+	if r.creates == r.failAt {
+		return errors.New("database tidak tersedia")
+	}
+	err := r.RegistrationRepository.Create(ctx, registration)
+	if err == nil {
+		r.created = append(r.created, registration.ID)
+	}
+	return err
+}
+
+func TestImportUsersHandler(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	superAdmin := env.createUser(t, "900001", "super_admin")
+
+	rec := env.importCSV(t, superAdmin, importCSV)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Imported int         `json:"imported"`
+		Rows     []importRow `json:"rows"`
+	}
+	decode(t, rec, &body)
+	if body.Imported != 3 || len(body.Rows) != 3 || body.Rows[0].TemporaryPassword == "" {
+		t.Errorf("hasil impor tidak lengkap: %s", rec.Body.String())
+	}
+	if sent := env.notifier.Messages(); len(sent) != 3 {
+		t.Errorf("email verifikasi terkirim %d, ingin 3", len(sent))
+	}
+}
+
+func TestImportUsersHandlerRollsBackOnFailure(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	superAdmin := env.createUser(t, "900001", "super_admin")
+	registrations := &failingRegistrations{RegistrationRepository: env.repos.Registrations, failAt: 2}
+	env.repos.Registrations = registrations
+	env.h = New(Dependencies{Repositories: env.repos, Storage: env.storage, Notifier: env.notifier})
+
+	rec := env.importCSV(t, superAdmin, importCSV)
+	if rec.Code != http.StatusInternalServerError || errorCode(t, rec) != response.CodeInternalError {
+		t.Fatalf("status %d %s, ingin 500: %s", rec.Code, errorCode(t, rec), rec.Body.String())
+	}
+
+	ctx := context.Background()
+	users, _ := env.repos.Users.List(ctx)
+	if len(users) != 1 {
+		t.Errorf("jumlah user %d setelah rollback, ingin 1 (super admin saja)", len(users))
+	}
+	for _, nim := range []string{"714220001", "714220002"} {
+		if _, err := env.repos.Users.FindByNIM(ctx, nim); err != repository.ErrNotFound {
+			t.Errorf("akun %s masih tersimpan setelah rollback", nim)
+		}
+	}
+	if len(registrations.created) != 1 {
+		t.Fatalf("pendaftaran yang sempat dibuat %d, ingin 1", len(registrations.created))
+	}
+	if left, _ := env.repos.Registrations.FindByIDs(ctx, registrations.created); len(left) != 0 {
+		t.Errorf("pendaftaran masih tersimpan setelah rollback: %+v", left)
+	}
+	if events, _ := env.repos.Events.ListByRegistration(ctx, registrations.created[0]); len(events) != 0 {
+		t.Errorf("riwayat pendaftaran yang di-rollback masih ada: %+v", events)
+	}
+	if sent := env.notifier.Messages(); len(sent) != 0 {
+		t.Errorf("email verifikasi terkirim untuk akun yang di-rollback: %d", len(sent))
+	}
+}
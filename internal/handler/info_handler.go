@@ -3,27 +3,28 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/ulbithebest/BE-pendaftaran/internal/config"
 	"github.com/ulbithebest/BE-pendaftaran/internal/model"
 	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var infoCollection = "informations"
-
 // CreateInfoHandler (Admin only)
-func CreateInfoHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) CreateInfoHandler(w http.ResponseWriter, r *http.Request) {
 	var info model.Information
 	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	if errs := validation.Struct(&info); len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
 		return
 	}
 
@@ -32,101 +33,75 @@ func CreateInfoHandler(w http.ResponseWriter, r *http.Request) {
 	info.CreatedAt = now
 	info.UpdatedAt = now
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection(infoCollection)
-	_, err := collection.InsertOne(context.TODO(), info)
-	if err != nil {
-		http.Error(w, `{"error": "Failed to create information"}`, http.StatusInternalServerError)
+	if err := h.informations.Create(r.Context(), &info); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat informasi")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(info)
+	response.JSON(w, http.StatusCreated, info)
 }
 
 // GetAllInfoHandler (Untuk semua user yang login)
-func GetAllInfoHandler(w http.ResponseWriter, r *http.Request) {
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection(infoCollection)
-
-	// Urutkan berdasarkan yang terbaru
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-
-	cursor, err := collection.Find(context.TODO(), bson.D{}, opts)
+func (h *Handler) GetAllInfoHandler(w http.ResponseWriter, r *http.Request) {
+	// Diurutkan berdasarkan yang terbaru
+	results, err := h.informations.List(r.Context())
 	if err != nil {
-		http.Error(w, `{"error": "Failed to fetch information"}`, http.StatusInternalServerError)
-		return
-	}
-	defer cursor.Close(context.TODO())
-
-	var results []model.Information
-	if err = cursor.All(context.TODO(), &results); err != nil {
-		http.Error(w, `{"error": "Failed to decode information"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil informasi")
 		return
 	}
-	if results == nil {
-		results = []model.Information{}
-	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	response.JSON(w, http.StatusOK, results)
 }
 
 // UpdateInfoHandler (Admin only)
-func UpdateInfoHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) UpdateInfoHandler(w http.ResponseWriter, r *http.Request) {
 	infoID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Invalid information ID"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID informasi tidak valid")
 		return
 	}
 
-	var payload struct {
-		Title   string `json:"title"`
-		Content string `json:"content"`
-	}
+	var payload model.Information
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"title":      payload.Title,
-			"content":    payload.Content,
-			"updated_at": primitive.NewDateTimeFromTime(time.Now()),
-		},
+	if errs := validation.Struct(&payload); len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection(infoCollection)
-	_, err = collection.UpdateOne(context.TODO(), bson.M{"_id": infoID}, update)
+	err = h.informations.Update(r.Context(), infoID, payload.Title, payload.Content, time.Now())
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeInformationNotFound, "Informasi tidak ditemukan")
+		return
+	}
 	if err != nil {
-		http.Error(w, `{"error": "Failed to update information"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui informasi")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Information updated successfully"})
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Information updated successfully"})
 }
 
 // DeleteInfoHandler (Admin only)
-func DeleteInfoHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) DeleteInfoHandler(w http.ResponseWriter, r *http.Request) {
 	infoID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
 	if err != nil {
-		http.Error(w, `{"error": "Invalid information ID"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID informasi tidak valid")
 		return
 	}
 
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection(infoCollection)
-	result, err := collection.DeleteOne(context.TODO(), bson.M{"_id": infoID})
-	if err != nil {
-		http.Error(w, `{"error": "Failed to delete information"}`, http.StatusInternalServerError)
+	err = h.informations.Delete(r.Context(), infoID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeInformationNotFound, "Informasi tidak ditemukan")
 		return
 	}
-
-	if result.DeletedCount == 0 {
-		http.Error(w, `{"error": "Information not found"}`, http.StatusNotFound)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus informasi")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Information deleted successfully"})
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Information deleted successfully"})
 }
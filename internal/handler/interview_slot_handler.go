@@ -0,0 +1,647 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// localTimeZone (WIB) dipakai saat menulis jadwal wawancara sebagai teks dan membaca tanggal tanpa zona waktu
+var localTimeZone = time.FixedZone("WIB", 7*60*60)
+
+// maxGeneratedSlots membatasi jumlah slot yang dibuat dalam satu kali generate
+const maxGeneratedSlots = 200
+
+// slotSchedule menulis waktu slot sebagai teks, misal "02 Jan 2026 09:00 - 09:30 WIB"
+func slotSchedule(slot *model.InterviewSlot) string {
+	start := slot.StartsAt.Time().In(localTimeZone)
+	end := slot.EndsAt.Time().In(localTimeZone)
+	return start.Format("02 Jan 2006 15:04") + " - " + end.Format("15:04 MST")
+}
+
+// slotLocation menggabungkan lokasi dan tautan online slot menjadi satu teks
+func slotLocation(slot *model.InterviewSlot) string {
+	switch {
+	case slot.Location != "" && slot.MeetingURL != "":
+		return slot.Location + " (" + slot.MeetingURL + ")"
+	case slot.Location != "":
+		return slot.Location
+	default:
+		return slot.MeetingURL
+	}
+}
+
+// releasesInterview mengecek apakah status berarti pendaftar tidak lagi menunggu wawancara,
+// sehingga jadwal dan kursi slotnya dilepas. Status accepted/rejected tetap menyimpan jadwal sebagai arsip.
+func releasesInterview(status string) bool {
+	switch status {
+	case model.RegistrationStatusPending, model.RegistrationStatusDocumentReview, model.RegistrationStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// validateSlotTimes memastikan rentang waktu terisi dan berurutan
+func validateSlotTimes(errs *validation.Errors, startsAt, endsAt primitive.DateTime) {
+	if startsAt == 0 {
+		errs.Add("starts_at", validation.CodeRequired, "Waktu mulai wajib diisi")
+	}
+	if endsAt == 0 {
+		errs.Add("ends_at", validation.CodeRequired, "Waktu selesai wajib diisi")
+	}
+	if startsAt != 0 && endsAt != 0 && endsAt <= startsAt {
+		errs.Add("ends_at", "INVALID_RANGE", "Waktu selesai harus setelah waktu mulai")
+	}
+}
+
+// validateSlotDetails memvalidasi tempat, kapasitas, dan pewawancara slot
+func (h *Handler) validateSlotDetails(ctx context.Context, errs *validation.Errors, slot *model.InterviewSlot) error {
+	*errs = append(*errs, validation.Struct(slot)...)
+	if slot.Location == "" && slot.MeetingURL == "" {
+		errs.Add("location", "LOCATION_REQUIRED", "Isi lokasi atau tautan wawancara online")
+	}
+	if slot.Interviewers == nil {
+		slot.Interviewers = []primitive.ObjectID{}
+	}
+	return h.checkAdmins(ctx, errs, "interviewers", "INVALID_INTERVIEWER", "Pewawancara", slot.Interviewers)
+}
+
+func writeInterviewerConflicts(w http.ResponseWriter, r *http.Request, conflicts []model.InterviewSlot) {
+	type conflict struct {
+		ID           primitive.ObjectID   `json:"id"`
+		StartsAt     primitive.DateTime   `json:"starts_at"`
+		EndsAt       primitive.DateTime   `json:"ends_at"`
+		Interviewers []primitive.ObjectID `json:"interviewers"`
+	}
+	details := make([]conflict, len(conflicts))
+	for i, slot := range conflicts {
+		details[i] = conflict{ID: slot.ID, StartsAt: slot.StartsAt, EndsAt: slot.EndsAt, Interviewers: slot.Interviewers}
+	}
+	response.ErrorWithDetails(w, r, http.StatusConflict, response.CodeInterviewerConflict,
+		"Pewawancara sudah memiliki jadwal lain pada waktu yang sama",
+		map[string]interface{}{"slots": details})
+}
+
+// GetInterviewSlotsHandler mengembalikan slot wawancara pada periode saat ini,
+// atau periode lain lewat query ?period=<id> (?period=all untuk semua periode)
+func (h *Handler) GetInterviewSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	periodID, ok := h.periodFilter(w, r)
+	if !ok {
+		return
+	}
+
+	slots, err := h.interviewSlots.List(r.Context(), repository.InterviewSlotFilter{PeriodID: periodID})
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, slots)
+}
+
+// GenerateInterviewSlotsHandler membuat slot berurutan dengan durasi dan jeda tetap
+// di antara starts_at dan ends_at (Admin only)
+func (h *Handler) GenerateInterviewSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	body := struct {
+		PeriodID        primitive.ObjectID   `json:"period_id"` // kosong berarti periode saat ini
+		StartsAt        primitive.DateTime   `json:"starts_at"`
+		EndsAt          primitive.DateTime   `json:"ends_at"`
+		DurationMinutes int                  `json:"duration_minutes" validate:"min=5,max=480" label:"Durasi"`
+		BreakMinutes    int                  `json:"break_minutes" validate:"min=0,max=240" label:"Jeda"`
+		Location        string               `json:"location"`
+		MeetingURL      string               `json:"meeting_url"`
+		Interviewers    []primitive.ObjectID `json:"interviewers"`
+		Capacity        int                  `json:"capacity"`
+	}{Capacity: 1}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	var period *model.RecruitmentPeriod
+	var err error
+	if body.PeriodID.IsZero() {
+		period, err = h.currentPeriod(ctx)
+	} else {
+		period, err = h.periods.FindByID(ctx, body.PeriodID)
+	}
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodePeriodNotFound, "Periode rekrutmen tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil periode rekrutmen")
+		return
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	template := model.InterviewSlot{
+		PeriodID:     period.ID,
+		Location:     strings.TrimSpace(body.Location),
+		MeetingURL:   strings.TrimSpace(body.MeetingURL),
+		Interviewers: body.Interviewers,
+		Capacity:     body.Capacity,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	errs := validation.Struct(&body)
+	validateSlotTimes(&errs, body.StartsAt, body.EndsAt)
+	if err := h.validateSlotDetails(ctx, &errs, &template); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa pewawancara")
+		return
+	}
+
+	var slots []model.InterviewSlot
+	if len(errs) == 0 {
+		duration := time.Duration(body.DurationMinutes) * time.Minute
+		step := duration + time.Duration(body.BreakMinutes)*time.Minute
+		for start := body.StartsAt.Time(); !start.Add(duration).After(body.EndsAt.Time()); start = start.Add(step) {
+			if len(slots) == maxGeneratedSlots {
+				errs.Add("ends_at", "TOO_MANY_SLOTS", fmt.Sprintf("Paling banyak %d slot dalam satu kali generate", maxGeneratedSlots))
+				break
+			}
+			slot := template
+			slot.StartsAt = primitive.NewDateTimeFromTime(start)
+			slot.EndsAt = primitive.NewDateTimeFromTime(start.Add(duration))
+			slots = append(slots, slot)
+		}
+		if len(slots) == 0 {
+			errs.Add("ends_at", "INVALID_RANGE", "Rentang waktu terlalu pendek untuk satu slot")
+		}
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	// Ambil semua jadwal pewawancara di rentang ini sekaligus, lalu cocokkan per slot
+	existing, err := h.interviewSlots.FindInterviewerConflicts(ctx, template.Interviewers, body.StartsAt.Time(), body.EndsAt.Time(), primitive.NilObjectID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa jadwal pewawancara")
+		return
+	}
+	var conflicts []model.InterviewSlot
+	for _, other := range existing {
+		for _, slot := range slots {
+			if other.StartsAt < slot.EndsAt && other.EndsAt > slot.StartsAt {
+				conflicts = append(conflicts, other)
+				break
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		writeInterviewerConflicts(w, r, conflicts)
+		return
+	}
+
+	if err := h.interviewSlots.CreateMany(ctx, slots); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan slot wawancara")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, slots)
+}
+
+// UpdateInterviewSlotHandler memperbarui waktu, tempat, pewawancara, atau kapasitas slot (Admin only).
+// Jadwal pada pendaftaran yang sudah memesan slot ikut diperbarui.
+func (h *Handler) UpdateInterviewSlotHandler(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.slotFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	slot := *existing
+	if err := json.NewDecoder(r.Body).Decode(&slot); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	slot.ID = existing.ID
+	slot.PeriodID = existing.PeriodID
+	slot.RegistrationIDs = existing.RegistrationIDs
+	slot.Booked = existing.Booked
+	slot.Location = strings.TrimSpace(slot.Location)
+	slot.MeetingURL = strings.TrimSpace(slot.MeetingURL)
+	slot.CreatedAt = existing.CreatedAt
+	slot.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	ctx := r.Context()
+	var errs validation.Errors
+	validateSlotTimes(&errs, slot.StartsAt, slot.EndsAt)
+	if err := h.validateSlotDetails(ctx, &errs, &slot); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa pewawancara")
+		return
+	}
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	if slot.Capacity < len(slot.RegistrationIDs) {
+		response.ErrorWithDetails(w, r, http.StatusConflict, response.CodeSlotInUse,
+			"Kapasitas tidak boleh lebih kecil dari jumlah pendaftar yang sudah memesan slot",
+			map[string]int{"registrations": len(slot.RegistrationIDs)})
+		return
+	}
+
+	conflicts, err := h.interviewSlots.FindInterviewerConflicts(ctx, slot.Interviewers, slot.StartsAt.Time(), slot.EndsAt.Time(), slot.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa jadwal pewawancara")
+		return
+	}
+	if len(conflicts) > 0 {
+		writeInterviewerConflicts(w, r, conflicts)
+		return
+	}
+
+	err = h.interviewSlots.Update(ctx, &slot)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeSlotNotFound, "Slot wawancara tidak ditemukan")
+		return
+	}
+	if err == repository.ErrSlotInUse {
+		response.Error(w, r, http.StatusConflict, response.CodeSlotInUse,
+			"Kapasitas tidak boleh lebih kecil dari jumlah pendaftar yang sudah memesan slot")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui slot wawancara")
+		return
+	}
+
+	schedule, location := slotSchedule(&slot), slotLocation(&slot)
+	if schedule != slotSchedule(existing) || location != slotLocation(existing) {
+		for _, regID := range slot.RegistrationIDs {
+			if err := h.registrations.SetInterviewSlot(ctx, regID, &slot.ID, schedule, location, slot.UpdatedAt.Time()); err != nil && err != repository.ErrNotFound {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui jadwal pada pendaftaran")
+				return
+			}
+		}
+	}
+
+	response.JSON(w, http.StatusOK, slot)
+}
+
+// DeleteInterviewSlotHandler menghapus slot yang belum dipesan siapa pun (Admin only)
+func (h *Handler) DeleteInterviewSlotHandler(w http.ResponseWriter, r *http.Request) {
+	slot, ok := h.slotFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	if len(slot.RegistrationIDs) > 0 {
+		response.ErrorWithDetails(w, r, http.StatusConflict, response.CodeSlotInUse,
+			"Slot sudah dipesan pendaftar. Pindahkan pendaftar ke slot lain terlebih dahulu.",
+			map[string]int{"registrations": len(slot.RegistrationIDs)})
+		return
+	}
+
+	err := h.interviewSlots.Delete(r.Context(), slot.ID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeSlotNotFound, "Slot wawancara tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus slot wawancara")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Slot wawancara berhasil dihapus"})
+}
+
+// AssignInterviewSlotHandler memasukkan pendaftaran berstatus interview ke slot (Admin only).
+// Pendaftaran yang sudah memiliki slot lain harus dikeluarkan dari slot tersebut terlebih dahulu.
+func (h *Handler) AssignInterviewSlotHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	slot, ok := h.slotFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		RegistrationID primitive.ObjectID `json:"registration_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	registration, err := h.registrations.FindByID(ctx, body.RegistrationID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+
+	if registration.PeriodID != slot.PeriodID {
+		var errs validation.Errors
+		errs.Add("registration_id", "PERIOD_MISMATCH", "Pendaftaran dan slot berada pada periode yang berbeda")
+		writeValidationErrors(w, r, errs)
+		return
+	}
+	if registration.Status != model.RegistrationStatusInterview {
+		writeNotInInterviewStage(w, r)
+		return
+	}
+	if registration.InterviewSlotID != nil && *registration.InterviewSlotID != slot.ID {
+		response.ErrorWithDetails(w, r, http.StatusConflict, response.CodeAlreadyBooked,
+			"Pendaftar sudah memiliki jadwal wawancara di slot lain",
+			map[string]string{"slot_id": registration.InterviewSlotID.Hex()})
+		return
+	}
+
+	if !h.bookSlot(w, r, actor, registration, slot) {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message":            "Pendaftar berhasil dijadwalkan",
+		"interview_schedule": slotSchedule(slot),
+		"interview_location": slotLocation(slot),
+	})
+}
+
+// UnassignInterviewSlotHandler mengeluarkan pendaftaran dari slot (Admin only)
+func (h *Handler) UnassignInterviewSlotHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	slot, ok := h.slotFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "registrationId"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	registration, err := h.registrations.FindByID(ctx, regID)
+	if err != nil && err != repository.ErrNotFound {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+	if err == repository.ErrNotFound || registration.InterviewSlotID == nil || *registration.InterviewSlotID != slot.ID {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak terdaftar pada slot ini")
+		return
+	}
+
+	if err := h.clearInterviewSlot(ctx, registration); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal melepas jadwal wawancara")
+		return
+	}
+
+	event := newRegistrationEvent(actor, registration.ID, model.RegistrationEventInterviewChanged, primitive.NewDateTimeFromTime(time.Now()))
+	event.NewStatus = registration.Status
+	h.recordEvents(ctx, event)
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Pendaftar berhasil dikeluarkan dari slot"})
+}
+
+// applicantSlot adalah slot wawancara yang ditampilkan ke pendaftar, tanpa data pewawancara
+type applicantSlot struct {
+	ID         primitive.ObjectID `json:"id"`
+	StartsAt   primitive.DateTime `json:"starts_at"`
+	EndsAt     primitive.DateTime `json:"ends_at"`
+	Location   string             `json:"location,omitempty"`
+	MeetingURL string             `json:"meeting_url,omitempty"`
+	Remaining  int                `json:"remaining"`
+}
+
+// newApplicantSlot menyusun applicantSlot dari slot wawancara
+func newApplicantSlot(slot *model.InterviewSlot) applicantSlot {
+	return applicantSlot{
+		ID:         slot.ID,
+		StartsAt:   slot.StartsAt,
+		EndsAt:     slot.EndsAt,
+		Location:   slot.Location,
+		MeetingURL: slot.MeetingURL,
+		Remaining:  slot.Capacity - slot.Booked,
+	}
+}
+
+// GetAvailableInterviewSlotsHandler mengembalikan slot yang masih bisa dipilih pendaftar
+// berstatus interview pada periodenya
+func (h *Handler) GetAvailableInterviewSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	registration, ok := h.interviewRegistration(w, r)
+	if !ok {
+		return
+	}
+
+	slots, err := h.interviewSlots.List(r.Context(), repository.InterviewSlotFilter{
+		PeriodID:      registration.PeriodID,
+		StartsAfter:   time.Now(),
+		AvailableOnly: true,
+	})
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+		return
+	}
+
+	available := make([]applicantSlot, len(slots))
+	for i := range slots {
+		available[i] = newApplicantSlot(&slots[i])
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"booked_slot_id": registration.InterviewSlotID,
+		"slots":          available,
+	})
+}
+
+// BookInterviewSlotHandler memesan (atau memindahkan) jadwal wawancara pendaftar ke slot pilihannya.
+// Slot yang sudah dimulai tidak bisa dipilih maupun ditinggalkan.
+func (h *Handler) BookInterviewSlotHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	registration, ok := h.interviewRegistration(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		SlotID primitive.ObjectID `json:"slot_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+	slot, err := h.interviewSlots.FindByID(ctx, body.SlotID)
+	if err == repository.ErrNotFound || (err == nil && slot.PeriodID != registration.PeriodID) {
+		response.Error(w, r, http.StatusNotFound, response.CodeSlotNotFound, "Slot wawancara tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+		return
+	}
+	if !slot.StartsAt.Time().After(now) {
+		response.Error(w, r, http.StatusConflict, response.CodeSlotPassed, "Slot wawancara sudah lewat")
+		return
+	}
+
+	if registration.InterviewSlotID != nil && *registration.InterviewSlotID != slot.ID {
+		current, err := h.interviewSlots.FindByID(ctx, *registration.InterviewSlotID)
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+			return
+		}
+		if err == nil && !current.StartsAt.Time().After(now) {
+			response.Error(w, r, http.StatusConflict, response.CodeSlotPassed, "Jadwal wawancara Anda sudah berlangsung dan tidak bisa dipindahkan")
+			return
+		}
+	}
+
+	if !h.bookSlot(w, r, actor, registration, slot) {
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{
+		"message":            "Jadwal wawancara berhasil dipilih",
+		"interview_schedule": slotSchedule(slot),
+		"interview_location": slotLocation(slot),
+	})
+}
+
+// bookSlot memesan kursi pada slot, melepas slot lama pendaftaran, lalu menyalin jadwalnya
+// ke pendaftaran. Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) bookSlot(w http.ResponseWriter, r *http.Request, actor *auth.PasetoPayload, registration *model.Registration, slot *model.InterviewSlot) bool {
+	ctx := r.Context()
+	now := time.Now()
+
+	err := h.interviewSlots.Book(ctx, slot.ID, registration.ID, now)
+	if err == repository.ErrSlotFull {
+		response.Error(w, r, http.StatusConflict, response.CodeSlotFull, "Slot wawancara sudah penuh")
+		return false
+	}
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeSlotNotFound, "Slot wawancara tidak ditemukan")
+		return false
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memesan slot wawancara")
+		return false
+	}
+
+	if registration.InterviewSlotID != nil && *registration.InterviewSlotID != slot.ID {
+		if err := h.interviewSlots.Release(ctx, *registration.InterviewSlotID, registration.ID, now); err != nil {
+			log.Printf("failed to release slot %s for registration %s: %v", registration.InterviewSlotID.Hex(), registration.ID.Hex(), err)
+		}
+	}
+
+	schedule, location := slotSchedule(slot), slotLocation(slot)
+	if err := h.registrations.SetInterviewSlot(ctx, registration.ID, &slot.ID, schedule, location, now); err != nil {
+		// Kembalikan kursinya agar slot tidak terisi tanpa pendaftaran
+		if err := h.interviewSlots.Release(ctx, slot.ID, registration.ID, now); err != nil {
+			log.Printf("failed to release slot %s for registration %s: %v", slot.ID.Hex(), registration.ID.Hex(), err)
+		}
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan jadwal wawancara")
+		return false
+	}
+
+	if registration.InterviewSlotID == nil || *registration.InterviewSlotID != slot.ID {
+		event := newRegistrationEvent(actor, registration.ID, model.RegistrationEventInterviewChanged, primitive.NewDateTimeFromTime(now))
+		event.NewStatus = registration.Status
+		event.InterviewSchedule = schedule
+		event.InterviewLocation = location
+		h.recordEvents(ctx, event)
+	}
+	return true
+}
+
+// clearInterviewSlot melepas kursi slot pendaftaran (jika ada) dan mengosongkan jadwal wawancaranya
+func (h *Handler) clearInterviewSlot(ctx context.Context, registration *model.Registration) error {
+	now := time.Now()
+	if registration.InterviewSlotID != nil {
+		if err := h.interviewSlots.Release(ctx, *registration.InterviewSlotID, registration.ID, now); err != nil {
+			return err
+		}
+	}
+	return h.registrations.SetInterviewSlot(ctx, registration.ID, nil, "", "", now)
+}
+
+// slotFromURL mengambil slot wawancara berdasarkan parameter {id}.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) slotFromURL(w http.ResponseWriter, r *http.Request) (*model.InterviewSlot, bool) {
+	slotID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID slot tidak valid")
+		return nil, false
+	}
+
+	slot, err := h.interviewSlots.FindByID(r.Context(), slotID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeSlotNotFound, "Slot wawancara tidak ditemukan")
+		return nil, false
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+		return nil, false
+	}
+	return slot, true
+}
+
+// interviewRegistration mengambil pendaftaran user yang login dan memastikan statusnya interview.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) interviewRegistration(w http.ResponseWriter, r *http.Request) (*model.Registration, bool) {
+	payload, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return nil, false
+	}
+
+	registration, err := h.userRegistration(r.Context(), payload.UserID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Anda belum mengirim pendaftaran pada periode ini")
+		return nil, false
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return nil, false
+	}
+	if registration.Status != model.RegistrationStatusInterview {
+		writeNotInInterviewStage(w, r)
+		return nil, false
+	}
+	return registration, true
+}
+
+func writeNotInInterviewStage(w http.ResponseWriter, r *http.Request) {
+	response.Error(w, r, http.StatusConflict, response.CodeNotInInterviewStage, "Pendaftaran belum berada pada tahap wawancara")
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const bookSlotPath = "/api/user/interview-slot"
+
+// createSlot menyimpan slot wawancara pada periode yang dimulai startsIn dari sekarang
+func (e *testEnv) createSlot(t *testing.T, period *model.RecruitmentPeriod, startsIn time.Duration, capacity int) *model.InterviewSlot {
+	t.Helper()
+	startsAt := time.Now().Add(startsIn)
+	slots := []model.InterviewSlot{{
+		PeriodID: period.ID, StartsAt: primitive.NewDateTimeFromTime(startsAt), EndsAt: primitive.NewDateTimeFromTime(startsAt.Add(30 * time.Minute)),
+		Location: "Ruang 101", Capacity: capacity,
+	}}
+	if err := e.repos.InterviewSlots.CreateMany(context.Background(), slots); err != nil {
+		t.Fatal(err)
+	}
+	return &slots[0]
+}
+
+// createInterviewee menyimpan user beserta pendaftaran berstatus interview pada periode
+func (e *testEnv) createInterviewee(t *testing.T, period *model.RecruitmentPeriod, nim string) (*model.User, *model.Registration) {
+	t.Helper()
+	user := e.createUser(t, nim, "user")
+	registration := &model.Registration{
+		UserID: user.ID, PeriodID: period.ID, Division1: "Web", Division2: "Data", Motivation: "m", VisionMission: "v",
+		Status: model.RegistrationStatusInterview,
+	}
+	if err := e.repos.Registrations.Create(context.Background(), registration); err != nil {
+		t.Fatal(err)
+	}
+	return user, registration
+}
+
+func TestBookInterviewSlotHandler(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	slot := env.createSlot(t, period, 24*time.Hour, 2)
+	user, registration := env.createInterviewee(t, period, "714220001")
+
+	rec := env.serve(t, "PUT", bookSlotPath, bookSlotPath, env.h.BookInterviewSlotHandler, user, map[string]string{"slot_id": slot.ID.Hex()})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	ctx := context.Background()
+	stored, _ := env.repos.InterviewSlots.FindByID(ctx, slot.ID)
+	if stored.Booked != 1 || len(stored.RegistrationIDs) != 1 || stored.RegistrationIDs[0] != registration.ID {
+		t.Errorf("slot tidak mencatat pemesanan: %+v", stored)
+	}
+	updated, _ := env.repos.Registrations.FindByID(ctx, registration.ID)
+	if updated.InterviewSlotID == nil || *updated.InterviewSlotID != slot.ID || updated.InterviewLocation != "Ruang 101" {
+		t.Errorf("jadwal tidak disalin ke pendaftaran: %+v", updated)
+	}
+	events, _ := env.repos.Events.ListByRegistration(ctx, registration.ID)
+	if len(events) != 1 || events[0].Type != model.RegistrationEventInterviewChanged {
+		t.Errorf("riwayat jadwal tidak tercatat: %+v", events)
+	}
+}
+
+func TestBookInterviewSlotHandlerConcurrentLastSeat(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	slot := env.createSlot(t, period, 24*time.Hour, 1)
+
+	const applicants = 8
+	users := make([]*model.User, applicants)
+	for i := range users {
+		users[i], _ = env.createInterviewee(t, period, "71422000"+string(rune('1'+i)))
+	}
+
+	recs := make([]*httptest.ResponseRecorder, applicants)
+	var wg sync.WaitGroup
+	for i := range users {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recs[i] = env.serve(t, "PUT", bookSlotPath, bookSlotPath, env.h.BookInterviewSlotHandler, users[i], map[string]string{"slot_id": slot.ID.Hex()})
+		}(i)
+	}
+	wg.Wait()
+
+	booked := 0
+	for i, rec := range recs {
+		switch {
+		case rec.Code == http.StatusOK:
+			booked++
+		case rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeSlotFull:
+			t.Errorf("pendaftar %d: status %d %s, ingin 409 %s", i, rec.Code, errorCode(t, rec), response.CodeSlotFull)
+		}
+	}
+	if booked != 1 {
+		t.Errorf("%d pemesanan berhasil pada slot berkapasitas 1", booked)
+	}
+	stored, _ := env.repos.InterviewSlots.FindByID(context.Background(), slot.ID)
+	if stored.Booked != 1 || len(stored.RegistrationIDs) != 1 {
+		t.Errorf("slot terisi melebihi kapasitas: %+v", stored)
+	}
+}
+
+func TestBookInterviewSlotHandlerPassedSlot(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	slot := env.createSlot(t, period, -time.Hour, 1)
+	user, registration := env.createInterviewee(t, period, "714220001")
+
+	rec := env.serve(t, "PUT", bookSlotPath, bookSlotPath, env.h.BookInterviewSlotHandler, user, map[string]string{"slot_id": slot.ID.Hex()})
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeSlotPassed {
+		t.Fatalf("status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeSlotPassed)
+	}
+	if updated, _ := env.repos.Registrations.FindByID(context.Background(), registration.ID); updated.InterviewSlotID != nil {
+		t.Errorf("slot yang sudah lewat tetap tersimpan: %+v", updated)
+	}
+}
+
+func TestUpdateInterviewSlotHandlerCapacityBelowBooked(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	admin := env.createUser(t, "900001", "admin")
+	slot := env.createSlot(t, period, 24*time.Hour, 2)
+	ctx := context.Background()
+	for _, nim := range []string{"714220001", "714220002"} {
+		_, registration := env.createInterviewee(t, period, nim)
+		if err := env.repos.InterviewSlots.Book(ctx, slot.ID, registration.ID, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := "/api/admin/interview-slots/" + slot.ID.Hex()
+	rec := env.serve(t, "PUT", "/api/admin/interview-slots/{id}", path, env.h.UpdateInterviewSlotHandler, admin, map[string]interface{}{"capacity": 1})
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeSlotInUse {
+		t.Fatalf("status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeSlotInUse)
+	}
+	if stored, _ := env.repos.InterviewSlots.FindByID(ctx, slot.ID); stored.Capacity != 2 {
+		t.Errorf("kapasitas berubah menjadi %d", stored.Capacity)
+	}
+
+	// Repository juga menolak jika pemesanan masuk setelah handler mengecek jumlah pemesan
+	stale := *slot
+	stale.Capacity = 1
+	if err := env.repos.InterviewSlots.Update(ctx, &stale); err != repository.ErrSlotInUse {
+		t.Errorf("Update = %v, ingin %v", err, repository.ErrSlotInUse)
+	}
+}
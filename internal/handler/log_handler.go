@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/ulbithebest/BE-pendaftaran/internal/applog"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
 )
 
-func GetAppLogsHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(applog.List())
+func (h *Handler) GetAppLogsHandler(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, applog.List())
 }
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/applog"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Proteksi brute-force login:
+//   - per NIM: setiap kegagalan menambah jeda (exponential backoff) dan akun dikunci
+//     sementara setelah LOGIN_MAX_ATTEMPTS kegagalan berturut-turut.
+//   - per IP: hanya dikunci setelah LOGIN_MAX_ATTEMPTS_PER_IP kegagalan, tanpa backoff,
+//     karena banyak mahasiswa bisa berbagi IP yang sama (wifi kampus).
+
+func loginNIMKey(nim string) string { return "nim:" + nim }
+func loginIPKey(ip string) string   { return "ip:" + ip }
+
+// loginRetryAfter mengembalikan berapa lama lagi login untuk NIM/IP ini boleh dicoba (0 berarti boleh)
+func (h *Handler) loginRetryAfter(ctx context.Context, nim, ip string, now time.Time) (time.Duration, error) {
+	cfg := config.GetConfig()
+
+	nimWait, err := h.attemptWait(ctx, loginNIMKey(nim), cfg.LoginBackoffBase, now)
+	if err != nil {
+		return 0, err
+	}
+	ipWait, err := h.attemptWait(ctx, loginIPKey(ip), 0, now)
+	if err != nil {
+		return 0, err
+	}
+
+	if ipWait > nimWait {
+		return ipWait, nil
+	}
+	return nimWait, nil
+}
+
+func (h *Handler) attemptWait(ctx context.Context, key string, backoffBase time.Duration, now time.Time) (time.Duration, error) {
+	attempt, err := h.loginAttempts.Get(ctx, key)
+	if err == repository.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if lockedUntil := attempt.LockedUntil.Time(); lockedUntil.After(now) {
+		return lockedUntil.Sub(now), nil
+	}
+
+	if backoffBase <= 0 || attempt.Failures == 0 {
+		return 0, nil
+	}
+
+	next := attempt.LastFailureAt.Time().Add(loginBackoff(backoffBase, attempt.Failures))
+	if next.After(now) {
+		return next.Sub(now), nil
+	}
+	return 0, nil
+}
+
+// loginBackoff menghitung jeda base * 2^(failures-1), dibatasi maksimal sebesar durasi lockout
+func loginBackoff(base time.Duration, failures int) time.Duration {
+	limit := config.GetConfig().LoginLockoutDuration
+	backoff := base
+	for i := 1; i < failures; i++ {
+		backoff *= 2
+		if backoff >= limit {
+			return limit
+		}
+	}
+	return backoff
+}
+
+// recordLoginFailure mencatat login gagal untuk NIM dan IP, lalu mengunci jika batas terlampaui
+func (h *Handler) recordLoginFailure(ctx context.Context, nim, ip string, now time.Time) error {
+	cfg := config.GetConfig()
+	if err := h.recordAttemptFailure(ctx, loginNIMKey(nim), cfg.LoginMaxAttempts, nim, ip, now); err != nil {
+		return err
+	}
+	return h.recordAttemptFailure(ctx, loginIPKey(ip), cfg.LoginMaxAttemptsPerIP, nim, ip, now)
+}
+
+func (h *Handler) recordAttemptFailure(ctx context.Context, key string, maxAttempts int, nim, ip string, now time.Time) error {
+	cfg := config.GetConfig()
+
+	attempt, err := h.loginAttempts.Get(ctx, key)
+	if err == repository.ErrNotFound {
+		attempt = &model.LoginAttempt{Key: key}
+	} else if err != nil {
+		return err
+	}
+
+	// Kegagalan lama (lebih dari satu periode lockout) tidak dihitung lagi
+	if now.Sub(attempt.LastFailureAt.Time()) > cfg.LoginLockoutDuration {
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = primitive.NewDateTimeFromTime(now)
+	expiresAt := now.Add(cfg.LoginLockoutDuration)
+
+	if attempt.Failures >= maxAttempts {
+		lockedUntil := now.Add(cfg.LoginLockoutDuration)
+		attempt.LockedUntil = primitive.NewDateTimeFromTime(lockedUntil)
+		attempt.Failures = 0
+		expiresAt = lockedUntil
+
+		applog.Add(applog.Entry{
+			Timestamp: now,
+			Level:     "warn",
+			Message: fmt.Sprintf("Login lockout: %s dikunci sampai %s setelah %d percobaan gagal",
+				key, lockedUntil.Format(time.RFC3339), maxAttempts),
+			Path:     "/login",
+			RemoteIP: ip,
+			UserNIM:  nim,
+		})
+	}
+
+	attempt.ExpiresAt = primitive.NewDateTimeFromTime(expiresAt)
+	return h.loginAttempts.Save(ctx, attempt)
+}
+
+// resetLoginFailures menghapus catatan login gagal untuk NIM setelah login berhasil
+func (h *Handler) resetLoginFailures(ctx context.Context, nim string) error {
+	return h.loginAttempts.Delete(ctx, loginNIMKey(nim))
+}
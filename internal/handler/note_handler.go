@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// noteThread adalah catatan utama beserta balasannya untuk tampilan admin
+type noteThread struct {
+	model.RegistrationNote
+	AuthorName string       `json:"author_name,omitempty"`
+	Replies    []noteThread `json:"replies,omitempty"`
+}
+
+// applicantNote adalah catatan yang ditampilkan ke pendaftar, tanpa data penulisnya
+type applicantNote struct {
+	ID        primitive.ObjectID `json:"id"`
+	Body      string             `json:"body"`
+	CreatedAt primitive.DateTime `json:"created_at"`
+	UpdatedAt primitive.DateTime `json:"updated_at"`
+	Replies   []applicantNote    `json:"replies,omitempty"`
+}
+
+// groupNotes mengelompokkan catatan (terurut dari yang terlama) menjadi catatan utama dan balasannya
+func groupNotes(notes []model.RegistrationNote) ([]model.RegistrationNote, map[primitive.ObjectID][]model.RegistrationNote) {
+	var roots []model.RegistrationNote
+	replies := make(map[primitive.ObjectID][]model.RegistrationNote)
+	for _, note := range notes {
+		if note.ParentID == nil {
+			roots = append(roots, note)
+		} else {
+			replies[*note.ParentID] = append(replies[*note.ParentID], note)
+		}
+	}
+	return roots, replies
+}
+
+// applicantNotes mengembalikan thread catatan yang boleh dilihat pendaftar
+func applicantNotes(notes []model.RegistrationNote) []applicantNote {
+	toApplicant := func(note model.RegistrationNote) applicantNote {
+		return applicantNote{ID: note.ID, Body: note.Body, CreatedAt: note.CreatedAt, UpdatedAt: note.UpdatedAt}
+	}
+
+	roots, replies := groupNotes(notes)
+	results := []applicantNote{}
+	for _, root := range roots {
+		if root.Visibility != model.NoteVisibilityApplicant {
+			continue
+		}
+		thread := toApplicant(root)
+		for _, reply := range replies[root.ID] {
+			thread.Replies = append(thread.Replies, toApplicant(reply))
+		}
+		results = append(results, thread)
+	}
+	return results
+}
+
+// GetRegistrationNotesHandler mengembalikan semua thread catatan satu pendaftaran (Admin only)
+func (h *Handler) GetRegistrationNotesHandler(w http.ResponseWriter, r *http.Request) {
+	regID, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	notes, err := h.notes.ListByRegistration(ctx, regID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil catatan pendaftaran")
+		return
+	}
+
+	userName := h.userNames(ctx)
+	withAuthor := func(note model.RegistrationNote) noteThread {
+		return noteThread{RegistrationNote: note, AuthorName: userName(note.AuthorID)}
+	}
+
+	roots, replies := groupNotes(notes)
+	threads := []noteThread{}
+	for _, root := range roots {
+		thread := withAuthor(root)
+		for _, reply := range replies[root.ID] {
+			thread.Replies = append(thread.Replies, withAuthor(reply))
+		}
+		threads = append(threads, thread)
+	}
+
+	response.JSON(w, http.StatusOK, threads)
+}
+
+// CreateRegistrationNoteHandler menambah catatan atau balasan pada pendaftaran (Admin only).
+// Visibilitas default internal; balasan selalu mengikuti visibilitas catatan utamanya.
+func (h *Handler) CreateRegistrationNoteHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	regID, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	note := model.RegistrationNote{Visibility: model.NoteVisibilityInternal}
+	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	note.ID = primitive.NilObjectID
+	note.RegistrationID = regID
+	note.AuthorID = actor.UserID
+	note.Body = strings.TrimSpace(note.Body)
+	note.CreatedAt = now
+	note.UpdatedAt = now
+
+	var errs validation.Errors
+	if note.ParentID != nil {
+		parent, err := h.notes.FindByID(r.Context(), *note.ParentID)
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa catatan yang dibalas")
+			return
+		}
+		if err == repository.ErrNotFound || parent.RegistrationID != regID {
+			errs.Add("parent_id", "INVALID_PARENT", "Catatan yang dibalas tidak ditemukan")
+		} else {
+			// Thread hanya satu tingkat: balasan atas balasan masuk ke catatan utamanya
+			if parent.ParentID != nil {
+				note.ParentID = parent.ParentID
+			}
+			note.Visibility = parent.Visibility
+		}
+	}
+	errs = append(errs, validation.Struct(&note)...)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	if err := h.notes.Create(r.Context(), &note); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan catatan")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, note)
+}
+
+// UpdateRegistrationNoteHandler mengubah isi atau visibilitas catatan. Hanya penulisnya atau
+// super admin yang boleh mengubah. Visibilitas catatan utama ikut berlaku untuk balasannya.
+func (h *Handler) UpdateRegistrationNoteHandler(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.editableNote(w, r)
+	if !ok {
+		return
+	}
+
+	note := *existing
+	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	note.ID = existing.ID
+	note.RegistrationID = existing.RegistrationID
+	note.ParentID = existing.ParentID
+	note.AuthorID = existing.AuthorID
+	note.Body = strings.TrimSpace(note.Body)
+	note.CreatedAt = existing.CreatedAt
+	note.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	if note.ParentID != nil {
+		note.Visibility = existing.Visibility
+	}
+
+	if errs := validation.Struct(&note); len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	ctx := r.Context()
+	err := h.notes.Update(ctx, &note)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeNoteNotFound, "Catatan tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui catatan")
+		return
+	}
+
+	if note.ParentID == nil && note.Visibility != existing.Visibility {
+		if err := h.notes.SetThreadVisibility(ctx, note.ID, note.Visibility); err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui visibilitas balasan catatan")
+			return
+		}
+	}
+
+	response.JSON(w, http.StatusOK, note)
+}
+
+// DeleteRegistrationNoteHandler menghapus catatan beserta balasannya. Hanya penulisnya atau super admin yang boleh menghapus.
+func (h *Handler) DeleteRegistrationNoteHandler(w http.ResponseWriter, r *http.Request) {
+	note, ok := h.editableNote(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.notes.Delete(r.Context(), note.ID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeNoteNotFound, "Catatan tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus catatan")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Catatan berhasil dihapus"})
+}
+
+// registrationFromURL membaca ID pendaftaran dari URL dan memastikan pendaftarannya ada.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) registrationFromURL(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
+		return primitive.NilObjectID, false
+	}
+
+	if _, err := h.registrations.FindByID(r.Context(), regID); err != nil {
+		if err == repository.ErrNotFound {
+			response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+			return primitive.NilObjectID, false
+		}
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return primitive.NilObjectID, false
+	}
+	return regID, true
+}
+
+// editableNote mengambil catatan dari URL dan memastikan user yang login boleh mengubahnya.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) editableNote(w http.ResponseWriter, r *http.Request) (*model.RegistrationNote, bool) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return nil, false
+	}
+
+	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
+		return nil, false
+	}
+	noteID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "noteId"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID catatan tidak valid")
+		return nil, false
+	}
+
+	note, err := h.notes.FindByID(r.Context(), noteID)
+	if err == repository.ErrNotFound || (err == nil && note.RegistrationID != regID) {
+		response.Error(w, r, http.StatusNotFound, response.CodeNoteNotFound, "Catatan tidak ditemukan")
+		return nil, false
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil catatan")
+		return nil, false
+	}
+
+	if !canEditNote(actor, note) {
+		response.Error(w, r, http.StatusForbidden, response.CodeNoteNotAuthor, "Hanya penulis catatan atau super admin yang dapat mengubahnya")
+		return nil, false
+	}
+	return note, true
+}
+
+func canEditNote(actor *auth.PasetoPayload, note *model.RegistrationNote) bool {
+	return actor.UserID == note.AuthorID || actor.Role == "super_admin"
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	notesPath = "/api/admin/registrations/{id}/notes"
+	notePath  = "/api/admin/registrations/{id}/notes/{noteId}"
+)
+
+// createNote menambah catatan lewat CreateRegistrationNoteHandler dan memastikan berhasil
+func (e *testEnv) createNote(t *testing.T, admin *model.User, regID primitive.ObjectID, body map[string]string) model.RegistrationNote {
+	t.Helper()
+	rec := e.serve(t, "POST", notesPath, "/api/admin/registrations/"+regID.Hex()+"/notes", e.h.CreateRegistrationNoteHandler, admin, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create note: status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	var note model.RegistrationNote
+	decode(t, rec, &note)
+	return note
+}
+
+// noteRequest mengirim PUT atau DELETE ke satu catatan
+func (e *testEnv) noteRequest(t *testing.T, method string, admin *model.User, note model.RegistrationNote, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	fn := e.h.UpdateRegistrationNoteHandler
+	if method == "DELETE" {
+		fn = e.h.DeleteRegistrationNoteHandler
+	}
+	path := "/api/admin/registrations/" + note.RegistrationID.Hex() + "/notes/" + note.ID.Hex()
+	return e.serve(t, method, notePath, path, fn, admin, body)
+}
+
+// applicantNotesOf mengembalikan catatan yang terlihat oleh pendaftar pada my-registration
+func (e *testEnv) applicantNotesOf(t *testing.T, user *model.User) []applicantNote {
+	t.Helper()
+	rec := e.serve(t, "GET", "/api/user/my-registration", "/api/user/my-registration", e.h.GetUserRegistrationHandler, user, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("my-registration: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Notes []applicantNote `json:"notes"`
+	}
+	decode(t, rec, &body)
+	return body.Notes
+}
+
+func TestRegistrationNoteHandlers(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	admin := env.createUser(t, "900001", "admin")
+	user := env.createUser(t, "714220001", "user")
+	registration := env.submitRegistration(t, user)
+
+	env.createNote(t, admin, registration.ID, map[string]string{"body": "Cek ulang sertifikat"})
+	shared := env.createNote(t, admin, registration.ID, map[string]string{"body": "Lengkapi CV", "visibility": model.NoteVisibilityApplicant})
+	reply := env.createNote(t, admin, registration.ID, map[string]string{"body": "Sudah dilengkapi", "parent_id": shared.ID.Hex()})
+	if reply.Visibility != model.NoteVisibilityApplicant {
+		t.Errorf("visibilitas balasan %s, ingin mengikuti catatan utama", reply.Visibility)
+	}
+	// Balasan atas balasan masuk ke catatan utamanya
+	nested := env.createNote(t, admin, registration.ID, map[string]string{"body": "Terima kasih", "parent_id": reply.ID.Hex()})
+	if nested.ParentID == nil || *nested.ParentID != shared.ID {
+		t.Errorf("parent balasan bertingkat %v, ingin %s", nested.ParentID, shared.ID.Hex())
+	}
+
+	rec := env.serve(t, "GET", notesPath, "/api/admin/registrations/"+registration.ID.Hex()+"/notes", env.h.GetRegistrationNotesHandler, admin, nil)
+	var threads []noteThread
+	decode(t, rec, &threads)
+	if len(threads) != 2 || len(threads[1].Replies) != 2 || threads[1].AuthorName != admin.Name {
+		t.Fatalf("thread admin %+v, ingin dua catatan utama dengan dua balasan pada yang kedua", threads)
+	}
+
+	notes := env.applicantNotesOf(t, user)
+	if len(notes) != 1 || notes[0].ID != shared.ID || len(notes[0].Replies) != 2 {
+		t.Fatalf("catatan pendaftar %+v, ingin hanya thread %s", notes, shared.ID.Hex())
+	}
+
+	// Mengubah visibilitas catatan utama ikut menyembunyikan balasannya
+	rec = env.noteRequest(t, "PUT", admin, shared, map[string]string{"body": "Lengkapi CV terbaru", "visibility": model.NoteVisibilityInternal})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	if stored, _ := env.repos.Notes.FindByID(context.Background(), reply.ID); stored.Visibility != model.NoteVisibilityInternal {
+		t.Errorf("visibilitas balasan %s, ingin internal", stored.Visibility)
+	}
+	if notes := env.applicantNotesOf(t, user); len(notes) != 0 {
+		t.Errorf("catatan internal terlihat oleh pendaftar: %+v", notes)
+	}
+}
+
+func TestCreateRegistrationNoteHandlerValidates(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	registration := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+	other := env.createRegistration(t, env.createUser(t, "714220002", "user"), model.RegistrationStatusPending)
+	foreign := env.createNote(t, admin, other.ID, map[string]string{"body": "Catatan pendaftar lain"})
+
+	rec := env.serve(t, "POST", notesPath, "/api/admin/registrations/"+registration.ID.Hex()+"/notes", env.h.CreateRegistrationNoteHandler, admin,
+		map[string]string{"body": "  ", "visibility": "publik", "parent_id": foreign.ID.Hex()})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "body") || !hasFieldError(t, rec, "parent_id") {
+		t.Errorf("ingin kesalahan pada body dan parent_id: %s", rec.Body.String())
+	}
+
+	rec = env.serve(t, "POST", notesPath, "/api/admin/registrations/"+primitive.NewObjectID().Hex()+"/notes", env.h.CreateRegistrationNoteHandler, admin,
+		map[string]string{"body": "Catatan"})
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != response.CodeRegistrationNotFound {
+		t.Errorf("pendaftaran tidak ada: status %d %s, ingin 404 %s", rec.Code, errorCode(t, rec), response.CodeRegistrationNotFound)
+	}
+}
+
+func TestRegistrationNoteHandlersRequireAuthor(t *testing.T) {
+	env := newTestEnv(t)
+	author := env.createUser(t, "900001", "admin")
+	otherAdmin := env.createUser(t, "900002", "admin")
+	superAdmin := env.createUser(t, "900003", "super_admin")
+	registration := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+	note := env.createNote(t, author, registration.ID, map[string]string{"body": "Perlu wawancara ulang"})
+	reply := env.createNote(t, otherAdmin, registration.ID, map[string]string{"body": "Setuju", "parent_id": note.ID.Hex()})
+
+	for _, method := range []string{"PUT", "DELETE"} {
+		rec := env.noteRequest(t, method, otherAdmin, note, map[string]string{"body": "Diubah"})
+		if rec.Code != http.StatusForbidden || errorCode(t, rec) != response.CodeNoteNotAuthor {
+			t.Errorf("%s oleh admin lain: status %d %s, ingin 403 %s", method, rec.Code, errorCode(t, rec), response.CodeNoteNotAuthor)
+		}
+	}
+
+	// Catatan harus milik pendaftaran pada URL
+	moved := note
+	moved.RegistrationID = primitive.NewObjectID()
+	rec := env.noteRequest(t, "DELETE", author, moved, nil)
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != response.CodeNoteNotFound {
+		t.Errorf("pendaftaran lain: status %d %s, ingin 404 %s", rec.Code, errorCode(t, rec), response.CodeNoteNotFound)
+	}
+
+	// Super admin boleh menghapus catatan siapa pun, beserta balasannya
+	if rec := env.noteRequest(t, "DELETE", superAdmin, note, nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	if notes, _ := env.repos.Notes.ListByRegistration(context.Background(), registration.ID); len(notes) != 0 {
+		t.Errorf("catatan tersisa setelah dihapus: %+v", notes)
+	}
+	rec = env.noteRequest(t, "DELETE", otherAdmin, reply, nil)
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != response.CodeNoteNotFound {
+		t.Errorf("balasan terhapus: status %d %s, ingin 404 %s", rec.Code, errorCode(t, rec), response.CodeNoteNotFound)
+	}
+}
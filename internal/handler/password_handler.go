@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePasswordHandler mengganti password user yang sedang login (wajib menyertakan password lama).
+// Semua sesi lama berakhir, sehingga respons berisi pasangan token baru untuk sesi saat ini.
+func (h *Handler) ChangePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	payload, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	var body struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	var errs validation.Errors
+	validatePassword(&errs, "new_password", "Password baru", body.NewPassword)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.users.FindByID(ctx, payload.UserID)
+	if err != nil {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(body.CurrentPassword)); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeCurrentPasswordIncorrect, "Password lama tidak sesuai")
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(body.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memproses password")
+		return
+	}
+
+	if err := h.users.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengganti password")
+		return
+	}
+	if err := h.tokens.RevokeUserRefreshTokens(ctx, user.ID, time.Now()); err != nil {
+		log.Printf("failed to revoke sessions for user %s: %v", user.ID.Hex(), err)
+	}
+
+	// Ambil ulang user agar token baru memakai token version yang terbaru
+	user, err = h.users.FindByID(ctx, user.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+
+	session, err := h.issueSession(ctx, user, primitive.NewObjectID())
+	if err != nil {
+		log.Printf("token generation failed for NIM %s: %v", user.NIM, err)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat token")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Password berhasil diperbarui",
+		"session": session,
+	})
+}
+
+// ForgotPasswordHandler membuat token reset password sekali pakai dan mengirimkannya lewat notifier.
+// Respons selalu sama agar tidak bisa dipakai untuk menebak NIM yang terdaftar.
+func (h *Handler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		NIM string `json:"nim"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	var errs validation.Errors
+	validation.Var(&errs, "nim", "NIM", body.NIM, "required")
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	respond := func() {
+		response.JSON(w, http.StatusOK, map[string]string{
+			"message": "Jika NIM terdaftar, instruksi reset password telah dikirim ke email Anda",
+		})
+	}
+
+	ctx := r.Context()
+	user, err := h.users.FindByNIM(ctx, strings.TrimSpace(body.NIM))
+	if err != nil || user.Disabled || user.Email == "" {
+		respond()
+		return
+	}
+
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat token reset password")
+		return
+	}
+
+	cfg := config.GetConfig()
+	now := time.Now()
+	err = h.passwordResets.Create(ctx, &model.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: primitive.NewDateTimeFromTime(now.Add(cfg.PasswordResetTTL)),
+		CreatedAt: primitive.NewDateTimeFromTime(now),
+	})
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat token reset password")
+		return
+	}
+
+	err = h.notifier.Send(ctx, notify.Message{
+		To:      user.Email,
+		Subject: "Reset Password Pendaftaran HIMATIF",
+		Body:    passwordResetBody(user.Name, token, cfg.PasswordResetURL, cfg.PasswordResetTTL),
+	})
+	if err != nil {
+		log.Printf("failed to send password reset for NIM %s: %v", user.NIM, err)
+	}
+
+	respond()
+}
+
+func passwordResetBody(name, token, resetURL string, ttl time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Halo %s,\n\n", name)
+	b.WriteString("Kami menerima permintaan untuk mereset password akun pendaftaran HIMATIF Anda.\n")
+	if resetURL != "" {
+		fmt.Fprintf(&b, "Buka tautan berikut untuk membuat password baru:\n%s?token=%s\n\n", resetURL, token)
+	} else {
+		fmt.Fprintf(&b, "Gunakan token berikut untuk membuat password baru:\n%s\n\n", token)
+	}
+	fmt.Fprintf(&b, "Token berlaku selama %s dan hanya bisa dipakai sekali.\n", ttl)
+	b.WriteString("Abaikan pesan ini jika Anda tidak meminta reset password.\n")
+	return b.String()
+}
+
+// ResetPasswordHandler mengganti password memakai token dari ForgotPasswordHandler
+func (h *Handler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || strings.TrimSpace(body.Token) == "" {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	var errs validation.Errors
+	validatePassword(&errs, "new_password", "Password baru", body.NewPassword)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+
+	reset, err := h.passwordResets.FindByTokenHash(ctx, auth.HashToken(strings.TrimSpace(body.Token)))
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusBadRequest, response.CodeResetTokenInvalid, "Token reset password tidak valid")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memvalidasi token reset password")
+		return
+	}
+
+	if reset.UsedAt != nil || reset.ExpiresAt.Time().Before(now) {
+		response.Error(w, r, http.StatusBadRequest, response.CodeResetTokenExpired, "Token reset password sudah tidak berlaku")
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(body.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memproses password")
+		return
+	}
+
+	// Tandai terpakai lebih dulu agar token yang sama tidak bisa dipakai dua kali secara bersamaan
+	err = h.passwordResets.MarkUsed(ctx, reset.ID, now)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusBadRequest, response.CodeResetTokenExpired, "Token reset password sudah tidak berlaku")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memvalidasi token reset password")
+		return
+	}
+
+	err = h.users.UpdatePassword(ctx, reset.UserID, string(hashedPassword))
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mereset password")
+		return
+	}
+	if err := h.tokens.RevokeUserRefreshTokens(ctx, reset.UserID, now); err != nil {
+		log.Printf("failed to revoke sessions for user %s: %v", reset.UserID.Hex(), err)
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Password berhasil direset, silakan login kembali"})
+}
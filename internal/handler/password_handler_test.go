@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const changePasswordPath = "/api/user/password"
+
+// canLogin mengecek apakah user bisa login dengan password tersebut
+func (e *testEnv) canLogin(t *testing.T, nim, password string) bool {
+	t.Helper()
+	rec := e.serve(t, "POST", "/login", "/login", e.h.LoginHandler, nil, map[string]string{"NPM": nim, "password": password})
+	return rec.Code == http.StatusOK
+}
+
+func TestChangePasswordHandler(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+
+	rec := env.serve(t, "PATCH", changePasswordPath, changePasswordPath, env.h.ChangePasswordHandler, user,
+		map[string]string{"current_password": testPassword, "new_password": "rahasia2"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Session sessionResponse `json:"session"`
+	}
+	decode(t, rec, &body)
+	if body.Session.Token == "" || body.Session.RefreshToken == "" {
+		t.Errorf("respons tidak berisi sesi baru: %s", rec.Body.String())
+	}
+
+	if !env.canLogin(t, user.NIM, "rahasia2") || env.canLogin(t, user.NIM, testPassword) {
+		t.Error("password tidak berganti")
+	}
+	// Token yang terbit sebelum password diganti tidak berlaku lagi
+	rec = env.serve(t, "GET", "/api/user/profile", "/api/user/profile", env.h.GetUserProfileHandler, user, nil)
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != response.CodeSessionInvalidated {
+		t.Errorf("token lama: status %d %s, ingin 401 %s", rec.Code, errorCode(t, rec), response.CodeSessionInvalidated)
+	}
+}
+
+func TestChangePasswordHandlerWrongCurrentPassword(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+
+	rec := env.serve(t, "PATCH", changePasswordPath, changePasswordPath, env.h.ChangePasswordHandler, user,
+		map[string]string{"current_password": "salah123", "new_password": "rahasia2"})
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != response.CodeCurrentPasswordIncorrect {
+		t.Fatalf("status %d %s, ingin 400 %s", rec.Code, errorCode(t, rec), response.CodeCurrentPasswordIncorrect)
+	}
+	if !env.canLogin(t, user.NIM, testPassword) {
+		t.Error("password berubah walaupun password lama salah")
+	}
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	env := newTestEnv(t)
+	cfg := config.GetConfig()
+	previous := cfg.PasswordResetURL
+	cfg.PasswordResetURL = ""
+	t.Cleanup(func() { cfg.PasswordResetURL = previous })
+	user := env.createUser(t, "714220001", "user")
+
+	rec := env.serve(t, "POST", "/forgot-password", "/forgot-password", env.h.ForgotPasswordHandler, nil, map[string]string{"nim": user.NIM})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("forgot-password: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	messages := env.notifier.Messages()
+	if len(messages) != 1 || messages[0].To != user.Email {
+		t.Fatalf("pesan reset tidak terkirim ke email user: %+v", messages)
+	}
+	_, rest, _ := strings.Cut(messages[0].Body, "password baru:\n")
+	token, _, _ := strings.Cut(rest, "\n")
+
+	reset := map[string]string{"token": token, "new_password": "rahasia2"}
+	rec = env.serve(t, "POST", "/reset-password", "/reset-password", env.h.ResetPasswordHandler, nil, reset)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reset-password: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	if !env.canLogin(t, user.NIM, "rahasia2") {
+		t.Error("password baru tidak bisa dipakai login")
+	}
+
+	// Token hanya bisa dipakai sekali
+	reset["new_password"] = "rahasia3"
+	rec = env.serve(t, "POST", "/reset-password", "/reset-password", env.h.ResetPasswordHandler, nil, reset)
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != response.CodeResetTokenExpired {
+		t.Errorf("token dipakai ulang: status %d %s, ingin 400 %s", rec.Code, errorCode(t, rec), response.CodeResetTokenExpired)
+	}
+}
+
+func TestForgotPasswordHandlerUnknownNIM(t *testing.T) {
+	env := newTestEnv(t)
+
+	rec := env.serve(t, "POST", "/forgot-password", "/forgot-password", env.h.ForgotPasswordHandler, nil, map[string]string{"nim": "714229999"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200 agar NIM tidak bisa ditebak: %s", rec.Code, rec.Body.String())
+	}
+	if messages := env.notifier.Messages(); len(messages) != 0 {
+		t.Errorf("pesan terkirim untuk NIM yang tidak terdaftar: %+v", messages)
+	}
+}
+
+func TestResetPasswordHandlerExpiredToken(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser(t, "714220001", "user")
+	token, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = env.repos.PasswordResets.Create(context.Background(), &model.PasswordReset{
+		UserID: user.ID, TokenHash: hash,
+		ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute)),
+		CreatedAt: primitive.NewDateTimeFromTime(time.Now().Add(-time.Hour)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := env.serve(t, "POST", "/reset-password", "/reset-password", env.h.ResetPasswordHandler, nil,
+		map[string]string{"token": token, "new_password": "rahasia2"})
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != response.CodeResetTokenExpired {
+		t.Fatalf("status %d %s, ingin 400 %s", rec.Code, errorCode(t, rec), response.CodeResetTokenExpired)
+	}
+	if !env.canLogin(t, user.NIM, testPassword) {
+		t.Error("password berubah dengan token yang sudah kedaluwarsa")
+	}
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// isPeriodOpen mengecek apakah periode aktif dan waktu now berada di dalam jendela pendaftarannya
+func isPeriodOpen(period *model.RecruitmentPeriod, now time.Time) bool {
+	return period.Status == model.PeriodStatusActive &&
+		!now.Before(period.OpensAt.Time()) &&
+		now.Before(period.ClosesAt.Time())
+}
+
+// openPeriod mengembalikan periode yang sedang menerima pendaftaran, ErrNotFound jika tidak ada
+func (h *Handler) openPeriod(ctx context.Context, now time.Time) (*model.RecruitmentPeriod, error) {
+	period, err := h.periods.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isPeriodOpen(period, now) {
+		return nil, repository.ErrNotFound
+	}
+	return period, nil
+}
+
+// currentPeriod mengembalikan periode aktif, atau periode terbaru jika tidak ada yang aktif.
+// ErrNotFound dikembalikan jika belum ada periode sama sekali.
+func (h *Handler) currentPeriod(ctx context.Context) (*model.RecruitmentPeriod, error) {
+	period, err := h.periods.FindActive(ctx)
+	if err != repository.ErrNotFound {
+		return period, err
+	}
+
+	periods, err := h.periods.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &periods[0], nil
+}
+
+// periodFilter membaca query "period" pada daftar admin: kosong berarti periode saat ini,
+// "all" berarti semua periode, selain itu harus berupa ID periode.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) periodFilter(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	switch value := r.URL.Query().Get("period"); value {
+	case "all":
+		return primitive.NilObjectID, true
+	case "":
+		period, err := h.currentPeriod(r.Context())
+		if err == repository.ErrNotFound {
+			return primitive.NilObjectID, true
+		}
+		if err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil periode rekrutmen")
+			return primitive.NilObjectID, false
+		}
+		return period.ID, true
+	default:
+		periodID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID periode tidak valid")
+			return primitive.NilObjectID, false
+		}
+		return periodID, true
+	}
+}
+
+// GetActivePeriodHandler mengembalikan periode aktif dan apakah pendaftaran sedang dibuka
+func (h *Handler) GetActivePeriodHandler(w http.ResponseWriter, r *http.Request) {
+	period, err := h.periods.FindActive(r.Context())
+	if err != nil && err != repository.ErrNotFound {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil periode rekrutmen")
+		return
+	}
+
+	if err == repository.ErrNotFound {
+		response.JSON(w, http.StatusOK, map[string]interface{}{"period": nil, "is_open": false})
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"period":  period,
+		"is_open": isPeriodOpen(period, time.Now()),
+	})
+}
+
+// GetAllPeriodsHandler mengembalikan semua periode rekrutmen (Admin only)
+func (h *Handler) GetAllPeriodsHandler(w http.ResponseWriter, r *http.Request) {
+	periods, err := h.periods.List(r.Context())
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil periode rekrutmen")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, periods)
+}
+
+// CreatePeriodHandler membuat periode rekrutmen baru (Admin only). Status default adalah draft.
+func (h *Handler) CreatePeriodHandler(w http.ResponseWriter, r *http.Request) {
+	period := model.RecruitmentPeriod{Status: model.PeriodStatusDraft}
+	if err := json.NewDecoder(r.Body).Decode(&period); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	period.ID = primitive.NewObjectID()
+	period.Name = strings.TrimSpace(period.Name)
+	period.CreatedAt = now
+	period.UpdatedAt = now
+
+	if !h.validatePeriod(w, r, &period) {
+		return
+	}
+
+	if err := h.periods.Create(r.Context(), &period); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat periode rekrutmen")
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, period)
+}
+
+// UpdatePeriodHandler memperbarui periode rekrutmen (Admin only). Field yang tidak dikirim tidak berubah.
+func (h *Handler) UpdatePeriodHandler(w http.ResponseWriter, r *http.Request) {
+	periodID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID periode tidak valid")
+		return
+	}
+
+	existing, err := h.periods.FindByID(r.Context(), periodID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodePeriodNotFound, "Periode rekrutmen tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui periode rekrutmen")
+		return
+	}
+
+	period := *existing
+	if err := json.NewDecoder(r.Body).Decode(&period); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	period.ID = existing.ID
+	period.Name = strings.TrimSpace(period.Name)
+	period.CreatedAt = existing.CreatedAt
+	period.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	if !h.validatePeriod(w, r, &period) {
+		return
+	}
+
+	err = h.periods.Update(r.Context(), &period)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodePeriodNotFound, "Periode rekrutmen tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memperbarui periode rekrutmen")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, period)
+}
+
+// DeletePeriodHandler menghapus periode yang belum memiliki pendaftaran (Admin only)
+func (h *Handler) DeletePeriodHandler(w http.ResponseWriter, r *http.Request) {
+	periodID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID periode tidak valid")
+		return
+	}
+
+	used, err := h.registrations.CountByPeriod(r.Context(), periodID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus periode rekrutmen")
+		return
+	}
+	if used > 0 {
+		response.ErrorWithDetails(w, r, http.StatusConflict, response.CodePeriodInUse,
+			"Periode sudah memiliki pendaftaran. Ubah statusnya menjadi closed alih-alih menghapusnya.",
+			map[string]int64{"registrations": used})
+		return
+	}
+
+	err = h.periods.Delete(r.Context(), periodID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodePeriodNotFound, "Periode rekrutmen tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghapus periode rekrutmen")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Periode rekrutmen berhasil dihapus"})
+}
+
+// validatePeriod memvalidasi isian periode dan memastikan hanya ada satu periode aktif.
+// Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) validatePeriod(w http.ResponseWriter, r *http.Request, period *model.RecruitmentPeriod) bool {
+	errs := validation.Struct(period)
+
+	if period.OpensAt == 0 {
+		errs.Add("opens_at", validation.CodeRequired, "Waktu buka wajib diisi")
+	}
+	if period.ClosesAt == 0 {
+		errs.Add("closes_at", validation.CodeRequired, "Waktu tutup wajib diisi")
+	}
+	if period.OpensAt != 0 && period.ClosesAt != 0 && period.ClosesAt <= period.OpensAt {
+		errs.Add("closes_at", "INVALID_RANGE", "Waktu tutup harus setelah waktu buka")
+	}
+
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return false
+	}
+
+	if period.Status == model.PeriodStatusActive {
+		active, err := h.periods.FindActive(r.Context())
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa periode aktif")
+			return false
+		}
+		if err == nil && active.ID != period.ID {
+			response.ErrorWithDetails(w, r, http.StatusConflict, response.CodePeriodAlreadyActive,
+				"Masih ada periode lain yang aktif. Tutup periode tersebut terlebih dahulu.",
+				map[string]string{"active_period_id": active.ID.Hex()})
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const periodsPath = "/api/admin/periods"
+
+// periodBody adalah isian periode dengan jendela pendaftaran relatif terhadap sekarang
+func periodBody(status string, opensIn, closesIn time.Duration) map[string]string {
+	now := time.Now()
+	return map[string]string{
+		"name": "Open Recruitment", "academic_year": "2026/2027", "status": status,
+		"opens_at": now.Add(opensIn).Format(time.RFC3339), "closes_at": now.Add(closesIn).Format(time.RFC3339),
+	}
+}
+
+func TestPeriodHandlers(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+
+	rec := env.serve(t, "POST", periodsPath, periodsPath, env.h.CreatePeriodHandler, admin, periodBody(model.PeriodStatusActive, -time.Hour, time.Hour))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	var period model.RecruitmentPeriod
+	decode(t, rec, &period)
+
+	rec = env.serve(t, "GET", "/api/periods/active", "/api/periods/active", env.h.GetActivePeriodHandler, nil, nil)
+	var active struct {
+		Period *model.RecruitmentPeriod `json:"period"`
+		IsOpen bool                     `json:"is_open"`
+	}
+	decode(t, rec, &active)
+	if active.Period == nil || active.Period.ID != period.ID || !active.IsOpen {
+		t.Errorf("periode aktif %+v, ingin %s yang sedang dibuka", active, period.ID.Hex())
+	}
+
+	// Hanya boleh ada satu periode aktif
+	rec = env.serve(t, "POST", periodsPath, periodsPath, env.h.CreatePeriodHandler, admin, periodBody(model.PeriodStatusActive, time.Hour, 2*time.Hour))
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodePeriodAlreadyActive {
+		t.Errorf("periode aktif kedua: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodePeriodAlreadyActive)
+	}
+
+	// Periode yang sudah punya pendaftaran tidak bisa dihapus
+	for _, name := range []string{"Web", "Data"} {
+		if err := env.repos.Divisions.Create(context.Background(), &model.Division{Name: name, Active: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env.submitRegistration(t, env.createUser(t, "714220001", "user"))
+	rec = env.serve(t, "DELETE", periodsPath+"/{id}", periodsPath+"/"+period.ID.Hex(), env.h.DeletePeriodHandler, admin, nil)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodePeriodInUse {
+		t.Errorf("hapus periode terpakai: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodePeriodInUse)
+	}
+}
+
+func TestCreatePeriodHandlerValidatesWindow(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+
+	rec := env.serve(t, "POST", periodsPath, periodsPath, env.h.CreatePeriodHandler, admin, periodBody("archived", time.Hour, -time.Hour))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "closes_at") || !hasFieldError(t, rec, "status") {
+		t.Errorf("ingin kesalahan pada closes_at dan status: %s", rec.Body.String())
+	}
+}
+
+func TestSubmitRegistrationHandlerTagsPeriod(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+
+	registration := env.submitRegistration(t, user)
+	if registration.PeriodID != period.ID {
+		t.Errorf("periode pendaftaran %s, ingin %s", registration.PeriodID.Hex(), period.ID.Hex())
+	}
+
+	req := registrationRequest(t, "POST", registrationAnswers, "cv", "certificate", "formal_photo")
+	rec := env.serveRequest(t, "/api/user/registration", req, env.h.SubmitRegistrationHandler, user)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeAlreadyRegistered {
+		t.Errorf("pendaftaran kedua: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeAlreadyRegistered)
+	}
+}
+
+func TestSubmitRegistrationHandlerOutsideWindow(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+	ctx := context.Background()
+
+	for name, window := range map[string][2]time.Duration{
+		"belum dibuka":  {time.Hour, 2 * time.Hour},
+		"sudah ditutup": {-2 * time.Hour, -time.Hour},
+	} {
+		period.OpensAt = primitive.NewDateTimeFromTime(time.Now().Add(window[0]))
+		period.ClosesAt = primitive.NewDateTimeFromTime(time.Now().Add(window[1]))
+		if err := env.repos.Periods.Update(ctx, period); err != nil {
+			t.Fatal(err)
+		}
+
+		req := registrationRequest(t, "POST", registrationAnswers, "cv", "certificate", "formal_photo")
+		rec := env.serveRequest(t, "/api/user/registration", req, env.h.SubmitRegistrationHandler, user)
+		if rec.Code != http.StatusForbidden || errorCode(t, rec) != response.CodeRegistrationClosed {
+			t.Errorf("%s: status %d %s, ingin 403 %s", name, rec.Code, errorCode(t, rec), response.CodeRegistrationClosed)
+		}
+	}
+	if registrations, _ := env.repos.Registrations.ListByUser(ctx, user.ID); len(registrations) != 0 {
+		t.Errorf("pendaftaran tersimpan di luar jendela: %+v", registrations)
+	}
+}
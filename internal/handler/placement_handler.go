@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/placement"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sumber nilai yang dipakai untuk meranking pendaftar di suatu divisi
+const (
+	scoreSourceDivision = "division" // Rata-rata penilaian untuk divisi tersebut
+	scoreSourceOverall  = "overall"  // Rata-rata seluruh penilaian karena divisi tersebut belum dinilai
+	scoreSourceNone     = "none"     // Belum dinilai sama sekali
+)
+
+type placementChoice struct {
+	Division    string   `json:"division"`
+	Score       *float64 `json:"score"`
+	ScoreSource string   `json:"score_source"`
+}
+
+// placementEntry adalah hasil penempatan satu pendaftar beserta alasannya
+type placementEntry struct {
+	RegistrationID primitive.ObjectID `json:"registration_id"`
+	Name           string             `json:"name"`
+	NIM            string             `json:"nim"`
+	Choices        []placementChoice  `json:"choices"`
+	// Division kosong jika pendaftar tidak mendapat divisi pilihannya
+	Division        string   `json:"division"`
+	Choice          int      `json:"choice"`
+	Rank            int      `json:"rank,omitempty"`
+	CurrentDivision string   `json:"current_division,omitempty"`
+	Changed         bool     `json:"changed"`
+	Explanation     []string `json:"explanation"`
+}
+
+type placementDivisionSummary struct {
+	Division    string   `json:"division"`
+	Quota       int      `json:"quota"`
+	Placed      int      `json:"placed"`
+	Remaining   *int     `json:"remaining"` // nil jika kuota tidak dibatasi
+	CutoffScore *float64 `json:"cutoff_score"`
+}
+
+// placementPlan adalah hasil penempatan seluruh pendaftar yang diterima pada satu periode.
+// Fingerprint berubah jika hasilnya berubah, sehingga apply bisa memastikan hasilnya sama dengan preview.
+type placementPlan struct {
+	PeriodID    primitive.ObjectID         `json:"period_id"`
+	Fingerprint string                     `json:"fingerprint"`
+	Total       int                        `json:"total"`
+	Unplaced    int                        `json:"unplaced"`
+	Divisions   []placementDivisionSummary `json:"divisions"`
+	Placements  []placementEntry           `json:"placements"`
+}
+
+// PreviewPlacementHandler menghitung penempatan divisi untuk pendaftar berstatus accepted tanpa menyimpannya
+func (h *Handler) PreviewPlacementHandler(w http.ResponseWriter, r *http.Request) {
+	plan, ok := h.placementPlan(w, r)
+	if !ok {
+		return
+	}
+	response.JSON(w, http.StatusOK, plan)
+}
+
+// ApplyPlacementHandler menghitung ulang penempatan lalu menyimpan divisi akhir pada setiap pendaftaran.
+// Jika body berisi fingerprint dari preview dan hasilnya sudah berbeda (misal ada nilai atau status yang
+// berubah), tidak ada yang disimpan.
+func (h *Handler) ApplyPlacementHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	var payload struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && err != io.EOF {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	plan, ok := h.placementPlan(w, r)
+	if !ok {
+		return
+	}
+	if payload.Fingerprint != "" && payload.Fingerprint != plan.Fingerprint {
+		response.Error(w, r, http.StatusConflict, response.CodePlacementChanged,
+			"Hasil penempatan sudah berubah sejak preview. Silakan tinjau ulang sebelum menyimpan.")
+		return
+	}
+
+	ctx := r.Context()
+	now := time.Now()
+	placements := make(map[primitive.ObjectID]string, len(plan.Placements))
+	for _, entry := range plan.Placements {
+		placements[entry.RegistrationID] = entry.Division
+	}
+	if err := h.registrations.SetPlacements(ctx, plan.PeriodID, placements, now); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan hasil penempatan")
+		return
+	}
+
+	var events []model.RegistrationEvent
+	applied := 0
+	at := primitive.NewDateTimeFromTime(now)
+	for _, entry := range plan.Placements {
+		if !entry.Changed {
+			continue
+		}
+		applied++
+		event := newRegistrationEvent(actor, entry.RegistrationID, model.RegistrationEventPlaced, at)
+		event.Division = entry.Division
+		event.Note = strings.Join(entry.Explanation, " ")
+		events = append(events, event)
+	}
+	h.recordEvents(ctx, events...)
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": fmt.Sprintf("Penempatan %d pendaftar berhasil disimpan", applied),
+		"applied": applied,
+		"plan":    plan,
+	})
+}
+
+// placementPlan mengumpulkan pendaftar yang diterima pada periode dari query, nilai wawancaranya,
+// dan kuota divisi lalu menjalankan placement.Run. Mengembalikan false jika respons error sudah dikirim.
+func (h *Handler) placementPlan(w http.ResponseWriter, r *http.Request) (*placementPlan, bool) {
+	periodID, ok := h.periodFilter(w, r)
+	if !ok {
+		return nil, false
+	}
+	if periodID.IsZero() {
+		var errs validation.Errors
+		errs.Add("period", validation.CodeRequired, "Penempatan dijalankan per periode rekrutmen")
+		writeValidationErrors(w, r, errs)
+		return nil, false
+	}
+
+	ctx := r.Context()
+	details, _, err := h.registrations.ListDetails(ctx, repository.RegistrationFilter{
+		PeriodID: periodID,
+		Statuses: []string{model.RegistrationStatusAccepted},
+		Sort:     repository.RegistrationSortSubmitted,
+	})
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return nil, false
+	}
+	divisions, err := h.divisions.List(ctx, false)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+		return nil, false
+	}
+	ids := make([]primitive.ObjectID, len(details))
+	for i := range details {
+		ids[i] = details[i].ID
+	}
+	scores, err := h.scores.ListByRegistrations(ctx, ids)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil nilai wawancara")
+		return nil, false
+	}
+
+	// Rata-rata total per pendaftaran per divisi; kunci "" untuk rata-rata seluruh penilaian
+	type sum struct {
+		total float64
+		count int
+	}
+	sums := make(map[primitive.ObjectID]map[string]*sum, len(details))
+	for _, score := range scores {
+		if sums[score.RegistrationID] == nil {
+			sums[score.RegistrationID] = map[string]*sum{}
+		}
+		for _, key := range []string{score.Division, ""} {
+			if sums[score.RegistrationID][key] == nil {
+				sums[score.RegistrationID][key] = &sum{}
+			}
+			sums[score.RegistrationID][key].total += score.Total
+			sums[score.RegistrationID][key].count++
+		}
+	}
+	average := func(id primitive.ObjectID, key string) (float64, bool) {
+		s := sums[id][key]
+		if s == nil || s.count == 0 {
+			return 0, false
+		}
+		return math.Round(s.total/float64(s.count)*100) / 100, true
+	}
+
+	applicants := make([]placement.Applicant, len(details))
+	choices := make(map[primitive.ObjectID][]placementChoice, len(details))
+	for i := range details {
+		detail := &details[i]
+		applicant := placement.Applicant{ID: detail.ID, Scores: map[string]float64{}}
+		for _, division := range []string{detail.Division1, detail.Division2} {
+			if division == "" {
+				continue
+			}
+			applicant.Choices = append(applicant.Choices, division)
+			choice := placementChoice{Division: division, ScoreSource: scoreSourceNone}
+			if score, ok := average(detail.ID, division); ok {
+				choice.Score, choice.ScoreSource = &score, scoreSourceDivision
+			} else if score, ok := average(detail.ID, ""); ok {
+				choice.Score, choice.ScoreSource = &score, scoreSourceOverall
+			}
+			if choice.Score != nil {
+				applicant.Scores[division] = *choice.Score
+			}
+			choices[detail.ID] = append(choices[detail.ID], choice)
+		}
+		applicants[i] = applicant
+	}
+
+	quotas := make([]placement.Division, len(divisions))
+	for i, division := range divisions {
+		quotas[i] = placement.Division{Name: division.Name, Quota: division.Quota, Inactive: !division.Active}
+	}
+	result := placement.Run(applicants, quotas)
+
+	plan := &placementPlan{
+		PeriodID:   periodID,
+		Total:      len(details),
+		Divisions:  []placementDivisionSummary{},
+		Placements: make([]placementEntry, 0, len(details)),
+	}
+	for _, division := range divisions {
+		placed := result.Divisions[division.Name]
+		summary := placementDivisionSummary{
+			Division:    division.Name,
+			Quota:       division.Quota,
+			Placed:      len(placed.Members),
+			CutoffScore: placed.Cutoff,
+		}
+		if division.Quota > 0 {
+			remaining := division.Quota - len(placed.Members)
+			summary.Remaining = &remaining
+		}
+		plan.Divisions = append(plan.Divisions, summary)
+	}
+
+	hash := sha256.New()
+	for i := range details {
+		detail := &details[i]
+		assignment := result.Assignments[detail.ID]
+		entry := placementEntry{
+			RegistrationID:  detail.ID,
+			Name:            detail.Name,
+			NIM:             detail.NIM,
+			Choices:         choices[detail.ID],
+			Division:        assignment.Division,
+			Choice:          assignment.Choice,
+			Rank:            assignment.Rank,
+			CurrentDivision: detail.PlacedDivision,
+			Changed:         assignment.Division != detail.PlacedDivision,
+		}
+		entry.Explanation = explainPlacement(&entry, assignment, result.Divisions)
+		if entry.Division == "" {
+			plan.Unplaced++
+		}
+		plan.Placements = append(plan.Placements, entry)
+		fmt.Fprintf(hash, "%s=%s\n", detail.ID.Hex(), assignment.Division)
+	}
+	plan.Fingerprint = hex.EncodeToString(hash.Sum(nil))[:16]
+
+	// Urutkan per divisi lalu peringkat agar mudah dibaca; yang tidak ditempatkan di akhir
+	sort.SliceStable(plan.Placements, func(i, j int) bool {
+		a, b := plan.Placements[i], plan.Placements[j]
+		if (a.Division == "") != (b.Division == "") {
+			return a.Division != ""
+		}
+		if a.Division != b.Division {
+			return a.Division < b.Division
+		}
+		return a.Rank < b.Rank
+	})
+	return plan, true
+}
+
+// explainPlacement menyusun alasan penempatan dalam bahasa yang bisa dibaca panitia
+func explainPlacement(entry *placementEntry, assignment *placement.Assignment, divisions map[string]*placement.DivisionResult) []string {
+	scoreOf := func(division string) *placementChoice {
+		for i := range entry.Choices {
+			if entry.Choices[i].Division == division {
+				return &entry.Choices[i]
+			}
+		}
+		return nil
+	}
+
+	var lines []string
+	for _, rejection := range assignment.Rejections {
+		if rejection.Reason == placement.ReasonUnknownDivision {
+			lines = append(lines, fmt.Sprintf("Divisi %s (pilihan %d) tidak ada pada katalog.", rejection.Division, rejection.Choice))
+			continue
+		}
+		if rejection.Reason == placement.ReasonInactiveDivision {
+			lines = append(lines, fmt.Sprintf("Divisi %s (pilihan %d) sedang dinonaktifkan.", rejection.Division, rejection.Choice))
+			continue
+		}
+		quota := divisions[rejection.Division].Quota
+		line := fmt.Sprintf("Tidak ditempatkan di %s (pilihan %d): kuota %d sudah terisi pendaftar dengan peringkat lebih tinggi", rejection.Division, rejection.Choice, quota)
+		own := scoreOf(rejection.Division)
+		switch {
+		case own == nil || own.Score == nil:
+			line += "; pendaftar belum memiliki nilai wawancara."
+		case rejection.Cutoff != nil && *rejection.Cutoff == *own.Score:
+			line += fmt.Sprintf(" (nilai sama dengan nilai terendah yang diterima, %s, tetapi mendaftar lebih akhir).", formatScore(*own.Score))
+		case rejection.Cutoff != nil:
+			line += fmt.Sprintf(" (nilai terendah yang diterima %s, nilai pendaftar %s).", formatScore(*rejection.Cutoff), formatScore(*own.Score))
+		default:
+			line += fmt.Sprintf(" (nilai pendaftar %s).", formatScore(*own.Score))
+		}
+		lines = append(lines, line)
+	}
+
+	if assignment.Division == "" {
+		return append(lines, "Tidak ditempatkan di divisi mana pun karena tidak ada pilihan yang masih bisa menerima.")
+	}
+	line := fmt.Sprintf("Ditempatkan di %s sesuai pilihan %d", assignment.Division, assignment.Choice)
+	if quota := divisions[assignment.Division].Quota; quota > 0 {
+		line += fmt.Sprintf(", peringkat %d dari kuota %d", assignment.Rank, quota)
+	} else {
+		line += fmt.Sprintf(", peringkat %d (kuota tidak dibatasi)", assignment.Rank)
+	}
+	if own := scoreOf(assignment.Division); own != nil && own.Score != nil {
+		line += fmt.Sprintf(", nilai %s", formatScore(*own.Score))
+		if own.ScoreSource == scoreSourceOverall {
+			line += " (rata-rata seluruh penilaian karena belum dinilai khusus untuk divisi ini)"
+		}
+	}
+	return append(lines, line+".")
+}
+
+func formatScore(score float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", score), "0"), ".")
+}
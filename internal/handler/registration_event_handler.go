@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newRegistrationEvent membuat event riwayat dengan pelaku dari payload token
+func newRegistrationEvent(actor *auth.PasetoPayload, registrationID primitive.ObjectID, eventType string, at primitive.DateTime) model.RegistrationEvent {
+	return model.RegistrationEvent{
+		RegistrationID: registrationID,
+		Type:           eventType,
+		ActorID:        actor.UserID,
+		ActorRole:      actor.Role,
+		CreatedAt:      at,
+	}
+}
+
+// recordEvents menyimpan riwayat pendaftaran. Perubahan utamanya sudah tersimpan,
+// jadi kegagalan di sini hanya dicatat ke log tanpa menggagalkan request.
+func (h *Handler) recordEvents(ctx context.Context, events ...model.RegistrationEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if err := h.events.CreateMany(ctx, events); err != nil {
+		log.Printf("failed to record %d registration event(s) for %s: %v", len(events), events[0].RegistrationID.Hex(), err)
+	}
+}
+
+// userNames mengembalikan fungsi pencari nama user yang mengambil setiap user sekali saja.
+// User yang sudah dihapus menghasilkan nama kosong.
+func (h *Handler) userNames(ctx context.Context) func(primitive.ObjectID) string {
+	names := make(map[primitive.ObjectID]string)
+	return func(id primitive.ObjectID) string {
+		name, ok := names[id]
+		if !ok {
+			if user, err := h.users.FindByID(ctx, id); err == nil {
+				name = user.Name
+			}
+			names[id] = name
+		}
+		return name
+	}
+}
+
+// registrationHistoryEntry adalah event riwayat beserta nama pelakunya untuk admin
+type registrationHistoryEntry struct {
+	model.RegistrationEvent
+	ActorName string `json:"actor_name,omitempty"`
+}
+
+// GetRegistrationHistoryHandler mengembalikan seluruh riwayat perubahan satu pendaftaran,
+// termasuk pendaftaran yang sudah dihapus admin
+func (h *Handler) GetRegistrationHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	events, err := h.events.ListByRegistration(ctx, regID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil riwayat pendaftaran")
+		return
+	}
+	// Riwayat pendaftaran yang sudah dihapus admin tetap bisa dibaca; tanpa riwayat, pendaftarannya harus ada
+	if len(events) == 0 {
+		if _, ok := h.registrationFromURL(w, r); !ok {
+			return
+		}
+	}
+
+	userName := h.userNames(ctx)
+	history := make([]registrationHistoryEntry, len(events))
+	for i, event := range events {
+		history[i] = registrationHistoryEntry{RegistrationEvent: event, ActorName: userName(event.ActorID)}
+	}
+
+	response.JSON(w, http.StatusOK, history)
+}
+
+// registrationTimelineEntry adalah event riwayat yang aman ditampilkan ke pendaftar:
+// tanpa pelaku dan tanpa catatan internal admin
+type registrationTimelineEntry struct {
+	Type              string             `json:"type"`
+	Status            string             `json:"status,omitempty"`
+	StatusLabel       string             `json:"status_label,omitempty"`
+	InterviewSchedule string             `json:"interview_schedule,omitempty"`
+	InterviewLocation string             `json:"interview_location,omitempty"`
+	Division          string             `json:"division,omitempty"`
+	At                primitive.DateTime `json:"at"`
+}
+
+func registrationTimeline(events []model.RegistrationEvent) []registrationTimelineEntry {
+	timeline := make([]registrationTimelineEntry, len(events))
+	for i, event := range events {
+		entry := registrationTimelineEntry{
+			Type:              event.Type,
+			Status:            event.NewStatus,
+			InterviewSchedule: event.InterviewSchedule,
+			InterviewLocation: event.InterviewLocation,
+			Division:          event.Division,
+			At:                event.CreatedAt,
+		}
+		if status, ok := model.FindRegistrationStatus(event.NewStatus); ok {
+			entry.StatusLabel = status.Label
+		}
+		timeline[i] = entry
+	}
+	return timeline
+}
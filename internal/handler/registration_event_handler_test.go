@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	registrationPath = "/api/admin/registrations/{id}"
+	historyPath      = "/api/admin/registrations/{id}/history"
+)
+
+// updateRegistration mengubah pendaftaran lewat UpdateRegistrationDetailsHandler
+func (e *testEnv) updateRegistration(t *testing.T, admin *model.User, id primitive.ObjectID, body map[string]string) int {
+	t.Helper()
+	rec := e.serve(t, "PATCH", registrationPath, "/api/admin/registrations/"+id.Hex(), e.h.UpdateRegistrationDetailsHandler, admin, body)
+	return rec.Code
+}
+
+func TestRegistrationHistoryAndTimeline(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	admin := env.createUser(t, "900001", "admin")
+	user := env.createUser(t, "714220001", "user")
+	registration := env.submitRegistration(t, user)
+
+	if code := env.updateRegistration(t, admin, registration.ID, map[string]string{"status": model.RegistrationStatusDocumentReview, "note": "berkas lengkap"}); code != http.StatusOK {
+		t.Fatalf("document_review: status %d, ingin 200", code)
+	}
+	if code := env.updateRegistration(t, admin, registration.ID, map[string]string{
+		"status": model.RegistrationStatusInterview, "interview_schedule": "Senin 10.00", "interview_location": "Ruang 101",
+	}); code != http.StatusOK {
+		t.Fatalf("interview: status %d, ingin 200", code)
+	}
+
+	rec := env.serve(t, "GET", historyPath, "/api/admin/registrations/"+registration.ID.Hex()+"/history", env.h.GetRegistrationHistoryHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("history: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var history []registrationHistoryEntry
+	decode(t, rec, &history)
+	if len(history) != 3 || history[0].Type != model.RegistrationEventSubmitted {
+		t.Fatalf("riwayat %+v, ingin submitted lalu dua perubahan status", history)
+	}
+	review := history[1]
+	if review.OldStatus != model.RegistrationStatusPending || review.NewStatus != model.RegistrationStatusDocumentReview ||
+		review.Note != "berkas lengkap" || review.ActorID != admin.ID || review.ActorName != admin.Name {
+		t.Errorf("event document_review %+v", review)
+	}
+	if interview := history[2]; interview.NewStatus != model.RegistrationStatusInterview || interview.InterviewSchedule != "Senin 10.00" {
+		t.Errorf("event interview %+v", interview)
+	}
+
+	// Timeline pendaftar tidak memuat pelaku maupun catatan internal
+	rec = env.serve(t, "GET", "/api/user/my-registration", "/api/user/my-registration", env.h.GetUserRegistrationHandler, user, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("my-registration: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Timeline []registrationTimelineEntry `json:"timeline"`
+	}
+	decode(t, rec, &body)
+	if len(body.Timeline) != 3 || body.Timeline[2].Status != model.RegistrationStatusInterview || body.Timeline[2].StatusLabel == "" {
+		t.Errorf("timeline %+v", body.Timeline)
+	}
+	if raw := rec.Body.String(); strings.Contains(raw, "berkas lengkap") || strings.Contains(raw, admin.ID.Hex()) {
+		t.Errorf("timeline membocorkan catatan atau pelaku: %s", raw)
+	}
+}
+
+func TestUpdateRegistrationDetailsHandlerInvalidTransition(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	registration := env.createRegistration(t, env.createUser(t, "714220001", "user"), model.RegistrationStatusPending)
+
+	rec := env.serve(t, "PATCH", registrationPath, "/api/admin/registrations/"+registration.ID.Hex(), env.h.UpdateRegistrationDetailsHandler, admin,
+		map[string]string{"status": model.RegistrationStatusAccepted})
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != response.CodeInvalidTransition {
+		t.Fatalf("status %d %s, ingin 400 %s", rec.Code, errorCode(t, rec), response.CodeInvalidTransition)
+	}
+
+	ctx := context.Background()
+	if stored, _ := env.repos.Registrations.FindByID(ctx, registration.ID); stored.Status != model.RegistrationStatusPending {
+		t.Errorf("status berubah menjadi %s", stored.Status)
+	}
+	if events, _ := env.repos.Events.ListByRegistration(ctx, registration.ID); len(events) != 0 {
+		t.Errorf("perubahan yang ditolak tercatat di riwayat: %+v", events)
+	}
+}
+
+func TestGetRegistrationHistoryHandlerNotFound(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+
+	rec := env.serve(t, "GET", historyPath, "/api/admin/registrations/"+primitive.NewObjectID().Hex()+"/history", env.h.GetRegistrationHistoryHandler, admin, nil)
+	if rec.Code != http.StatusNotFound || errorCode(t, rec) != response.CodeRegistrationNotFound {
+		t.Errorf("status %d %s, ingin 404 %s", rec.Code, errorCode(t, rec), response.CodeRegistrationNotFound)
+	}
+}
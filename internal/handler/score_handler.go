@@ -0,0 +1,379 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxRubricCriteria membatasi jumlah kriteria pada satu rubrik
+const maxRubricCriteria = 20
+
+// SetDivisionRubricHandler mengganti rubrik penilaian wawancara divisi (Admin only).
+// Mengirim criteria kosong menghapus rubrik; penilaian yang sudah ada tidak berubah.
+func (h *Handler) SetDivisionRubricHandler(w http.ResponseWriter, r *http.Request) {
+	divisionID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID divisi tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	division, err := h.divisions.FindByID(ctx, divisionID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeDivisionNotFound, "Divisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+		return
+	}
+
+	rubric := model.InterviewRubric{ScaleMin: 1, ScaleMax: 5}
+	if err := json.NewDecoder(r.Body).Decode(&rubric); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	now := time.Now()
+	var stored *model.InterviewRubric
+	if len(rubric.Criteria) > 0 {
+		if errs := validateRubric(&rubric); len(errs) > 0 {
+			writeValidationErrors(w, r, errs)
+			return
+		}
+		rubric.UpdatedAt = primitive.NewDateTimeFromTime(now)
+		stored = &rubric
+	}
+
+	err = h.divisions.SetRubric(ctx, divisionID, stored, now)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeDivisionNotFound, "Divisi tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan rubrik penilaian")
+		return
+	}
+
+	division.Rubric = stored
+	division.UpdatedAt = primitive.NewDateTimeFromTime(now)
+	response.JSON(w, http.StatusOK, division)
+}
+
+// validateRubric merapikan lalu memvalidasi skala dan setiap kriteria rubrik
+func validateRubric(rubric *model.InterviewRubric) validation.Errors {
+	errs := validation.Struct(rubric)
+	if !errs.Has("scale_min") && !errs.Has("scale_max") && rubric.ScaleMax <= rubric.ScaleMin {
+		errs.Add("scale_max", "INVALID_RANGE", "Nilai maksimum harus lebih besar dari nilai minimum")
+	}
+	if len(rubric.Criteria) > maxRubricCriteria {
+		errs.Add("criteria", "TOO_MANY_CRITERIA", fmt.Sprintf("Paling banyak %d kriteria", maxRubricCriteria))
+	}
+
+	keys := make(map[string]bool)
+	for i := range rubric.Criteria {
+		criterion := &rubric.Criteria[i]
+		criterion.Key = strings.ToLower(strings.TrimSpace(criterion.Key))
+		criterion.Name = strings.TrimSpace(criterion.Name)
+		criterion.Description = strings.TrimSpace(criterion.Description)
+
+		prefix := fmt.Sprintf("criteria[%d].", i)
+		for _, fieldErr := range validation.Struct(criterion) {
+			errs.Add(prefix+fieldErr.Field, fieldErr.Code, fieldErr.Message)
+		}
+		if keys[criterion.Key] {
+			errs.Add(prefix+"key", "DUPLICATE_KEY", "Kode kriteria "+criterion.Key+" sudah dipakai")
+		}
+		keys[criterion.Key] = true
+	}
+	return errs
+}
+
+// scoreableStatus mengecek apakah pendaftaran dengan status tersebut boleh dinilai.
+// Status akhir tetap boleh agar nilai bisa dikoreksi setelah keputusan.
+func scoreableStatus(status string) bool {
+	switch status {
+	case model.RegistrationStatusInterview, model.RegistrationStatusAccepted, model.RegistrationStatusRejected:
+		return true
+	}
+	return false
+}
+
+// scoreRubric mencocokkan nilai yang dikirim dengan kriteria rubrik dan menghitung totalnya,
+// yaitu rata-rata berbobot yang dinormalisasi ke 0-100
+func scoreRubric(rubric *model.InterviewRubric, values map[string]int) ([]model.CriterionScore, float64, validation.Errors) {
+	var errs validation.Errors
+	scores := make([]model.CriterionScore, 0, len(rubric.Criteria))
+	known := make(map[string]bool, len(rubric.Criteria))
+	var weighted, totalWeight float64
+	for _, criterion := range rubric.Criteria {
+		known[criterion.Key] = true
+		field := "scores." + criterion.Key
+		value, ok := values[criterion.Key]
+		switch {
+		case !ok:
+			errs.Add(field, validation.CodeRequired, "Nilai "+criterion.Name+" wajib diisi")
+			continue
+		case value < rubric.ScaleMin:
+			errs.Add(field, validation.CodeMinValue, fmt.Sprintf("Nilai %s tidak boleh kurang dari %d", criterion.Name, rubric.ScaleMin))
+			continue
+		case value > rubric.ScaleMax:
+			errs.Add(field, validation.CodeMaxValue, fmt.Sprintf("Nilai %s tidak boleh lebih dari %d", criterion.Name, rubric.ScaleMax))
+			continue
+		}
+		scores = append(scores, model.CriterionScore{Key: criterion.Key, Score: value})
+		weighted += float64(criterion.Weight) * float64(value-rubric.ScaleMin) / float64(rubric.ScaleMax-rubric.ScaleMin)
+		totalWeight += float64(criterion.Weight)
+	}
+
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs.Add("scores."+key, validation.CodeInvalidOption, "Kriteria "+key+" tidak ada pada rubrik")
+	}
+
+	if len(errs) > 0 || totalWeight == 0 {
+		return nil, 0, errs
+	}
+	return scores, math.Round(weighted/totalWeight*10000) / 100, nil
+}
+
+// SubmitInterviewScoreHandler menyimpan penilaian wawancara admin yang login untuk satu pendaftaran
+// dan divisi (default pilihan 1). Jika pendaftaran memesan slot yang memiliki pewawancara, hanya
+// pewawancara tersebut atau super admin yang boleh menilai. Mengirim ulang menggantikan penilaian sebelumnya.
+func (h *Handler) SubmitInterviewScoreHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	regID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidID, "ID pendaftaran tidak valid")
+		return
+	}
+
+	ctx := r.Context()
+	registration, err := h.registrations.FindByID(ctx, regID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Pendaftaran tidak ditemukan")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+	if !scoreableStatus(registration.Status) {
+		writeNotInInterviewStage(w, r)
+		return
+	}
+
+	if registration.InterviewSlotID != nil && actor.Role != "super_admin" {
+		slot, err := h.interviewSlots.FindByID(ctx, *registration.InterviewSlotID)
+		if err != nil && err != repository.ErrNotFound {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil slot wawancara")
+			return
+		}
+		if err == nil && len(slot.Interviewers) > 0 && !containsID(slot.Interviewers, actor.UserID) {
+			response.Error(w, r, http.StatusForbidden, response.CodeNotInterviewer, "Hanya pewawancara pada slot pendaftar yang dapat memberi nilai")
+			return
+		}
+	}
+
+	var body struct {
+		Division string         `json:"division"`
+		Scores   map[string]int `json:"scores"`
+		Comment  string         `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
+		return
+	}
+
+	divisionName := registration.Division1
+	if name := strings.TrimSpace(body.Division); name != "" {
+		switch {
+		case strings.EqualFold(name, registration.Division1):
+		case strings.EqualFold(name, registration.Division2):
+			divisionName = registration.Division2
+		default:
+			var errs validation.Errors
+			errs.Add("division", validation.CodeInvalidOption, "Divisi harus salah satu pilihan pendaftar")
+			writeValidationErrors(w, r, errs)
+			return
+		}
+	}
+
+	division, err := h.divisions.FindByName(ctx, divisionName)
+	if err != nil && err != repository.ErrNotFound {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+		return
+	}
+	if err == repository.ErrNotFound || division.Rubric == nil {
+		response.Error(w, r, http.StatusConflict, response.CodeRubricNotConfigured, "Rubrik penilaian divisi "+divisionName+" belum diatur")
+		return
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	score := model.InterviewScore{
+		RegistrationID: regID,
+		InterviewerID:  actor.UserID,
+		Division:       division.Name,
+		Comment:        strings.TrimSpace(body.Comment),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	scores, total, errs := scoreRubric(division.Rubric, body.Scores)
+	errs = append(errs, validation.Struct(&score)...)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+	score.Scores = scores
+	score.Total = total
+
+	if err := h.scores.Upsert(ctx, &score); err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan penilaian")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, score)
+}
+
+// interviewScoreEntry adalah penilaian beserta nama pewawancaranya
+type interviewScoreEntry struct {
+	model.InterviewScore
+	InterviewerName string `json:"interviewer_name,omitempty"`
+}
+
+// criterionSummary adalah rata-rata nilai satu kriteria dari semua pewawancara
+type criterionSummary struct {
+	Key     string  `json:"key"`
+	Name    string  `json:"name,omitempty"`
+	Average float64 `json:"average"`
+}
+
+// divisionScoreSummary adalah ringkasan penilaian satu pendaftaran untuk satu divisi
+type divisionScoreSummary struct {
+	Division string             `json:"division"`
+	Average  float64            `json:"average"`
+	Count    int                `json:"count"`
+	Criteria []criterionSummary `json:"criteria"`
+}
+
+// GetRegistrationScoresHandler mengembalikan semua penilaian wawancara satu pendaftaran beserta
+// rata-rata keseluruhan dan rata-rata per divisi dan per kriteria (Admin only)
+func (h *Handler) GetRegistrationScoresHandler(w http.ResponseWriter, r *http.Request) {
+	regID, ok := h.registrationFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	scores, err := h.scores.ListByRegistration(ctx, regID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil penilaian wawancara")
+		return
+	}
+
+	userName := h.userNames(ctx)
+	entries := make([]interviewScoreEntry, len(scores))
+	byDivision := make(map[string][]model.InterviewScore)
+	var divisionOrder []string
+	var sum float64
+	for i, score := range scores {
+		entries[i] = interviewScoreEntry{InterviewScore: score, InterviewerName: userName(score.InterviewerID)}
+		if _, ok := byDivision[score.Division]; !ok {
+			divisionOrder = append(divisionOrder, score.Division)
+		}
+		byDivision[score.Division] = append(byDivision[score.Division], score)
+		sum += score.Total
+	}
+
+	summaries := make([]divisionScoreSummary, 0, len(divisionOrder))
+	for _, name := range divisionOrder {
+		// Nama kriteria diambil dari rubrik saat ini; kriteria yang sudah dihapus tetap ditampilkan dengan kodenya
+		var rubric *model.InterviewRubric
+		if division, err := h.divisions.FindByName(ctx, name); err == nil {
+			rubric = division.Rubric
+		}
+		summaries = append(summaries, summarizeScores(name, byDivision[name], rubric))
+	}
+
+	var average *float64
+	if len(scores) > 0 {
+		avg := math.Round(sum/float64(len(scores))*100) / 100
+		average = &avg
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"average":   average,
+		"count":     len(scores),
+		"divisions": summaries,
+		"scores":    entries,
+	})
+}
+
+func summarizeScores(division string, scores []model.InterviewScore, rubric *model.InterviewRubric) divisionScoreSummary {
+	summary := divisionScoreSummary{Division: division, Count: len(scores), Criteria: []criterionSummary{}}
+
+	var keys []string
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	var total float64
+	for _, score := range scores {
+		total += score.Total
+		for _, value := range score.Scores {
+			if counts[value.Key] == 0 {
+				keys = append(keys, value.Key)
+			}
+			sums[value.Key] += float64(value.Score)
+			counts[value.Key]++
+		}
+	}
+	summary.Average = math.Round(total/float64(len(scores))*100) / 100
+
+	names := make(map[string]string)
+	if rubric != nil {
+		for _, criterion := range rubric.Criteria {
+			names[criterion.Key] = criterion.Name
+		}
+	}
+	for _, key := range keys {
+		summary.Criteria = append(summary.Criteria, criterionSummary{
+			Key:     key,
+			Name:    names[key],
+			Average: math.Round(sums[key]/float64(counts[key])*100) / 100,
+		})
+	}
+	return summary
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	rubricPath = "/api/admin/divisions/{id}/rubric"
+	scoresPath = "/api/admin/registrations/{id}/scores"
+)
+
+// webRubric berbobot komunikasi 2 dan teknis 1 dengan skala 1-5
+var webRubric = map[string]interface{}{
+	"scale_min": 1, "scale_max": 5,
+	"criteria": []map[string]interface{}{
+		{"key": "Komunikasi", "name": "Komunikasi", "weight": 2},
+		{"key": "teknis", "name": "Kemampuan teknis", "weight": 1},
+	},
+}
+
+// setRubric mengirim rubrik divisi lewat SetDivisionRubricHandler
+func (e *testEnv) setRubric(t *testing.T, admin *model.User, division string, rubric interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	found, err := e.repos.Divisions.FindByName(context.Background(), division)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e.serve(t, "PUT", rubricPath, "/api/admin/divisions/"+found.ID.Hex()+"/rubric", e.h.SetDivisionRubricHandler, admin, rubric)
+}
+
+// submitScore mengirim penilaian wawancara untuk satu pendaftaran
+func (e *testEnv) submitScore(t *testing.T, interviewer *model.User, regID primitive.ObjectID, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return e.serve(t, "PUT", scoresPath, "/api/admin/registrations/"+regID.Hex()+"/scores", e.h.SubmitInterviewScoreHandler, interviewer, body)
+}
+
+func TestInterviewScoreHandlers(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	first := env.createUser(t, "900001", "admin")
+	second := env.createUser(t, "900002", "admin")
+	_, registration := env.createInterviewee(t, period, "714220001")
+
+	if rec := env.setRubric(t, first, "Web", webRubric); rec.Code != http.StatusOK {
+		t.Fatalf("rubric: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := env.submitScore(t, first, registration.ID, map[string]interface{}{"scores": map[string]int{"komunikasi": 2, "teknis": 1}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("score: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	// Mengirim ulang menggantikan penilaian sebelumnya
+	rec = env.submitScore(t, first, registration.ID, map[string]interface{}{"scores": map[string]int{"komunikasi": 5, "teknis": 3}, "comment": " Lancar "})
+	var score model.InterviewScore
+	decode(t, rec, &score)
+	if score.Division != "Web" || score.Total != 83.33 || score.Comment != "Lancar" {
+		t.Errorf("penilaian %+v, ingin divisi Web dengan total 83.33", score)
+	}
+	if rec := env.submitScore(t, second, registration.ID, map[string]interface{}{"scores": map[string]int{"komunikasi": 3, "teknis": 1}}); rec.Code != http.StatusOK {
+		t.Fatalf("score kedua: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = env.serve(t, "GET", scoresPath, "/api/admin/registrations/"+registration.ID.Hex()+"/scores", env.h.GetRegistrationScoresHandler, first, nil)
+	var body struct {
+		Average   float64                `json:"average"`
+		Count     int                    `json:"count"`
+		Divisions []divisionScoreSummary `json:"divisions"`
+		Scores    []interviewScoreEntry  `json:"scores"`
+	}
+	decode(t, rec, &body)
+	if body.Count != 2 || body.Average != 58.33 || len(body.Scores) != 2 || body.Scores[0].InterviewerName == "" {
+		t.Fatalf("ringkasan %+v, ingin dua penilaian dengan rata-rata 58.33", body)
+	}
+	if len(body.Divisions) != 1 || len(body.Divisions[0].Criteria) != 2 {
+		t.Fatalf("ringkasan divisi %+v", body.Divisions)
+	}
+	if criterion := body.Divisions[0].Criteria[0]; criterion.Key != "komunikasi" || criterion.Name != "Komunikasi" || criterion.Average != 4 {
+		t.Errorf("rata-rata kriteria %+v, ingin komunikasi 4", criterion)
+	}
+}
+
+func TestSetDivisionRubricHandlerValidates(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	admin := env.createUser(t, "900001", "admin")
+
+	rec := env.setRubric(t, admin, "Web", map[string]interface{}{
+		"scale_min": 5, "scale_max": 5,
+		"criteria": []map[string]interface{}{
+			{"key": "teknis", "name": "Teknis", "weight": 1},
+			{"key": "Teknis", "name": "", "weight": 0},
+		},
+	})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	for _, field := range []string{"scale_max", "criteria[1].key", "criteria[1].name", "criteria[1].weight"} {
+		if !hasFieldError(t, rec, field) {
+			t.Errorf("ingin kesalahan pada %s: %s", field, rec.Body.String())
+		}
+	}
+}
+
+func TestSubmitInterviewScoreHandlerRejects(t *testing.T) {
+	env := newTestEnv(t)
+	period := env.openPeriod(t)
+	admin := env.createUser(t, "900001", "admin")
+	user, registration := env.createInterviewee(t, period, "714220001")
+	env.setRubric(t, admin, "Web", webRubric)
+
+	rec := env.submitScore(t, admin, registration.ID, map[string]interface{}{"scores": map[string]int{"komunikasi": 6, "kerapian": 3}})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("nilai tidak valid: status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	for _, field := range []string{"scores.komunikasi", "scores.teknis", "scores.kerapian"} {
+		if !hasFieldError(t, rec, field) {
+			t.Errorf("ingin kesalahan pada %s: %s", field, rec.Body.String())
+		}
+	}
+
+	rec = env.submitScore(t, admin, registration.ID, map[string]interface{}{"division": "Data", "scores": map[string]int{"teknis": 3}})
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeRubricNotConfigured {
+		t.Errorf("tanpa rubrik: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeRubricNotConfigured)
+	}
+
+	pending := env.createRegistration(t, env.createUser(t, "714220002", "user"), model.RegistrationStatusPending)
+	rec = env.submitScore(t, admin, pending.ID, map[string]interface{}{"scores": map[string]int{"komunikasi": 3, "teknis": 3}})
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeNotInInterviewStage {
+		t.Errorf("belum wawancara: status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeNotInInterviewStage)
+	}
+
+	// Slot dengan pewawancara hanya boleh dinilai pewawancaranya atau super admin
+	interviewer := env.createUser(t, "900002", "admin")
+	startsAt := time.Now().Add(24 * time.Hour)
+	slots := []model.InterviewSlot{{
+		PeriodID: period.ID, StartsAt: primitive.NewDateTimeFromTime(startsAt), EndsAt: primitive.NewDateTimeFromTime(startsAt.Add(30 * time.Minute)),
+		Capacity: 1, Interviewers: []primitive.ObjectID{interviewer.ID},
+	}}
+	if err := env.repos.InterviewSlots.CreateMany(context.Background(), slots); err != nil {
+		t.Fatal(err)
+	}
+	if rec := env.serve(t, "PUT", bookSlotPath, bookSlotPath, env.h.BookInterviewSlotHandler, user, map[string]string{"slot_id": slots[0].ID.Hex()}); rec.Code != http.StatusOK {
+		t.Fatalf("book: status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	valid := map[string]interface{}{"scores": map[string]int{"komunikasi": 4, "teknis": 4}}
+	rec = env.submitScore(t, admin, registration.ID, valid)
+	if rec.Code != http.StatusForbidden || errorCode(t, rec) != response.CodeNotInterviewer {
+		t.Errorf("bukan pewawancara: status %d %s, ingin 403 %s", rec.Code, errorCode(t, rec), response.CodeNotInterviewer)
+	}
+	for _, allowed := range []*model.User{interviewer, env.createUser(t, "900003", "super_admin")} {
+		if rec := env.submitScore(t, allowed, registration.ID, valid); rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d, ingin 200: %s", allowed.Role, rec.Code, rec.Body.String())
+		}
+	}
+	if scores, _ := env.repos.Scores.ListByRegistration(context.Background(), registration.ID); len(scores) != 2 {
+		t.Errorf("%d penilaian tersimpan, ingin 2", len(scores))
+	}
+}
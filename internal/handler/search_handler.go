@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	// snippetRadius adalah jumlah karakter di kiri dan kanan kecocokan pertama pada cuplikan jawaban
+	snippetRadius = 60
+)
+
+// searchHighlight adalah cuplikan field yang cocok; kata yang cocok diapit <mark></mark>
+// dan sisanya sudah di-escape sebagai HTML
+type searchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// searchResult adalah satu pendaftar pada hasil pencarian. Pendaftar yang belum mendaftar
+// pada periode yang dicari tidak memiliki registration_id, status, maupun divisi.
+type searchResult struct {
+	RegistrationID *primitive.ObjectID `json:"registration_id,omitempty"`
+	UserID         primitive.ObjectID  `json:"user_id"`
+	Name           string              `json:"name"`
+	NIM            string              `json:"nim"`
+	Email          string              `json:"email"`
+	PhoneNumber    string              `json:"phone_number"`
+	Status         string              `json:"status,omitempty"`
+	Division1      string              `json:"division1,omitempty"`
+	Division2      string              `json:"division2,omitempty"`
+	Score          float64             `json:"score"`
+	Highlights     []searchHighlight   `json:"highlights"`
+}
+
+// SearchHandler mencari pendaftar berdasarkan nama, NIM, email, nomor telepon, serta isi motivasi
+// dan visi misi. Hasil diurutkan dari yang paling relevan dan bisa dibatasi dengan filter yang sama
+// seperti daftar pendaftar (period, status, division, submitted_from, submitted_to).
+func (h *Handler) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.registrationFilter(w, r)
+	if !ok {
+		return
+	}
+	// q di sini adalah kata kunci pencarian teks, bukan filter nama/NIM pada ListDetails
+	q := filter.Search
+	filter.Search = ""
+
+	var errs validation.Errors
+	validation.Var(&errs, "q", "Kata kunci", q, "required,min=2")
+	limit := queryInt(&errs, r.URL.Query().Get("limit"), "limit", "Jumlah hasil", defaultSearchLimit, 1, maxSearchLimit)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
+		return
+	}
+
+	// Tanpa filter status, divisi, atau waktu kirim, user yang belum mendaftar tetap ditampilkan.
+	// Jika ada, filter pendaftaran ikut dijalankan pada pencarian user agar limit berlaku setelahnya.
+	includeUnregistered := len(filter.Statuses) == 0 && filter.Division == "" &&
+		filter.SubmittedFrom.IsZero() && filter.SubmittedBefore.IsZero()
+	var scope *repository.RegistrationFilter
+	if !includeUnregistered {
+		scope = &filter
+	}
+
+	ctx := r.Context()
+	userMatches, err := h.users.Search(ctx, q, scope, limit)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mencari data pendaftar")
+		return
+	}
+	answerMatches, err := h.registrations.SearchAnswers(ctx, q, filter, limit)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mencari data pendaftaran")
+		return
+	}
+
+	terms := searchTerms(q)
+	results := map[primitive.ObjectID]*searchResult{}
+	var order []primitive.ObjectID
+	add := func(key primitive.ObjectID, result *searchResult) *searchResult {
+		if existing, ok := results[key]; ok {
+			return existing
+		}
+		results[key] = result
+		order = append(order, key)
+		return result
+	}
+
+	// User yang cocok dipetakan ke pendaftarannya pada cakupan filter
+	if len(userMatches) > 0 {
+		userIDs := make([]primitive.ObjectID, 0, len(userMatches))
+		for _, match := range userMatches {
+			userIDs = append(userIDs, match.User.ID)
+		}
+		scoped := filter
+		scoped.UserIDs = userIDs
+		details, _, err := h.registrations.ListDetails(ctx, scoped)
+		if err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+			return
+		}
+		registered := make(map[primitive.ObjectID][]model.RegistrationDetail, len(details))
+		for _, detail := range details {
+			registered[detail.UserID] = append(registered[detail.UserID], detail)
+		}
+
+		for _, match := range userMatches {
+			highlights := userHighlights(&match.User, terms)
+			if len(registered[match.User.ID]) == 0 {
+				if includeUnregistered {
+					result := add(match.User.ID, userSearchResult(&match.User))
+					result.Score += match.Score
+					result.Highlights = append(result.Highlights, highlights...)
+				}
+				continue
+			}
+			for i := range registered[match.User.ID] {
+				detail := &registered[match.User.ID][i]
+				result := add(detail.ID, detailSearchResult(detail))
+				result.Score += match.Score
+				result.Highlights = append(result.Highlights, highlights...)
+			}
+		}
+	}
+
+	if len(answerMatches) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(answerMatches))
+		for _, match := range answerMatches {
+			ids = append(ids, match.Registration.ID)
+		}
+		details, _, err := h.registrations.ListDetails(ctx, repository.RegistrationFilter{IDs: ids})
+		if err != nil {
+			response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+			return
+		}
+		byID := make(map[primitive.ObjectID]*model.RegistrationDetail, len(details))
+		for i := range details {
+			byID[details[i].ID] = &details[i]
+		}
+		for _, match := range answerMatches {
+			detail, ok := byID[match.Registration.ID]
+			if !ok {
+				// Pendaftaran yang user-nya sudah dihapus
+				continue
+			}
+			result := add(detail.ID, detailSearchResult(detail))
+			result.Score += match.Score
+			for _, field := range []struct{ name, text string }{
+				{"motivation", detail.Motivation}, {"vision_mission", detail.VisionMission},
+			} {
+				if snippet, ok := highlight(field.text, terms, snippetRadius); ok {
+					result.Highlights = append(result.Highlights, searchHighlight{Field: field.name, Snippet: snippet})
+				}
+			}
+		}
+	}
+
+	list := make([]searchResult, 0, len(order))
+	for _, key := range order {
+		list = append(list, *results[key])
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Score > list[j].Score })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"query":   q,
+		"total":   len(list),
+		"results": list,
+	})
+}
+
+func userSearchResult(user *model.User) *searchResult {
+	return &searchResult{
+		UserID:      user.ID,
+		Name:        user.Name,
+		NIM:         user.NIM,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Highlights:  []searchHighlight{},
+	}
+}
+
+func detailSearchResult(detail *model.RegistrationDetail) *searchResult {
+	id := detail.ID
+	return &searchResult{
+		RegistrationID: &id,
+		UserID:         detail.UserID,
+		Name:           detail.Name,
+		NIM:            detail.NIM,
+		Email:          detail.Email,
+		PhoneNumber:    detail.PhoneNumber,
+		Status:         detail.Status,
+		Division1:      detail.Division1,
+		Division2:      detail.Division2,
+		Highlights:     []searchHighlight{},
+	}
+}
+
+// userHighlights menandai kata kunci pada data diri user yang cocok
+func userHighlights(user *model.User, terms []string) []searchHighlight {
+	var highlights []searchHighlight
+	for _, field := range []struct{ name, text string }{
+		{"name", user.Name}, {"nim", user.NIM}, {"email", user.Email}, {"phone_number", user.PhoneNumber},
+	} {
+		if snippet, ok := highlight(field.text, terms, 0); ok {
+			highlights = append(highlights, searchHighlight{Field: field.name, Snippet: snippet})
+		}
+	}
+	return highlights
+}
+
+// searchTerms mengembalikan kata kunci yang ditandai pada cuplikan: seluruh query (untuk
+// kecocokan sebagian) dan setiap katanya
+func searchTerms(q string) []string {
+	terms := []string{strings.ToLower(q)}
+	for _, word := range strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 2 && !containsTerm(terms, word) {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func containsTerm(terms []string, term string) bool {
+	for _, t := range terms {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight mengapit setiap kecocokan terms (huruf kecil) pada text dengan <mark></mark> dan
+// meng-escape sisanya sebagai HTML. radius > 0 memotong text menjadi cuplikan di sekitar
+// kecocokan pertama. Mengembalikan false jika tidak ada yang cocok.
+func highlight(text string, terms []string, radius int) (string, bool) {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// marked[i] true jika karakter ke-i termasuk kecocokan
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		needle := []rune(term)
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) != term {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			if first == -1 || i < first {
+				first = i
+			}
+		}
+	}
+	if first == -1 {
+		return "", false
+	}
+
+	start, end := 0, len(runes)
+	if radius > 0 {
+		if first-radius > start {
+			start = first - radius
+		}
+		if first+radius*2 < end {
+			end = first + radius*2
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		segment := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			segment = "<mark>" + segment + "</mark>"
+		}
+		b.WriteString(segment)
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
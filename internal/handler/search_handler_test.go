@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const searchPath = "/api/admin/search"
+
+type searchBody struct {
+	Total   int `json:"total"`
+	Results []struct {
+		RegistrationID *primitive.ObjectID `json:"registration_id"`
+		UserID         primitive.ObjectID  `json:"user_id"`
+		Status         string              `json:"status"`
+		Highlights     []searchHighlight   `json:"highlights"`
+	} `json:"results"`
+}
+
+// registerIn menyimpan pendaftaran milik user pada periode tertentu
+func (e *testEnv) registerIn(t *testing.T, user *model.User, periodID primitive.ObjectID, status string) *model.Registration {
+	t.Helper()
+	registration := &model.Registration{
+		UserID: user.ID, PeriodID: periodID, Division1: "Web", Division2: "Data", Motivation: "m", VisionMission: "v", Status: status,
+	}
+	if err := e.repos.Registrations.Create(context.Background(), registration); err != nil {
+		t.Fatal(err)
+	}
+	return registration
+}
+
+func TestSearchHandlerIncludesUnregisteredUsers(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	period := env.openPeriod(t)
+	registered := env.createUser(t, "714220001", "user")
+	registration := env.registerIn(t, registered, period.ID, model.RegistrationStatusPending)
+	unregistered := env.createUser(t, "714220002", "user")
+
+	rec := env.serve(t, "GET", searchPath, searchPath+"?q=71422", env.h.SearchHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var body searchBody
+	decode(t, rec, &body)
+	if body.Total != 2 {
+		t.Fatalf("total %d, ingin 2: %s", body.Total, rec.Body.String())
+	}
+	for _, result := range body.Results {
+		switch result.UserID {
+		case registered.ID:
+			if result.RegistrationID == nil || *result.RegistrationID != registration.ID || result.Status != model.RegistrationStatusPending {
+				t.Errorf("hasil pendaftar tidak membawa pendaftarannya: %+v", result)
+			}
+		case unregistered.ID:
+			if result.RegistrationID != nil {
+				t.Errorf("user yang belum mendaftar membawa registration_id: %+v", result)
+			}
+		default:
+			t.Errorf("hasil tak terduga: %+v", result)
+		}
+		marked := false
+		for _, h := range result.Highlights {
+			marked = marked || (h.Field == "nim" && strings.HasPrefix(h.Snippet, "<mark>71422</mark>"))
+		}
+		if !marked {
+			t.Errorf("NIM tidak ditandai: %+v", result.Highlights)
+		}
+	}
+}
+
+func TestSearchHandlerAppliesFilterBeforeLimit(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	period := env.openPeriod(t)
+	oldPeriod := primitive.NewObjectID()
+
+	// User yang dibuat lebih dulu diterima pada periode lain sehingga tidak masuk cakupan filter
+	for _, nim := range []string{"714220001", "714220002", "714220003"} {
+		env.registerIn(t, env.createUser(t, nim, "user"), oldPeriod, model.RegistrationStatusAccepted)
+	}
+	env.registerIn(t, env.createUser(t, "714220004", "user"), period.ID, model.RegistrationStatusPending)
+	want := map[primitive.ObjectID]bool{}
+	for _, nim := range []string{"714220005", "714220006"} {
+		want[env.registerIn(t, env.createUser(t, nim, "user"), period.ID, model.RegistrationStatusAccepted).ID] = true
+	}
+
+	path := searchPath + "?q=71422&limit=2&status=accepted&period=" + period.ID.Hex()
+	rec := env.serve(t, "GET", searchPath, path, env.h.SearchHandler, admin, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var body searchBody
+	decode(t, rec, &body)
+	if body.Total != 2 {
+		t.Fatalf("total %d, ingin 2: %s", body.Total, rec.Body.String())
+	}
+	for _, result := range body.Results {
+		if result.RegistrationID == nil || !want[*result.RegistrationID] {
+			t.Errorf("hasil di luar filter: %+v", result)
+		}
+	}
+}
+
+func TestSearchHandlerValidatesQuery(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+
+	rec := env.serve(t, "GET", searchPath, searchPath+"?q=a&limit=100", env.h.SearchHandler, admin, nil)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "q") || !hasFieldError(t, rec, "limit") {
+		t.Errorf("ingin kesalahan pada q dan limit: %s", rec.Body.String())
+	}
+}
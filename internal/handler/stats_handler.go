@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type statusCount struct {
+	Status string `json:"status"`
+	Label  string `json:"label"`
+	Count  int64  `json:"count"`
+}
+
+type divisionCount struct {
+	Division     string `json:"division"`
+	FirstChoice  int64  `json:"first_choice"`
+	SecondChoice int64  `json:"second_choice"`
+	Total        int64  `json:"total"`
+}
+
+type dailyCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// funnelStage adalah satu tahap corong rekrutmen; Rate adalah persentase terhadap tahap sebelumnya
+type funnelStage struct {
+	Stage string   `json:"stage"`
+	Label string   `json:"label"`
+	Count int64    `json:"count"`
+	Rate  *float64 `json:"rate"`
+}
+
+type statusDuration struct {
+	Status         string  `json:"status"`
+	Label          string  `json:"label"`
+	Transitions    int64   `json:"transitions"`
+	AverageSeconds int64   `json:"average_seconds"`
+	AverageHours   float64 `json:"average_hours"`
+}
+
+// registrationStats adalah respons dasbor statistik rekrutmen
+type registrationStats struct {
+	PeriodID          *primitive.ObjectID `json:"period_id"`
+	TotalRegistered   int64               `json:"total_registered_users"`
+	TotalSubmitted    int64               `json:"total_registrations"`
+	ByStatus          []statusCount       `json:"by_status"`
+	ByDivision        []divisionCount     `json:"by_division"`
+	SubmissionsPerDay []dailyCount        `json:"submissions_per_day"`
+	Funnel            []funnelStage       `json:"funnel"`
+	TimeInStatus      []statusDuration    `json:"time_in_status"`
+}
+
+// GetStatsHandler mengembalikan statistik rekrutmen untuk dasbor admin: jumlah pendaftaran per status
+// dan pilihan divisi, pendaftaran per hari, corong hingga diterima, serta lama rata-rata pendaftaran
+// berada pada setiap status. Query period sama seperti daftar pendaftar.
+func (h *Handler) GetStatsHandler(w http.ResponseWriter, r *http.Request) {
+	periodID, ok := h.periodFilter(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	stats, err := h.registrations.Stats(ctx, repository.RegistrationFilter{PeriodID: periodID})
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghitung statistik pendaftaran")
+		return
+	}
+	registered, err := h.users.CountByRole(ctx, "user")
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menghitung jumlah user")
+		return
+	}
+	divisions, err := h.divisions.List(ctx, false)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data divisi")
+		return
+	}
+
+	result := registrationStats{
+		TotalRegistered:   registered,
+		TotalSubmitted:    stats.Total,
+		ByStatus:          []statusCount{},
+		ByDivision:        []divisionCount{},
+		SubmissionsPerDay: []dailyCount{},
+		TimeInStatus:      []statusDuration{},
+	}
+	if !periodID.IsZero() {
+		result.PeriodID = &periodID
+	}
+
+	// Semua status pada alur selalu ditampilkan; status lama di luar alur menyusul di akhir
+	for _, status := range model.RegistrationStatuses {
+		result.ByStatus = append(result.ByStatus, statusCount{Status: status.Value, Label: status.Label, Count: stats.ByStatus[status.Value]})
+	}
+	for _, status := range sortedKeys(stats.ByStatus) {
+		if _, ok := model.FindRegistrationStatus(status); !ok {
+			result.ByStatus = append(result.ByStatus, statusCount{Status: status, Label: status, Count: stats.ByStatus[status]})
+		}
+	}
+
+	// Divisi pada katalog selalu ditampilkan, termasuk yang belum dipilih siapa pun
+	listed := map[string]bool{}
+	addDivision := func(name string) {
+		if listed[name] {
+			return
+		}
+		listed[name] = true
+		first, second := stats.ByDivision1[name], stats.ByDivision2[name]
+		result.ByDivision = append(result.ByDivision, divisionCount{Division: name, FirstChoice: first, SecondChoice: second, Total: first + second})
+	}
+	for _, division := range divisions {
+		addDivision(division.Name)
+	}
+	for _, counts := range []map[string]int64{stats.ByDivision1, stats.ByDivision2} {
+		for _, name := range sortedKeys(counts) {
+			if name != "" {
+				addDivision(name)
+			}
+		}
+	}
+
+	for _, day := range stats.PerDay {
+		result.SubmissionsPerDay = append(result.SubmissionsPerDay, dailyCount{Date: day.Date, Count: day.Count})
+	}
+
+	// Akun tidak terikat periode, jadi tahap akun terdaftar hanya masuk corong tanpa filter periode
+	var stages []funnelStage
+	if periodID.IsZero() {
+		stages = append(stages, funnelStage{Stage: "registered", Label: "Akun terdaftar", Count: registered})
+	}
+	stages = append(stages, funnelStage{Stage: "submitted", Label: "Mengirim pendaftaran", Count: stats.Total})
+	for _, status := range []string{model.RegistrationStatusDocumentReview, model.RegistrationStatusInterview, model.RegistrationStatusAccepted} {
+		info, _ := model.FindRegistrationStatus(status)
+		stages = append(stages, funnelStage{Stage: status, Label: info.Label, Count: stats.Reached[status]})
+	}
+	for i := 1; i < len(stages); i++ {
+		if previous := stages[i-1].Count; previous > 0 {
+			rate := math.Round(float64(stages[i].Count)/float64(previous)*10000) / 100
+			stages[i].Rate = &rate
+		}
+	}
+	result.Funnel = stages
+
+	for _, status := range model.RegistrationStatuses {
+		duration, ok := stats.Durations[status.Value]
+		if !ok {
+			continue
+		}
+		result.TimeInStatus = append(result.TimeInStatus, statusDuration{
+			Status:         status.Value,
+			Label:          status.Label,
+			Transitions:    duration.Count,
+			AverageSeconds: int64(duration.Average.Seconds()),
+			AverageHours:   math.Round(duration.Average.Hours()*100) / 100,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, result)
+}
+
+func sortedKeys(counts map[string]int64) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+)
+
+func (e *testEnv) funnel(t *testing.T, actor *model.User, query string) []funnelStage {
+	t.Helper()
+	rec := e.serve(t, "GET", "/api/admin/stats", "/api/admin/stats"+query, e.h.GetStatsHandler, actor, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+	var stats registrationStats
+	decode(t, rec, &stats)
+	return stats.Funnel
+}
+
+func TestGetStatsHandlerFunnel(t *testing.T) {
+	env := newTestEnv(t)
+	admin := env.createUser(t, "900001", "admin")
+	period := env.openPeriod(t)
+	applicant := env.createUser(t, "714220001", "user")
+	env.createUser(t, "714220002", "user") // belum mendaftar
+	env.createRegistration(t, applicant, model.RegistrationStatusPending)
+
+	// Tanpa filter periode corong dimulai dari akun terdaftar
+	all := env.funnel(t, admin, "?period=all")
+	if len(all) == 0 || all[0].Stage != "registered" || all[0].Count != 2 {
+		t.Errorf("period=all: tahap pertama %+v, ingin registered dengan 2 akun", all)
+	}
+
+	// Akun tidak terikat periode, jadi corong per periode dimulai dari pendaftaran
+	for _, query := range []string{"", "?period=" + period.ID.Hex()} {
+		stages := env.funnel(t, admin, query)
+		if len(stages) == 0 || stages[0].Stage != "submitted" || stages[0].Rate != nil {
+			t.Errorf("query %q: tahap pertama %+v, ingin submitted tanpa rate", query, stages)
+		}
+		for _, stage := range stages {
+			if stage.Stage == "registered" {
+				t.Errorf("query %q: tahap registered tidak boleh muncul dengan filter periode", query)
+			}
+		}
+	}
+}
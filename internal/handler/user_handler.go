@@ -7,25 +7,21 @@ import (
 	"fmt"
 	"io"
 	"log" // Dibutuhkan untuk log.Println di SubmitRegistrationHandler
+	"math"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
-	// "time"
-
-	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/cloudinary/cloudinary-go/v2/api"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
-	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
-	"github.com/ulbithebest/BE-pendaftaran/internal/config" // <-- PASTIKAN CONFIG DI-IMPORT
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
 	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
 	"github.com/ulbithebest/BE-pendaftaran/internal/model"
 	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -61,333 +57,519 @@ func validateUploadedFile(file multipart.File, header *multipart.FileHeader, all
 	return fmt.Errorf("tipe file %s tidak didukung", detectedMime)
 }
 
-func RegisterHandler(w http.ResponseWriter, r *http.Request) {
+// registrationFile mendeskripsikan satu berkas pada form pendaftaran
+type registrationFile struct {
+	field        string // nama field pada multipart form
+	label        string // nama berkas pada pesan kesalahan
+	suffix       string // akhiran Public ID di storage
+	resourceType string
+	required     bool
+	extensions   map[string]struct{}
+	mimePrefixes []string
+	url          func(*model.Registration) *string // field URL berkas pada model
+}
+
+var (
+	documentExtensions = map[string]struct{}{".png": {}, ".pdf": {}}
+	documentMimes      = []string{"image/png", "application/pdf"}
+	photoExtensions    = map[string]struct{}{".png": {}, ".jpg": {}, ".jpeg": {}}
+	photoMimes         = []string{"image/png", "image/jpeg"}
+)
+
+// registrationFiles adalah daftar berkas yang diterima form pendaftaran, berurutan
+var registrationFiles = []registrationFile{
+	{field: "cv", label: "CV", suffix: "cv", resourceType: "auto", required: true, extensions: documentExtensions, mimePrefixes: documentMimes,
+		url: func(reg *model.Registration) *string { return &reg.CvUrl }},
+	{field: "certificate", label: "Sertifikat Morris", suffix: "cert", resourceType: "auto", required: true, extensions: documentExtensions, mimePrefixes: documentMimes,
+		url: func(reg *model.Registration) *string { return &reg.CertificateUrl }},
+	{field: "optional_certificate", label: "Sertifikat Bebas", suffix: "optional_cert", resourceType: "auto", extensions: documentExtensions, mimePrefixes: documentMimes,
+		url: func(reg *model.Registration) *string { return &reg.OptionalCertificateUrl }},
+	{field: "formal_photo", label: "Foto formal", suffix: "formal_photo", resourceType: "image", required: true, extensions: photoExtensions, mimePrefixes: photoMimes,
+		url: func(reg *model.Registration) *string { return &reg.FormalPhotoUrl }},
+}
+
+// readRegistrationFiles memvalidasi berkas pada form pendaftaran dan mencatat kesalahannya ke errs.
+// Jika requireAll false (saat mengubah pendaftaran), berkas wajib boleh tidak dikirim.
+// Berkas yang valid dikembalikan per field dan harus ditutup pemanggil dengan closeFiles.
+func readRegistrationFiles(r *http.Request, errs *validation.Errors, requireAll bool) map[string]multipart.File {
+	files := make(map[string]multipart.File)
+	for _, spec := range registrationFiles {
+		file, header, err := r.FormFile(spec.field)
+		if err != nil {
+			if spec.required && requireAll {
+				errs.Add(spec.field, strings.ToUpper(spec.field)+"_REQUIRED", spec.label+" wajib diunggah")
+			}
+			continue
+		}
+
+		if err := validateUploadedFile(file, header, spec.extensions, spec.mimePrefixes); err != nil {
+			file.Close()
+			errs.Add(spec.field, strings.ToUpper(spec.field)+"_INVALID", fmt.Sprintf("File %s tidak valid: %s", spec.label, err.Error()))
+			continue
+		}
+		files[spec.field] = file
+	}
+	return files
+}
+
+func closeFiles(files map[string]multipart.File) {
+	for _, file := range files {
+		file.Close()
+	}
+}
+
+// uploadRegistrationFiles mengunggah berkas dengan Public ID yang unik per user lalu menyimpan
+// URL-nya ke registration dan mengembalikan URL yang baru diunggah. Jika gagal, berkas yang sudah
+// terunggah dihapus kembali dan label berkas yang gagal ikut dikembalikan.
+func (h *Handler) uploadRegistrationFiles(ctx context.Context, nim string, files map[string]multipart.File, registration *model.Registration) ([]string, string, error) {
+	var uploaded []string
+	for _, spec := range registrationFiles {
+		file, ok := files[spec.field]
+		if !ok {
+			continue
+		}
+
+		publicID := fmt.Sprintf("himatif-registrations/%s_%s_%d",
+			nim,
+			spec.suffix,
+			time.Now().UnixNano())
+
+		url, err := h.storage.Upload(ctx, file, publicID, spec.resourceType)
+		if err != nil {
+			log.Printf("Cloudinary %s upload error: %v", spec.field, err)
+			h.deleteUploadedFiles(ctx, uploaded)
+			return nil, spec.label, err
+		}
+		*spec.url(registration) = url
+		uploaded = append(uploaded, url)
+	}
+	return uploaded, "", nil
+}
+
+// deleteUploadedFiles menghapus berkas dari storage; kegagalannya hanya dicatat ke log
+func (h *Handler) deleteUploadedFiles(ctx context.Context, urls []string) {
+	for _, url := range urls {
+		if err := h.storage.Delete(ctx, url); err != nil {
+			log.Printf("failed to delete uploaded file %s: %v", url, err)
+		}
+	}
+}
+
+func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	var user model.User
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
-	// Validasi Nomor Telepon
-	if len(user.PhoneNumber) < 10 || len(user.PhoneNumber) > 13 {
-		http.Error(w, `{"error": "Nomor telepon harus antara 10 hingga 13 digit."}`, http.StatusBadRequest)
+	user.ID = primitive.NilObjectID
+	user.Role = "user"
+	trimUserFields(&user)
+
+	// Validasi seluruh field sekaligus agar user bisa memperbaiki semuanya dalam satu kali kirim
+	errs := validateNewUser(&user)
+	validatePassword(&errs, "password", "Password", user.Password)
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
 		return
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		http.Error(w, `{"error": "Failed to hash password"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memproses password")
 		return
 	}
 	user.Password = string(hashedPassword)
-	user.Role = "user"
-
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("users")
+	user.Disabled = false
+	user.EmailVerifiedAt = nil
 
 	// Cek duplikasi NIM
-	count, _ := collection.CountDocuments(context.TODO(), bson.M{"nim": user.NIM})
-	if count > 0 {
-		http.Error(w, `{"error": "NIM already registered"}`, http.StatusConflict)
+	exists, err := h.users.ExistsByNIM(r.Context(), user.NIM, primitive.NilObjectID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa NIM")
+		return
+	}
+	if exists {
+		response.Error(w, r, http.StatusConflict, response.CodeNIMAlreadyRegistered, "NIM sudah terdaftar")
 		return
 	}
 
-	// Simpan user ke database
-	_, err = collection.InsertOne(context.TODO(), user)
+	// Simpan user ke database; index unik NIM menolak registrasi bersamaan dengan NIM yang sama
+	err = h.users.Create(r.Context(), &user)
+	if err == repository.ErrDuplicate {
+		response.Error(w, r, http.StatusConflict, response.CodeNIMAlreadyRegistered, "NIM sudah terdaftar")
+		return
+	}
 	if err != nil {
-		http.Error(w, `{"error": "Failed to register user"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mendaftarkan user")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Registration successful"})
+	// Kegagalan kirim email tidak membatalkan registrasi, user bisa meminta kirim ulang
+	if err := h.sendVerificationEmail(r.Context(), &user); err != nil {
+		log.Printf("failed to send verification email for NIM %s: %v", user.NIM, err)
+	}
+
+	response.JSON(w, http.StatusCreated, map[string]string{"message": "Registration successful"})
 }
 
-func LoginHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	var creds struct {
 		NPM      string `json:"NPM"`
 		Password string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, "Format request tidak valid")
 		return
 	}
 
-	var user model.User
-	// PERBAIKAN: Gunakan nama database dari config, bukan hardcode
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("users")
-	err := collection.FindOne(context.TODO(), bson.M{"nim": creds.NPM}).Decode(&user)
+	ctx := r.Context()
+	ip := middleware.GetRequestIP(r)
+	now := time.Now()
+
+	// Tolak lebih awal jika NIM/IP sedang dalam masa jeda atau terkunci
+	retryAfter, err := h.loginRetryAfter(ctx, creds.NPM, ip, now)
 	if err != nil {
-		http.Error(w, `{"error": "Invalid NIM or password"}`, http.StatusUnauthorized)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa percobaan login")
+		return
+	}
+	if retryAfter > 0 {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		response.ErrorWithDetails(w, r, http.StatusTooManyRequests, response.CodeLoginLocked,
+			fmt.Sprintf("Terlalu banyak percobaan login. Coba lagi dalam %d detik.", seconds),
+			map[string]int{"retry_after": seconds})
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(creds.Password)); err != nil {
-		http.Error(w, `{"error": "Invalid NIM or password"}`, http.StatusUnauthorized)
+	user, err := h.users.FindByNIM(ctx, creds.NPM)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(creds.Password))
+	}
+	if err != nil {
+		if err := h.recordLoginFailure(ctx, creds.NPM, ip, now); err != nil {
+			log.Printf("failed to record login failure for NIM %s: %v", creds.NPM, err)
+		}
+		response.Error(w, r, http.StatusUnauthorized, response.CodeInvalidCredentials, "NIM atau password salah")
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.NIM, user.Role)
+	if err := h.resetLoginFailures(ctx, user.NIM); err != nil {
+		log.Printf("failed to reset login failures for NIM %s: %v", user.NIM, err)
+	}
+
+	if user.Disabled {
+		response.Error(w, r, http.StatusForbidden, response.CodeAccountDisabled, "Akun telah dinonaktifkan")
+		return
+	}
+
+	session, err := h.issueSession(ctx, user, primitive.NewObjectID())
 	if err != nil {
 		log.Printf("token generation failed for NIM %s: %v", user.NIM, err)
-		http.Error(w, `{"error": "Failed to generate token"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal membuat token")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"token": token,
-		"role":  user.Role,
-	})
+	response.JSON(w, http.StatusOK, session)
 }
 
-func SubmitRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SubmitRegistrationHandler(w http.ResponseWriter, r *http.Request) {
 	// 1. Dapatkan data user dari token
 	payload, ok := middleware.GetPayloadFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "User data not found"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
 		return
 	}
 
-	// 2. Parse form (beri ruang untuk multipart overhead, validasi per-file tetap 2MB)
-	if err := r.ParseMultipartForm(20 << 20); err != nil {
-		http.Error(w, `{"error": "File size exceeds limit"}`, http.StatusBadRequest)
-		return
+	// Jika kebijakan verifikasi email aktif, user wajib memverifikasi email sebelum mendaftar
+	if config.GetConfig().RequireEmailVerification {
+		user, err := h.users.FindByID(r.Context(), payload.UserID)
+		if err != nil {
+			response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
+			return
+		}
+		if user.EmailVerifiedAt == nil {
+			response.Error(w, r, http.StatusForbidden, response.CodeEmailNotVerified, "Email belum diverifikasi. Silakan verifikasi email Anda terlebih dahulu.")
+			return
+		}
 	}
 
-	// Ambil dua pilihan divisi dari form
-	division1 := r.FormValue("division1")
-	division2 := r.FormValue("division2")
+	// Pendaftaran hanya diterima selama periode rekrutmen aktif sedang dibuka
+	period, err := h.openPeriod(r.Context(), time.Now())
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusForbidden, response.CodeRegistrationClosed, "Pendaftaran sedang ditutup")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa periode rekrutmen")
+		return
+	}
 
-	// Validasi backend: pastikan dua pilihan tidak sama
-	if division1 == division2 {
-		http.Error(w, `{"error": "Pilihan Divisi 1 dan 2 tidak boleh sama."}`, http.StatusBadRequest)
+	// Setiap user hanya boleh mendaftar sekali per periode; perubahan lewat UpdateRegistrationHandler
+	_, err = h.registrations.FindByUserAndPeriod(r.Context(), payload.UserID, period.ID)
+	if err == nil {
+		writeAlreadyRegistered(w, r)
+		return
+	}
+	if err != repository.ErrNotFound {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa data pendaftaran")
 		return
 	}
 
-	// 3. Setup koneksi ke Cloudinary
-	cfg := config.GetConfig()
-	cld, err := cloudinary.NewFromParams(cfg.CloudinaryCloudName, cfg.CloudinaryApiKey, cfg.CloudinaryApiSecret)
-	if err != nil {
-		http.Error(w, `{"error": "Failed to connect to Cloudinary"}`, http.StatusInternalServerError)
+	// 2. Parse form (beri ruang untuk multipart overhead, validasi per-file tetap 2MB)
+	if err := r.ParseMultipartForm(20 << 20); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodePayloadTooLarge, "Ukuran berkas melebihi batas")
 		return
 	}
-	ctx := context.Background()
 
-	// 4. Proses Upload CV (Wajib)
-	file, fileHeader, err := r.FormFile("cv")
+	// Validasi isian form dan semua berkas sekaligus sebelum mengunggah apa pun
+	registration := model.Registration{
+		UserID:        payload.UserID,
+		PeriodID:      period.ID,
+		Division1:     strings.TrimSpace(r.FormValue("division1")),
+		Division2:     strings.TrimSpace(r.FormValue("division2")),
+		Motivation:    r.FormValue("motivation"),
+		VisionMission: r.FormValue("vision_mission"),
+	}
+	errs, err := h.validateRegistration(r.Context(), &registration)
 	if err != nil {
-		http.Error(w, `{"error": "CV file is required"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa pilihan divisi")
 		return
 	}
-	defer file.Close()
 
-	if err := validateUploadedFile(file, fileHeader, map[string]struct{}{
-		".png": {},
-		".pdf": {},
-	}, []string{"image/png", "application/pdf"}); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "File CV tidak valid: %s"}`, err.Error()), http.StatusBadRequest)
+	files := readRegistrationFiles(r, &errs, true)
+	defer closeFiles(files)
+
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
 		return
 	}
 
-	// Buat Public ID yang unik untuk CV
-	cvPublicID := fmt.Sprintf("himatif-registrations/%s_cv_%d",
-		payload.NIM,
-		time.Now().Unix())
+	ctx := r.Context()
 
-	uploadResult, err := cld.Upload.Upload(ctx, file, uploader.UploadParams{
-		PublicID:      cvPublicID,
-		ResourceType:  "auto",
-		Overwrite:     api.Bool(true),
-		AccessControl: []api.AccessControlRule{{AccessType: "anonymous"}},
-	})
+	// 3. Unggah berkas dengan Public ID yang unik per user
+	uploaded, label, err := h.uploadRegistrationFiles(ctx, payload.NIM, files, &registration)
 	if err != nil {
-		log.Printf("Cloudinary CV upload error: %v", err)
-		http.Error(w, `{"error": "Failed to upload CV"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeUploadFailed, "Gagal mengunggah "+label)
 		return
 	}
 
-	// CUKUP GUNAKAN URL ASLI DARI CLOUDINARY
-	cvUrl := uploadResult.SecureURL
+	// 4. Simpan URL dan data form yang sudah benar ke database
+	registration.Status = model.RegistrationStatusPending
+	registration.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
 
-	// 5. Proses Upload Sertifikat Morris (Wajib)
-	certFile, certHeader, err := r.FormFile("certificate")
+	err = h.registrations.Create(ctx, &registration)
 	if err != nil {
-		http.Error(w, `{"error": "Sertifikat Morris wajib diunggah"}`, http.StatusBadRequest)
+		// Berkas yang tidak jadi tersimpan tidak boleh tertinggal di storage
+		h.deleteUploadedFiles(ctx, uploaded)
+	}
+	if err == repository.ErrDuplicate {
+		// Dua submit bersamaan: yang kalah ditolak oleh index unik
+		writeAlreadyRegistered(w, r)
 		return
 	}
-	defer certFile.Close()
-
-	if err := validateUploadedFile(certFile, certHeader, map[string]struct{}{
-		".png": {},
-		".pdf": {},
-	}, []string{"image/png", "application/pdf"}); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "File Sertifikat Morris tidak valid: %s"}`, err.Error()), http.StatusBadRequest)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan pendaftaran")
 		return
 	}
 
-	// Buat Public ID yang unik untuk sertifikat
-	certPublicID := fmt.Sprintf("himatif-registrations/%s_cert_%d",
-		payload.NIM,
-		time.Now().Unix())
+	event := newRegistrationEvent(payload, registration.ID, model.RegistrationEventSubmitted, registration.UpdatedAt)
+	event.NewStatus = registration.Status
+	h.recordEvents(ctx, event)
+
+	response.JSON(w, http.StatusCreated, map[string]string{"message": "Registration submitted successfully"})
+}
+
+func writeAlreadyRegistered(w http.ResponseWriter, r *http.Request) {
+	response.Error(w, r, http.StatusConflict, response.CodeAlreadyRegistered,
+		"Anda sudah mengirim pendaftaran pada periode ini. Silakan ubah pendaftaran yang sudah ada.")
+}
 
-	certUploadResult, err := cld.Upload.Upload(ctx, certFile, uploader.UploadParams{
-		PublicID:      certPublicID,
-		ResourceType:  "auto",
-		Overwrite:     api.Bool(true),
-		AccessControl: []api.AccessControlRule{{AccessType: "anonymous"}},
-	})
+// UpdateRegistrationHandler mengubah jawaban dan mengganti berkas pendaftaran milik user pada periode
+// yang sedang dibuka, selama statusnya masih pending. Field dan berkas yang tidak dikirim tidak diubah.
+func (h *Handler) UpdateRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	payload, ok := middleware.GetPayloadFromContext(r.Context())
+	if !ok {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
+		return
+	}
+
+	ctx := r.Context()
+	period, err := h.openPeriod(ctx, time.Now())
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusForbidden, response.CodeRegistrationClosed, "Pendaftaran sedang ditutup")
+		return
+	}
 	if err != nil {
-		log.Printf("Cloudinary certificate upload error: %v", err)
-		http.Error(w, `{"error": "Failed to upload Sertifikat Morris"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa periode rekrutmen")
 		return
 	}
 
-	// CUKUP GUNAKAN URL ASLI DARI CLOUDINARY
-	certificateUrl := certUploadResult.SecureURL
+	existing, err := h.registrations.FindByUserAndPeriod(ctx, payload.UserID, period.ID)
+	if err == repository.ErrNotFound {
+		response.Error(w, r, http.StatusNotFound, response.CodeRegistrationNotFound, "Anda belum mengirim pendaftaran pada periode ini")
+		return
+	}
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+	if existing.Status != model.RegistrationStatusPending {
+		writeRegistrationLocked(w, r)
+		return
+	}
 
-	// 6. Proses Upload Sertifikat Bebas (Opsional)
-	optionalCertificateUrl := ""
-	optionalCertFile, optionalCertHeader, err := r.FormFile("optional_certificate")
-	if err == nil {
-		defer optionalCertFile.Close()
+	if err := r.ParseMultipartForm(20 << 20); err != nil {
+		response.Error(w, r, http.StatusBadRequest, response.CodePayloadTooLarge, "Ukuran berkas melebihi batas")
+		return
+	}
 
-		if err := validateUploadedFile(optionalCertFile, optionalCertHeader, map[string]struct{}{
-			".png": {},
-			".pdf": {},
-		}, []string{"image/png", "application/pdf"}); err != nil {
-			http.Error(w, fmt.Sprintf(`{"error": "File Sertifikat Bebas tidak valid: %s"}`, err.Error()), http.StatusBadRequest)
-			return
+	// Hanya field yang ikut dikirim yang menimpa jawaban lama
+	registration := *existing
+	answers := []struct {
+		field  string
+		target *string
+		trim   bool
+	}{
+		{"division1", &registration.Division1, true},
+		{"division2", &registration.Division2, true},
+		{"motivation", &registration.Motivation, false},
+		{"vision_mission", &registration.VisionMission, false},
+	}
+	for _, answer := range answers {
+		values, ok := r.MultipartForm.Value[answer.field]
+		if !ok || len(values) == 0 {
+			continue
 		}
-
-		optionalCertPublicID := fmt.Sprintf("himatif-registrations/%s_optional_cert_%d",
-			payload.NIM,
-			time.Now().Unix())
-
-		optionalCertUploadResult, err := cld.Upload.Upload(ctx, optionalCertFile, uploader.UploadParams{
-			PublicID:      optionalCertPublicID,
-			ResourceType:  "auto",
-			Overwrite:     api.Bool(true),
-			AccessControl: []api.AccessControlRule{{AccessType: "anonymous"}},
-		})
-		if err != nil {
-			log.Printf("Cloudinary optional certificate upload error: %v", err)
-			http.Error(w, `{"error": "Failed to upload sertifikat bebas"}`, http.StatusInternalServerError)
-			return
+		*answer.target = values[0]
+		if answer.trim {
+			*answer.target = strings.TrimSpace(values[0])
 		}
-
-		optionalCertificateUrl = optionalCertUploadResult.SecureURL
 	}
 
-	// 7. Proses Upload Foto Formal
-	formalPhotoFile, formalPhotoHeader, err := r.FormFile("formal_photo")
+	errs, err := h.validateRegistration(ctx, &registration)
 	if err != nil {
-		http.Error(w, `{"error": "Foto formal wajib diunggah"}`, http.StatusBadRequest)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memeriksa pilihan divisi")
 		return
 	}
-	defer formalPhotoFile.Close()
 
-	if err := validateUploadedFile(formalPhotoFile, formalPhotoHeader, map[string]struct{}{
-		".png": {},
-		".jpg": {},
-		".jpeg": {},
-	}, []string{"image/png", "image/jpeg"}); err != nil {
-		http.Error(w, fmt.Sprintf(`{"error": "File foto formal tidak valid: %s"}`, err.Error()), http.StatusBadRequest)
+	files := readRegistrationFiles(r, &errs, false)
+	defer closeFiles(files)
+
+	if len(errs) > 0 {
+		writeValidationErrors(w, r, errs)
 		return
 	}
 
-	formalPhotoPublicID := fmt.Sprintf("himatif-registrations/%s_formal_photo_%d",
-		payload.NIM,
-		time.Now().Unix())
-
-	formalPhotoUploadResult, err := cld.Upload.Upload(ctx, formalPhotoFile, uploader.UploadParams{
-		PublicID:      formalPhotoPublicID,
-		ResourceType:  "image",
-		Overwrite:     api.Bool(true),
-		AccessControl: []api.AccessControlRule{{AccessType: "anonymous"}},
-	})
+	uploaded, label, err := h.uploadRegistrationFiles(ctx, payload.NIM, files, &registration)
 	if err != nil {
-		log.Printf("Cloudinary formal photo upload error: %v", err)
-		http.Error(w, `{"error": "Failed to upload foto formal"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeUploadFailed, "Gagal mengunggah "+label)
 		return
 	}
 
-	formalPhotoUrl := formalPhotoUploadResult.SecureURL
-
-	// 8. Simpan URL dan data form yang sudah benar ke database
-	registration := model.Registration{
-		UserID:                 payload.UserID,
-		Division1:              division1,
-		Division2:              division2,
-		Motivation:             r.FormValue("motivation"),
-		VisionMission:          r.FormValue("vision_mission"),
-		CvUrl:                  cvUrl,
-		CertificateUrl:         certificateUrl,
-		OptionalCertificateUrl: optionalCertificateUrl,
-		FormalPhotoUrl:         formalPhotoUrl,
-		Status:                 "pending",
-		Note:                   "",
-		UpdatedAt:              primitive.NewDateTimeFromTime(time.Now()),
-	}
-
-	collection := repository.MongoClient.Database(cfg.DatabaseName).Collection("registrations")
-	_, err = collection.InsertOne(context.TODO(), registration)
+	registration.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	err = h.registrations.UpdateSubmission(ctx, &registration)
+	if err != nil {
+		// Pendaftaran tetap memakai berkas lama, jadi berkas yang baru diunggah dibuang
+		h.deleteUploadedFiles(ctx, uploaded)
+	}
+	if err == repository.ErrNotFound {
+		// Status diubah admin di antara pengecekan di atas dan penyimpanan
+		writeRegistrationLocked(w, r)
+		return
+	}
 	if err != nil {
-		http.Error(w, `{"error": "Failed to submit registration"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal menyimpan pendaftaran")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Registration submitted successfully"})
+	// Berkas lama yang sudah diganti tidak lagi dirujuk pendaftaran mana pun
+	var replaced []string
+	for _, spec := range registrationFiles {
+		if old := *spec.url(existing); old != "" && old != *spec.url(&registration) {
+			replaced = append(replaced, old)
+		}
+	}
+	h.deleteUploadedFiles(ctx, replaced)
+
+	h.recordEvents(ctx, newRegistrationEvent(payload, registration.ID, model.RegistrationEventUpdated, registration.UpdatedAt))
+
+	response.JSON(w, http.StatusOK, registration)
+}
+
+func writeRegistrationLocked(w http.ResponseWriter, r *http.Request) {
+	response.Error(w, r, http.StatusConflict, response.CodeRegistrationLocked,
+		"Pendaftaran sudah diproses sehingga tidak bisa diubah lagi")
 }
 
-func GetUserProfileHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetUserProfileHandler(w http.ResponseWriter, r *http.Request) {
 	payload, ok := middleware.GetPayloadFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "Failed to get user data from token"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
 		return
 	}
 
-	var user model.User
-	// PERBAIKAN: Gunakan nama database dari config, bukan hardcode
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("users")
-	err := collection.FindOne(context.TODO(), bson.M{"_id": payload.UserID}).Decode(&user)
+	user, err := h.users.FindByID(r.Context(), payload.UserID)
 	if err != nil {
-		http.Error(w, `{"error": "User not found"}`, http.StatusNotFound)
+		response.Error(w, r, http.StatusNotFound, response.CodeUserNotFound, "User tidak ditemukan")
 		return
 	}
 
 	user.Password = ""
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(user)
+	response.JSON(w, http.StatusOK, user)
+}
+
+// userRegistration mengembalikan pendaftaran user pada periode saat ini. Selama belum ada
+// periode sama sekali, dipakai pendaftaran terbaru user (data lama tanpa periode).
+func (h *Handler) userRegistration(ctx context.Context, userID primitive.ObjectID) (*model.Registration, error) {
+	period, err := h.currentPeriod(ctx)
+	switch err {
+	case nil:
+		return h.registrations.FindByUserAndPeriod(ctx, userID, period.ID)
+	case repository.ErrNotFound:
+		return h.registrations.FindByUserID(ctx, userID)
+	default:
+		return nil, err
+	}
 }
 
 // GetUserRegistrationHandler mengambil detail pendaftaran milik user yang sedang login
-func GetUserRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) GetUserRegistrationHandler(w http.ResponseWriter, r *http.Request) {
 	payload, ok := middleware.GetPayloadFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "User data not found in token"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Data user tidak ditemukan pada token")
 		return
 	}
 
-	var registration model.Registration
-	collection := repository.MongoClient.Database(config.GetConfig().DatabaseName).Collection("registrations")
-
-	// Cari pendaftaran berdasarkan user_id dari token
-	err := collection.FindOne(context.TODO(), bson.M{"user_id": payload.UserID}).Decode(&registration)
+	ctx := r.Context()
+	registration, err := h.userRegistration(ctx, payload.UserID)
 	if err != nil {
 		// Jika tidak ditemukan, itu bukan error. Kirim respons kosong.
-		if err == mongo.ErrNoDocuments {
+		if err == repository.ErrNotFound {
 			w.WriteHeader(http.StatusNoContent) // 204 No Content
 			return
 		}
-		http.Error(w, `{"error": "Failed to fetch registration data"}`, http.StatusInternalServerError)
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil data pendaftaran")
+		return
+	}
+
+	events, err := h.events.ListByRegistration(ctx, registration.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil riwayat pendaftaran")
+		return
+	}
+
+	notes, err := h.notes.ListByRegistration(ctx, registration.ID)
+	if err != nil {
+		response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal mengambil catatan pendaftaran")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(registration)
+	response.JSON(w, http.StatusOK, struct {
+		*model.Registration
+		Timeline []registrationTimelineEntry `json:"timeline"`
+		Notes    []applicantNote             `json:"notes"`
+	}{registration, registrationTimeline(events), applicantNotes(notes)})
 }
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func registerBody(nim string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": "Budi", "nim": nim, "email": nim + "@example.com", "phone_number": "081234567890",
+		"birth_place": "Bandung", "birth_date": "2004-02-01", "password": testPassword,
+	}
+}
+
+func TestRegisterHandler(t *testing.T) {
+	env := newTestEnv(t)
+
+	body := registerBody("714220001")
+	body["role"] = "super_admin" // role dari request harus diabaikan
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+
+	user, err := env.repos.Users.FindByNIM(context.Background(), "714220001")
+	if err != nil {
+		t.Fatalf("user tidak tersimpan: %v", err)
+	}
+	if user.Role != "user" {
+		t.Errorf("role %q, ingin user", user.Role)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(testPassword)) != nil {
+		t.Error("password tidak disimpan sebagai hash bcrypt")
+	}
+}
+
+func TestRegisterHandlerDuplicateNIM(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUser(t, "714220001", "user")
+
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, registerBody("714220001"))
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeNIMAlreadyRegistered {
+		t.Fatalf("status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeNIMAlreadyRegistered)
+	}
+	users, _ := env.repos.Users.List(context.Background())
+	if len(users) != 1 {
+		t.Errorf("jumlah user %d, ingin 1", len(users))
+	}
+}
+
+func TestRegisterHandlerValidation(t *testing.T) {
+	env := newTestEnv(t)
+
+	body := registerBody("bukan-nim")
+	body["email"] = "bukan-email"
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "nim") || !hasFieldError(t, rec, "email") {
+		t.Errorf("ingin kesalahan pada nim dan email: %s", rec.Body.String())
+	}
+}
+
+func TestRegisterHandlerRequiresBirthData(t *testing.T) {
+	env := newTestEnv(t)
+
+	body := registerBody("714220001")
+	delete(body, "birth_place")
+	body["birth_date"] = " "
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status %d, ingin 422: %s", rec.Code, rec.Body.String())
+	}
+	if !hasFieldError(t, rec, "birth_place") || !hasFieldError(t, rec, "birth_date") {
+		t.Errorf("tempat dan tanggal lahir harus wajib saat registrasi: %s", rec.Body.String())
+	}
+}
+
+func TestRegisterHandlerKeepsPasswordWhitespace(t *testing.T) {
+	env := newTestEnv(t)
+
+	body := registerBody("714220001")
+	body["password"] = " rahasia1 "
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	user, _ := env.repos.Users.FindByNIM(context.Background(), "714220001")
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(" rahasia1 ")) != nil {
+		t.Error("password harus disimpan apa adanya termasuk spasi")
+	}
+}
+
+func TestRegisterHandlerRejectsBlankPassword(t *testing.T) {
+	env := newTestEnv(t)
+
+	body := registerBody("714220001")
+	body["password"] = "      "
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusUnprocessableEntity || !hasFieldError(t, rec, "password") {
+		t.Fatalf("status %d, ingin 422 dengan kesalahan password: %s", rec.Code, rec.Body.String())
+	}
+	if users, _ := env.repos.Users.List(context.Background()); len(users) != 0 {
+		t.Errorf("user tersimpan padahal password hanya berisi spasi: %+v", users)
+	}
+}
+
+func TestRegisterHandlerTrimsNIMBeforeDuplicateCheck(t *testing.T) {
+	env := newTestEnv(t)
+	env.createUser(t, "714220001", "user")
+
+	body := registerBody("714220001")
+	body["nim"] = " 714220001 "
+	body["email"] = " budi@example.com "
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeNIMAlreadyRegistered {
+		t.Fatalf("status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeNIMAlreadyRegistered)
+	}
+	if users, _ := env.repos.Users.List(context.Background()); len(users) != 1 {
+		t.Errorf("jumlah user %d, ingin 1", len(users))
+	}
+}
+
+func TestRegisterHandlerStoresTrimmedFields(t *testing.T) {
+	env := newTestEnv(t)
+
+	body := registerBody("714220001")
+	body["nim"] = " 714220001 "
+	body["email"] = " budi@example.com "
+	body["name"] = " Budi "
+	rec := env.serve(t, "POST", "/register", "/register", env.h.RegisterHandler, nil, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	user, err := env.repos.Users.FindByNIM(context.Background(), "714220001")
+	if err != nil {
+		t.Fatalf("NIM tidak disimpan tanpa spasi: %v", err)
+	}
+	if user.Email != "budi@example.com" || user.Name != "Budi" {
+		t.Errorf("email %q dan nama %q masih mengandung spasi", user.Email, user.Name)
+	}
+}
+
+// testStorage membungkus MemoryStorage agar unggahan ke-failAt gagal dan beforeUpload bisa
+// menyisipkan kejadian di tengah proses unggah
+type testStorage struct {
+	*storage.MemoryStorage
+	failAt       int
+	uploads      int
+	uploaded     []string
+	beforeUpload func()
+}
+
+func (s *testStorage) Upload(ctx context.Context, file io.Reader, publicID, resourceType string) (string, error) {
+	s.uploads++
+	if s.uploads == s.failAt {
+		return "", errors.New("storage tidak tersedia")
+	}
+	if s.beforeUpload != nil {
+		s.beforeUpload()
+	}
+	url, err := s.MemoryStorage.Upload(ctx, file, publicID, resourceType)
+	if err == nil {
+		s.uploaded = append(s.uploaded, url)
+	}
+	return url, err
+}
+
+func (e *testEnv) useStorage(s storage.FileStorage) {
+	e.h = New(Dependencies{Repositories: e.repos, Storage: s, Notifier: e.notifier})
+}
+
+func (e *testEnv) stored(url string) bool {
+	_, ok := e.storage.File(strings.TrimPrefix(url, "memory://"))
+	return ok
+}
+
+var pngContent = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// registrationRequest menyusun form multipart pendaftaran dengan berkas PNG untuk setiap field di files
+func registrationRequest(t *testing.T, method string, fields map[string]string, files ...string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	for _, field := range files {
+		part, err := writer.CreateFormFile(field, field+".png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(pngContent)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(method, "/api/user/registration", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+var registrationAnswers = map[string]string{"division1": "Web", "division2": "Data", "motivation": "Belajar", "vision_mission": "Berkontribusi"}
+
+func (e *testEnv) submitRegistration(t *testing.T, user *model.User) *model.Registration {
+	t.Helper()
+	req := registrationRequest(t, "POST", registrationAnswers, "cv", "certificate", "formal_photo")
+	rec := e.serveRequest(t, "/api/user/registration", req, e.h.SubmitRegistrationHandler, user)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit: status %d, ingin 201: %s", rec.Code, rec.Body.String())
+	}
+	registrations, err := e.repos.Registrations.ListByUser(context.Background(), user.ID)
+	if err != nil || len(registrations) != 1 {
+		t.Fatalf("pendaftaran tidak tersimpan: %v %v", registrations, err)
+	}
+	return &registrations[0]
+}
+
+func TestUpdateRegistrationHandlerDeletesReplacedFiles(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+	before := env.submitRegistration(t, user)
+
+	req := registrationRequest(t, "PUT", nil, "cv")
+	rec := env.serveRequest(t, "/api/user/registration", req, env.h.UpdateRegistrationHandler, user)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, ingin 200: %s", rec.Code, rec.Body.String())
+	}
+
+	after, _ := env.repos.Registrations.FindByID(context.Background(), before.ID)
+	if after.CvUrl == before.CvUrl || !env.stored(after.CvUrl) {
+		t.Errorf("CV baru tidak tersimpan: %s", after.CvUrl)
+	}
+	if env.stored(before.CvUrl) {
+		t.Errorf("CV lama %s masih tersimpan setelah diganti", before.CvUrl)
+	}
+	if after.CertificateUrl != before.CertificateUrl || !env.stored(after.CertificateUrl) {
+		t.Errorf("sertifikat yang tidak diganti ikut berubah atau terhapus")
+	}
+}
+
+func TestUpdateRegistrationHandlerDeletesUploadsWhenSaveFails(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+	before := env.submitRegistration(t, user)
+
+	// Admin memproses pendaftaran saat berkas sedang diunggah, sehingga penyimpanan ditolak
+	files := &testStorage{MemoryStorage: env.storage, beforeUpload: func() {
+		status := model.RegistrationStatusDocumentReview
+		env.repos.Registrations.Update(context.Background(), before.ID, repository.RegistrationUpdate{Status: &status})
+	}}
+	env.useStorage(files)
+
+	req := registrationRequest(t, "PUT", nil, "cv")
+	rec := env.serveRequest(t, "/api/user/registration", req, env.h.UpdateRegistrationHandler, user)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != response.CodeRegistrationLocked {
+		t.Fatalf("status %d %s, ingin 409 %s", rec.Code, errorCode(t, rec), response.CodeRegistrationLocked)
+	}
+	if len(files.uploaded) != 1 || env.stored(files.uploaded[0]) {
+		t.Errorf("berkas yang baru diunggah masih tersimpan: %v", files.uploaded)
+	}
+	if !env.stored(before.CvUrl) {
+		t.Error("CV lama ikut terhapus padahal pendaftaran tidak berubah")
+	}
+}
+
+func TestSubmitRegistrationHandlerDeletesUploadsWhenUploadFails(t *testing.T) {
+	env := newTestEnv(t)
+	env.openPeriod(t)
+	user := env.createUser(t, "714220001", "user")
+	files := &testStorage{MemoryStorage: env.storage, failAt: 2}
+	env.useStorage(files)
+
+	req := registrationRequest(t, "POST", registrationAnswers, "cv", "certificate", "formal_photo")
+	rec := env.serveRequest(t, "/api/user/registration", req, env.h.SubmitRegistrationHandler, user)
+	if rec.Code != http.StatusInternalServerError || errorCode(t, rec) != response.CodeUploadFailed {
+		t.Fatalf("status %d %s, ingin 500 %s", rec.Code, errorCode(t, rec), response.CodeUploadFailed)
+	}
+	if len(files.uploaded) != 1 || env.stored(files.uploaded[0]) {
+		t.Errorf("berkas yang sudah terunggah tidak dihapus: %v", files.uploaded)
+	}
+	if registrations, _ := env.repos.Registrations.ListByUser(context.Background(), user.ID); len(registrations) != 0 {
+		t.Errorf("pendaftaran tersimpan padahal unggahan gagal: %+v", registrations)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/validation"
+)
+
+const minPasswordLength = 6
+
+// writeValidationErrors mengirim respons 422 berisi seluruh kesalahan validasi sekaligus di "details"
+func writeValidationErrors(w http.ResponseWriter, r *http.Request, errs validation.Errors) {
+	response.ErrorWithDetails(w, r, http.StatusUnprocessableEntity, response.CodeValidationFailed, "Data yang dikirim tidak valid", errs)
+}
+
+// validatePassword memvalidasi password baru dengan aturan yang sama di semua handler.
+// Password tidak di-trim karena spasi ikut di-hash saat disimpan, tetapi password yang hanya
+// berisi spasi tetap dianggap kosong.
+func validatePassword(errs *validation.Errors, field, label, password string) {
+	if strings.TrimSpace(password) == "" {
+		validation.Var(errs, field, label, "", "required")
+		return
+	}
+	validation.Var(errs, field, label, password, "notrim,required,min="+strconv.Itoa(minPasswordLength))
+}
+
+// trimUserFields membuang spasi di awal dan akhir data profil sebelum divalidasi, dicek
+// duplikasinya, dan disimpan, agar " 714220001 " tidak dianggap NIM yang berbeda
+func trimUserFields(user *model.User) {
+	for _, value := range []*string{&user.Name, &user.NIM, &user.Email, &user.PhoneNumber, &user.BirthPlace, &user.BirthDate} {
+		*value = strings.TrimSpace(*value)
+	}
+}
+
+// validateNewUser memvalidasi user yang baru dibuat. Tempat dan tanggal lahir hanya wajib saat
+// pembuatan akun karena user lama belum memilikinya dan tetap harus bisa diubah oleh admin.
+func validateNewUser(user *model.User) validation.Errors {
+	errs := validation.Struct(user)
+	if !errs.Has("birth_place") {
+		validation.Var(&errs, "birth_place", "Tempat lahir", user.BirthPlace, "required")
+	}
+	if !errs.Has("birth_date") {
+		validation.Var(&errs, "birth_date", "Tanggal lahir", user.BirthDate, "required")
+	}
+	return errs
+}
+
+// validateRegistration memvalidasi isian form pendaftaran; kedua pilihan harus merujuk divisi yang aktif.
+// Nama divisi dinormalisasi sesuai katalog.
+func (h *Handler) validateRegistration(ctx context.Context, registration *model.Registration) (validation.Errors, error) {
+	errs := validation.Struct(registration)
+
+	choices := []struct {
+		field string
+		value *string
+	}{
+		{"division1", &registration.Division1},
+		{"division2", &registration.Division2},
+	}
+	for _, choice := range choices {
+		if errs.Has(choice.field) {
+			continue
+		}
+		division, err := h.activeDivision(ctx, *choice.value)
+		if err == repository.ErrNotFound {
+			errs.Add(choice.field, "UNKNOWN_DIVISION", "Divisi "+*choice.value+" tidak tersedia")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		*choice.value = division.Name
+	}
+	return errs, nil
+}
@@ -6,43 +6,83 @@ import (
 	"strings"
 
 	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type PasetoPayloadKey string
 
 const payloadKey PasetoPayloadKey = "pasetoPayload"
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, `{"error": "Authorization header required"}`, http.StatusUnauthorized)
-			return
-		}
+// TokenRevocationChecker mengecek apakah sebuah access token (berdasarkan jti) sudah dicabut
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, `{"error": "Invalid authorization header format"}`, http.StatusUnauthorized)
-			return
-		}
+// UserFinder mengambil data user terbaru untuk mencocokkan token version dan status akun
+type UserFinder interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error)
+}
 
-		tokenString := parts[1]
-		payload, err := auth.VerifyToken(tokenString)
-		if err != nil {
-			http.Error(w, `{"error": "Invalid token"}`, http.StatusUnauthorized)
-			return
-		}
+// AuthMiddleware memverifikasi token Paseto dan menolak token yang sudah dicabut (logout),
+// milik akun yang dinonaktifkan, atau yang terbit sebelum role/password user berubah
+func AuthMiddleware(tokens TokenRevocationChecker, users UserFinder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				response.Error(w, r, http.StatusUnauthorized, response.CodeAuthHeaderMissing, "Header Authorization wajib diisi")
+				return
+			}
 
-		ctx := context.WithValue(r.Context(), payloadKey, payload)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				response.Error(w, r, http.StatusUnauthorized, response.CodeAuthHeaderInvalid, "Format header Authorization tidak valid")
+				return
+			}
+
+			tokenString := parts[1]
+			payload, err := auth.VerifyToken(tokenString)
+			if err != nil {
+				response.Error(w, r, http.StatusUnauthorized, response.CodeTokenInvalid, "Token tidak valid")
+				return
+			}
+
+			revoked, err := tokens.IsAccessTokenRevoked(r.Context(), payload.TokenID)
+			if err != nil {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memvalidasi token")
+				return
+			}
+			if revoked {
+				response.Error(w, r, http.StatusUnauthorized, response.CodeTokenRevoked, "Token sudah dicabut")
+				return
+			}
+
+			// Gangguan database bukan alasan untuk mengeluarkan user; hanya akun yang sudah dihapus,
+			// dinonaktifkan, atau token version yang berbeda yang membuat sesi tidak berlaku
+			user, err := users.FindByID(r.Context(), payload.UserID)
+			if err != nil && err != repository.ErrNotFound {
+				response.Error(w, r, http.StatusInternalServerError, response.CodeInternalError, "Gagal memvalidasi sesi")
+				return
+			}
+			if err == repository.ErrNotFound || user.Disabled || user.TokenVersion != payload.TokenVersion {
+				response.Error(w, r, http.StatusUnauthorized, response.CodeSessionInvalidated, "Sesi sudah tidak berlaku, silakan login kembali")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), payloadKey, payload)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func AdminOnlyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		payload, ok := r.Context().Value(payloadKey).(*auth.PasetoPayload)
 		if !ok || (payload.Role != "admin" && payload.Role != "super_admin") {
-			http.Error(w, `{"error": "Admin access required"}`, http.StatusForbidden)
+			response.Error(w, r, http.StatusForbidden, response.CodeAdminRequired, "Akses khusus admin")
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -53,7 +93,7 @@ func SuperAdminOnlyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		payload, ok := r.Context().Value(payloadKey).(*auth.PasetoPayload)
 		if !ok || payload.Role != "super_admin" {
-			http.Error(w, `{"error": "Super admin access required"}`, http.StatusForbidden)
+			response.Error(w, r, http.StatusForbidden, response.CodeSuperAdminRequired, "Akses khusus super admin")
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("PASETO_SECRET_KEY", "R4nd0mS3cr3tK3yF0rP4s3t0Appl1c4t")
+	config.GetConfig()
+	config.LoadDatabaseCredentials(map[string]string{})
+	os.Exit(m.Run())
+}
+
+type notRevoked struct{}
+
+func (notRevoked) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return false, nil
+}
+
+// userFinderFunc mengubah fungsi biasa menjadi UserFinder
+type userFinderFunc func(ctx context.Context, id primitive.ObjectID) (*model.User, error)
+
+func (f userFinderFunc) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	return f(ctx, id)
+}
+
+func TestAuthMiddlewareUserLookup(t *testing.T) {
+	userID := primitive.NewObjectID()
+	token, _, err := auth.GenerateToken(userID, "714220001", "user", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		user     *model.User
+		err      error
+		wantCode int
+		want     response.Code
+	}{
+		{name: "sesi berlaku", user: &model.User{ID: userID, TokenVersion: 2}, wantCode: http.StatusNoContent},
+		{name: "gangguan database", err: errors.New("server selection timeout"), wantCode: http.StatusInternalServerError, want: response.CodeInternalError},
+		{name: "akun dihapus", err: repository.ErrNotFound, wantCode: http.StatusUnauthorized, want: response.CodeSessionInvalidated},
+		{name: "akun dinonaktifkan", user: &model.User{ID: userID, TokenVersion: 2, Disabled: true}, wantCode: http.StatusUnauthorized, want: response.CodeSessionInvalidated},
+		{name: "token version berubah", user: &model.User{ID: userID, TokenVersion: 3}, wantCode: http.StatusUnauthorized, want: response.CodeSessionInvalidated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			users := userFinderFunc(func(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+				return tc.user, tc.err
+			})
+			protected := AuthMiddleware(notRevoked{}, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest("GET", "/api/user/profile", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			protected.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantCode {
+				t.Fatalf("status %d, ingin %d: %s", rec.Code, tc.wantCode, rec.Body.String())
+			}
+			if tc.want == "" {
+				return
+			}
+			var body struct {
+				Error struct {
+					Code response.Code `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("respons bukan JSON: %v", err)
+			}
+			if body.Error.Code != tc.want {
+				t.Errorf("kode %s, ingin %s", body.Error.Code, tc.want)
+			}
+		})
+	}
+}
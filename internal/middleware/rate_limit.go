@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+)
+
+// RateLimitStore menyimpan token bucket, bisa in-process atau MongoDB (lihat repository.RateLimitRepository)
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, capacity int, refillPerSecond float64, now time.Time) (bool, time.Duration, error)
+}
+
+// RateLimitKey menentukan request dikelompokkan berdasarkan apa
+type RateLimitKey int
+
+const (
+	// RateLimitByIP mengelompokkan request berdasarkan IP asal
+	RateLimitByIP RateLimitKey = iota
+	// RateLimitByUser mengelompokkan request berdasarkan user ID dari token,
+	// fallback ke IP jika request belum terotentikasi
+	RateLimitByUser
+)
+
+// RateLimitRule adalah batas request untuk satu grup route
+type RateLimitRule struct {
+	// Name membedakan bucket antar grup route, misal "auth" atau "upload"
+	Name string
+	// Requests adalah jumlah request (kapasitas bucket) per durasi Per. Nol berarti tanpa batas.
+	Requests int
+	Per      time.Duration
+	By       RateLimitKey
+}
+
+// RateLimit membatasi request memakai token bucket. Request yang melebihi batas
+// mendapat 429 beserta header Retry-After.
+func RateLimit(store RateLimitStore, rule RateLimitRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if rule.Requests <= 0 || rule.Per <= 0 {
+			return next
+		}
+		refillPerSecond := float64(rule.Requests) / rule.Per.Seconds()
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// GetRequestIP hanya memakai X-Forwarded-For lewat proxy tepercaya, sehingga klien
+			// tidak bisa mendapat bucket baru dengan mengganti header tersebut
+			key := rule.Name + ":ip:" + GetRequestIP(r)
+			if rule.By == RateLimitByUser {
+				if payload, ok := GetPayloadFromContext(r.Context()); ok {
+					key = rule.Name + ":user:" + payload.UserID.Hex()
+				}
+			}
+
+			allowed, retryAfter, err := store.Take(r.Context(), key, rule.Requests, refillPerSecond, time.Now())
+			if err != nil {
+				// Gangguan pada store tidak boleh membuat API ikut mati
+				log.Printf("rate limit store error for %s: %v", key, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				response.ErrorWithDetails(w, r, http.StatusTooManyRequests, response.CodeRateLimited,
+					fmt.Sprintf("Terlalu banyak permintaan. Coba lagi dalam %d detik.", seconds),
+					map[string]int{"retry_after": seconds})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
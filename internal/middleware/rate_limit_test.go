@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+)
+
+func limitedByIP(requests int) http.Handler {
+	return RateLimit(repository.NewMemoryRateLimitRepository(), RateLimitRule{
+		Name: "auth", Requests: requests, Per: time.Minute, By: RateLimitByIP,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// withTrustedProxyHops mengganti TRUSTED_PROXY_HOPS selama satu test
+func withTrustedProxyHops(t *testing.T, hops int) {
+	t.Helper()
+	cfg := config.GetConfig()
+	previous := cfg.TrustedProxyHops
+	cfg.TrustedProxyHops = hops
+	t.Cleanup(func() { cfg.TrustedProxyHops = previous })
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	withTrustedProxyHops(t, 0)
+	limited := limitedByIP(2)
+
+	// Tanpa proxy tepercaya, setiap request dari koneksi yang sama memakai bucket yang sama
+	// walaupun X-Forwarded-For berganti
+	for i, forwardedFor := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = "198.51.100.9:5555"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		limited.ServeHTTP(rec, req)
+
+		want := http.StatusNoContent
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if rec.Code != want {
+			t.Errorf("request %d dengan X-Forwarded-For %s: status %d, ingin %d", i+1, forwardedFor, rec.Code, want)
+		}
+	}
+}
+
+func TestRateLimitSeparatesClientsBehindTrustedProxy(t *testing.T) {
+	withTrustedProxyHops(t, 1)
+	limited := limitedByIP(1)
+
+	// Semua request datang dari IP front-end yang sama, tetapi klien aslinya berbeda
+	for _, forwardedFor := range []string{"203.0.113.7", "203.0.113.8"} {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = "169.254.1.1:5555"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		limited.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("klien %s: status %d, ingin %d karena bucket-nya terpisah", forwardedFor, rec.Code, http.StatusNoContent)
+		}
+	}
+
+	// Klien pertama sudah menghabiskan bucket-nya sendiri
+	req := httptest.NewRequest("POST", "/login", nil)
+	req.RemoteAddr = "169.254.1.1:5555"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	rec := httptest.NewRecorder()
+	limited.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("request kedua klien 203.0.113.7: status %d, ingin %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
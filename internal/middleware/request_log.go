@@ -9,6 +9,8 @@ import (
 
 	"github.com/ulbithebest/BE-pendaftaran/internal/applog"
 	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
 )
 
 type statusRecorder struct {
@@ -68,12 +70,13 @@ func RequestLogMiddleware(next http.Handler) http.Handler {
 			FullPath:      fullPath,
 			StatusCode:    recorder.statusCode,
 			DurationMs:    durationMs,
-			RemoteIP:      getRequestIP(r),
+			RemoteIP:      GetRequestIP(r),
 			UserAgent:     r.UserAgent(),
 			Host:          r.Host,
 			Referer:       r.Referer(),
 			ResponseBytes: recorder.bytesWritten,
 			ContentLength: r.ContentLength,
+			RequestID:     response.RequestID(r.Context()),
 		}
 
 		if payload != nil {
@@ -104,17 +107,50 @@ func extractPayloadFromAuthorization(authHeader string) *auth.PasetoPayload {
 	return payload
 }
 
-func getRequestIP(r *http.Request) string {
-	forwardedFor := strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-For"), ",")[0])
-	if forwardedFor != "" {
-		return forwardedFor
+// GetRequestIP mengambil IP asal request untuk log, rate limit, dan penguncian login.
+// Header X-Forwarded-For/X-Real-IP hanya dipercaya sebanyak TRUSTED_PROXY_HOPS proxy
+// di depan server; tanpa proxy tepercaya IP diambil dari koneksi (RemoteAddr).
+func GetRequestIP(r *http.Request) string {
+	return clientIP(r, config.GetConfig().TrustedProxyHops)
+}
+
+// clientIP menelusuri rantai X-Forwarded-For dari kanan. Setiap proxy tepercaya menambahkan
+// IP peer-nya di ujung kanan, sehingga entri ke-hops dari kanan (RemoteAddr dihitung sebagai
+// entri terakhir) adalah IP klien. Entri di sebelah kirinya dikirim klien dan bisa dipalsukan.
+func clientIP(r *http.Request, hops int) string {
+	remote := remoteAddrIP(r)
+	if hops <= 0 {
+		return remote
 	}
 
-	xRealIP := strings.TrimSpace(r.Header.Get("X-Real-IP"))
-	if xRealIP != "" {
-		return xRealIP
+	var chain []string
+	for _, entry := range strings.Split(r.Header.Get("X-Forwarded-For"), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			chain = append(chain, entry)
+		}
+	}
+	if len(chain) == 0 {
+		// Proxy seperti nginx bisa hanya mengirim X-Real-IP
+		if xRealIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); xRealIP != "" {
+			chain = append(chain, xRealIP)
+		}
 	}
+	chain = append(chain, remote)
+
+	// Rantai lebih pendek dari jumlah proxy berarti request tidak melewati semua proxy;
+	// entri paling kiri adalah yang paling jauh yang masih ditambahkan proxy
+	index := len(chain) - 1 - hops
+	if index < 0 {
+		index = 0
+	}
+	if ip := net.ParseIP(chain[index]); ip != nil {
+		return ip.String()
+	}
+	return remote
+}
 
+// remoteAddrIP mengambil host dari RemoteAddr koneksi
+func remoteAddrIP(r *http.Request) string {
 	host, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
 	if err == nil && host != "" {
 		return host
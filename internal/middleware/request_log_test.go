@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		name         string
+		hops         int
+		forwardedFor string
+		realIP       string
+		remoteAddr   string
+		wantClientIP string
+	}{
+		{"tanpa proxy mengabaikan header", 0, "1.1.1.1", "2.2.2.2", "10.0.0.5:4321", "10.0.0.5"},
+		{"satu proxy", 1, "203.0.113.7", "", "10.0.0.1:80", "203.0.113.7"},
+		{"satu proxy dengan XFF palsu", 1, "1.1.1.1, 203.0.113.7", "", "10.0.0.1:80", "203.0.113.7"},
+		{"dua proxy", 2, "1.1.1.1, 203.0.113.7, 10.0.0.2", "", "10.0.0.1:80", "203.0.113.7"},
+		{"X-Real-IP dari proxy", 1, "", "203.0.113.7", "10.0.0.1:80", "203.0.113.7"},
+		{"request melewati proxy", 1, "", "", "198.51.100.9:5555", "198.51.100.9"},
+		{"rantai lebih pendek dari jumlah proxy", 3, "203.0.113.7", "", "10.0.0.1:80", "203.0.113.7"},
+		{"entri bukan IP", 1, "bukan-ip", "", "10.0.0.1:80", "10.0.0.1"},
+		{"IPv6", 1, "2001:db8::1", "", "[::1]:80", "2001:db8::1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+			if tc.realIP != "" {
+				req.Header.Set("X-Real-IP", tc.realIP)
+			}
+			if got := clientIP(req, tc.hops); got != tc.wantClientIP {
+				t.Errorf("clientIP = %s, ingin %s", got, tc.wantClientIP)
+			}
+		})
+	}
+}
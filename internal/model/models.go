@@ -4,66 +4,212 @@ import "go.mongodb.org/mongo-driver/bson/primitive"
 
 // User sesuai dengan koleksi 'users'
 type User struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name        string             `bson:"name" json:"name"`
-	NIM         string             `bson:"nim" json:"nim"`
-	BirthPlace  string             `bson:"birth_place" json:"birth_place"` // <-- TAMBAHKAN INI
-	BirthDate   string             `bson:"birth_date" json:"birth_date"`   // <-- TAMBAHKAN INI
-	Email       string             `bson:"email" json:"email"`
-	PhoneNumber string             `bson:"phone_number" json:"phone_number"`
-	Password    string             `bson:"password" json:"password"`
-	Role        string             `bson:"role" json:"role"`
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Name            string              `bson:"name" json:"name" validate:"required,max=100" label:"Nama"`
+	NIM             string              `bson:"nim" json:"nim" validate:"required,nim" label:"NIM"`
+	BirthPlace      string              `bson:"birth_place" json:"birth_place" validate:"max=100" label:"Tempat lahir"` // <-- TAMBAHKAN INI
+	BirthDate       string              `bson:"birth_date" json:"birth_date" validate:"date" label:"Tanggal lahir"`     // <-- TAMBAHKAN INI
+	Email           string              `bson:"email" json:"email" validate:"required,email" label:"Email"`
+	PhoneNumber     string              `bson:"phone_number" json:"phone_number" validate:"required,phone" label:"Nomor telepon"`
+	Password        string              `bson:"password" json:"password"`
+	Role            string              `bson:"role" json:"role" validate:"required,oneof=user admin super_admin" label:"Role"`
+	Disabled        bool                `bson:"disabled" json:"disabled"`
+	EmailVerifiedAt *primitive.DateTime `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty"`
+	TokenVersion    int                 `bson:"token_version" json:"-"` // Dinaikkan saat role/password/status akun berubah agar token lama tidak berlaku
 }
 
 // Registration sesuai dengan koleksi 'registrations'
 type Registration struct {
-	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	UserID            primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Division1         string             `bson:"division1" json:"division1"` // <-- TAMBAHKAN INI
-	Division2         string             `bson:"division2" json:"division2"`
-	Motivation        string             `bson:"motivation" json:"motivation"`
-	VisionMission     string             `bson:"vision_mission" json:"vision_mission"`
-	InterviewSchedule string             `bson:"interview_schedule,omitempty" json:"interview_schedule,omitempty"`
-	InterviewLocation string             `bson:"interview_location,omitempty" json:"interview_location,omitempty"`
-	CvUrl             string             `bson:"cv_url" json:"cv_url"` // <-- UBAH INI
-	CertificateUrl    string             `bson:"certificate_url,omitempty" json:"certificate_url,omitempty"`
-	OptionalCertificateUrl string        `bson:"optional_certificate_url,omitempty" json:"optional_certificate_url,omitempty"`
-	FormalPhotoUrl    string             `bson:"formal_photo_url,omitempty" json:"formal_photo_url,omitempty"`
-	Status            string             `bson:"status" json:"status"`
-	Note              string             `bson:"note" json:"note"`
-	UpdatedAt         primitive.DateTime `bson:"updated_at" json:"updated_at"`
+	ID                     primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID                 primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	PeriodID               primitive.ObjectID  `bson:"period_id,omitempty" json:"period_id,omitempty"`
+	Division1              string              `bson:"division1" json:"division1" validate:"required,max=100" label:"Divisi 1"` // <-- TAMBAHKAN INI
+	Division2              string              `bson:"division2" json:"division2" validate:"required,max=100,nefield=Division1" label:"Divisi 2"`
+	Motivation             string              `bson:"motivation" json:"motivation" validate:"required,max=5000" label:"Motivasi"`
+	VisionMission          string              `bson:"vision_mission" json:"vision_mission" validate:"required,max=5000" label:"Visi dan misi"`
+	InterviewSchedule      string              `bson:"interview_schedule,omitempty" json:"interview_schedule,omitempty"`
+	InterviewLocation      string              `bson:"interview_location,omitempty" json:"interview_location,omitempty"`
+	InterviewSlotID        *primitive.ObjectID `bson:"interview_slot_id,omitempty" json:"interview_slot_id,omitempty"`
+	CvUrl                  string              `bson:"cv_url" json:"cv_url"` // <-- UBAH INI
+	CertificateUrl         string              `bson:"certificate_url,omitempty" json:"certificate_url,omitempty"`
+	OptionalCertificateUrl string              `bson:"optional_certificate_url,omitempty" json:"optional_certificate_url,omitempty"`
+	FormalPhotoUrl         string              `bson:"formal_photo_url,omitempty" json:"formal_photo_url,omitempty"`
+	Status                 string              `bson:"status" json:"status"`
+	PlacedDivision         string              `bson:"placed_division,omitempty" json:"placed_division,omitempty"` // Divisi akhir hasil penempatan, hanya untuk yang diterima
+	Note                   string              `bson:"note" json:"note"`                                           // Catatan lama; catatan baru disimpan di koleksi 'registration_notes'
+	UpdatedAt              primitive.DateTime  `bson:"updated_at" json:"updated_at"`
 }
 
 // Struct untuk menggabungkan data Registrasi dan User
 type RegistrationDetail struct {
-	ID             primitive.ObjectID `bson:"_id" json:"id"`
-	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Name           string             `bson:"name" json:"name"`
-	NIM            string             `bson:"nim" json:"nim"`
-	Division1      string             `bson:"division1" json:"division1"` // <-- TAMBAHKAN INI
-	Division2      string             `bson:"division2" json:"division2"`
-	Motivation     string             `bson:"motivation" json:"motivation"`
-	VisionMission  string             `bson:"vision_mission" json:"vision_mission"`
-	InterviewSchedule string          `bson:"interview_schedule,omitempty" json:"interview_schedule,omitempty"`
-	InterviewLocation string          `bson:"interview_location,omitempty" json:"interview_location,omitempty"`
-	CvUrl          string             `bson:"cv_url" json:"cv_url"` // <-- UBAH INI
-	CertificateUrl string             `bson:"certificate_url,omitempty" json:"certificate_url,omitempty"`
-	OptionalCertificateUrl string      `bson:"optional_certificate_url,omitempty" json:"optional_certificate_url,omitempty"`
-	FormalPhotoUrl string             `bson:"formal_photo_url,omitempty" json:"formal_photo_url,omitempty"`
-	Status         string             `bson:"status" json:"status"`
-	Note           string             `bson:"note,omitempty" json:"note,omitempty"`
-	UpdatedAt      primitive.DateTime `bson:"updated_at" json:"updated_at"`
+	ID                     primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID                 primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	PeriodID               primitive.ObjectID  `bson:"period_id,omitempty" json:"period_id,omitempty"`
+	Name                   string              `bson:"name" json:"name"`
+	NIM                    string              `bson:"nim" json:"nim"`
+	Email                  string              `bson:"email" json:"email"`
+	PhoneNumber            string              `bson:"phone_number" json:"phone_number"`
+	Division1              string              `bson:"division1" json:"division1"` // <-- TAMBAHKAN INI
+	Division2              string              `bson:"division2" json:"division2"`
+	Motivation             string              `bson:"motivation" json:"motivation"`
+	VisionMission          string              `bson:"vision_mission" json:"vision_mission"`
+	InterviewSchedule      string              `bson:"interview_schedule,omitempty" json:"interview_schedule,omitempty"`
+	InterviewLocation      string              `bson:"interview_location,omitempty" json:"interview_location,omitempty"`
+	InterviewSlotID        *primitive.ObjectID `bson:"interview_slot_id,omitempty" json:"interview_slot_id,omitempty"`
+	CvUrl                  string              `bson:"cv_url" json:"cv_url"` // <-- UBAH INI
+	CertificateUrl         string              `bson:"certificate_url,omitempty" json:"certificate_url,omitempty"`
+	OptionalCertificateUrl string              `bson:"optional_certificate_url,omitempty" json:"optional_certificate_url,omitempty"`
+	FormalPhotoUrl         string              `bson:"formal_photo_url,omitempty" json:"formal_photo_url,omitempty"`
+	Status                 string              `bson:"status" json:"status"`
+	PlacedDivision         string              `bson:"placed_division,omitempty" json:"placed_division,omitempty"`
+	Note                   string              `bson:"note,omitempty" json:"note,omitempty"`
+	UpdatedAt              primitive.DateTime  `bson:"updated_at" json:"updated_at"`
+	ScoreAverage           *float64            `bson:"score_average,omitempty" json:"score_average"` // Rata-rata nilai wawancara (0-100), nil jika belum dinilai
+	ScoreCount             int                 `bson:"score_count" json:"score_count"`
 }
 
 // Information sesuai dengan koleksi 'informations'
 type Information struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Title     string             `bson:"title" json:"title"`
-	Content   string             `bson:"content" json:"content"`
+	Title     string             `bson:"title" json:"title" validate:"required,max=200" label:"Judul"`
+	Content   string             `bson:"content" json:"content" validate:"required" label:"Isi"`
 	CreatedAt primitive.DateTime `bson:"created_at" json:"created_at"`
 	UpdatedAt primitive.DateTime `bson:"updated_at" json:"updated_at"`
 }
 
+// Division sesuai dengan koleksi 'divisions'. Registration.Division1/Division2 menyimpan nama divisi.
+type Division struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Name         string               `bson:"name" json:"name" validate:"required,max=100" label:"Nama divisi"`
+	Description  string               `bson:"description" json:"description" validate:"max=2000" label:"Deskripsi"`
+	Quota        int                  `bson:"quota" json:"quota" validate:"min=0" label:"Kuota"` // 0 = tidak dibatasi
+	Active       bool                 `bson:"active" json:"active"`
+	Coordinators []primitive.ObjectID `bson:"coordinators" json:"coordinators,omitempty"` // ID admin koordinator divisi
+	Rubric       *InterviewRubric     `bson:"rubric,omitempty" json:"rubric,omitempty"`   // Kriteria penilaian wawancara, nil jika belum diatur
+	CreatedAt    primitive.DateTime   `bson:"created_at" json:"created_at"`
+	UpdatedAt    primitive.DateTime   `bson:"updated_at" json:"updated_at"`
+}
+
+// Status periode rekrutmen
+const (
+	PeriodStatusDraft  = "draft"  // Disiapkan admin, belum menerima pendaftaran
+	PeriodStatusActive = "active" // Periode berjalan; pendaftaran diterima selama dalam jendela waktu
+	PeriodStatusClosed = "closed" // Periode selesai, hanya untuk arsip
+)
+
+// RecruitmentPeriod sesuai dengan koleksi 'recruitment_periods'. Hanya satu periode yang boleh berstatus active.
+type RecruitmentPeriod struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name         string             `bson:"name" json:"name" validate:"required,max=100" label:"Nama periode"`
+	AcademicYear string             `bson:"academic_year" json:"academic_year" validate:"required,max=20" label:"Tahun akademik"`
+	OpensAt      primitive.DateTime `bson:"opens_at" json:"opens_at"`
+	ClosesAt     primitive.DateTime `bson:"closes_at" json:"closes_at"`
+	Status       string             `bson:"status" json:"status" validate:"required,oneof=draft active closed" label:"Status"`
+	CreatedAt    primitive.DateTime `bson:"created_at" json:"created_at"`
+	UpdatedAt    primitive.DateTime `bson:"updated_at" json:"updated_at"`
+}
+
+// Jenis event pada riwayat pendaftaran
+const (
+	RegistrationEventSubmitted        = "submitted"         // Pendaftar mengirim formulir
+	RegistrationEventUpdated          = "updated"           // Pendaftar mengubah jawaban atau berkas
+	RegistrationEventStatusChanged    = "status_changed"    // Admin mengubah status
+	RegistrationEventInterviewChanged = "interview_changed" // Admin mengubah jadwal/lokasi wawancara tanpa mengubah status
+	RegistrationEventImported         = "imported"          // Super admin mengimpor pendaftaran dari berkas CSV
+	RegistrationEventPlaced           = "placed"            // Admin menetapkan divisi penempatan akhir
+	RegistrationEventDeleted          = "deleted"           // Admin menghapus pendaftaran; riwayatnya tetap disimpan
+)
+
+// RegistrationEvent sesuai dengan koleksi 'registration_events'. Koleksi ini hanya ditambah, tidak pernah diubah.
+type RegistrationEvent struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	RegistrationID    primitive.ObjectID `bson:"registration_id" json:"registration_id"`
+	Type              string             `bson:"type" json:"type"`
+	ActorID           primitive.ObjectID `bson:"actor_id" json:"actor_id"`
+	ActorRole         string             `bson:"actor_role" json:"actor_role"`
+	OldStatus         string             `bson:"old_status,omitempty" json:"old_status,omitempty"`
+	NewStatus         string             `bson:"new_status,omitempty" json:"new_status,omitempty"`
+	Note              string             `bson:"note,omitempty" json:"note,omitempty"`
+	InterviewSchedule string             `bson:"interview_schedule,omitempty" json:"interview_schedule,omitempty"`
+	InterviewLocation string             `bson:"interview_location,omitempty" json:"interview_location,omitempty"`
+	Division          string             `bson:"division,omitempty" json:"division,omitempty"` // Divisi penempatan pada event placed
+	CreatedAt         primitive.DateTime `bson:"created_at" json:"created_at"`
+}
+
+// Visibilitas catatan pendaftaran
+const (
+	NoteVisibilityInternal  = "internal"  // Hanya untuk admin
+	NoteVisibilityApplicant = "applicant" // Ditampilkan juga ke pendaftar
+)
+
+// RegistrationNote sesuai dengan koleksi 'registration_notes'. Balasan menyimpan ParentID catatan
+// utamanya dan selalu mengikuti visibilitas catatan utama tersebut.
+type RegistrationNote struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	RegistrationID primitive.ObjectID  `bson:"registration_id" json:"registration_id"`
+	ParentID       *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"` // nil untuk catatan utama
+	AuthorID       primitive.ObjectID  `bson:"author_id" json:"author_id"`
+	Body           string              `bson:"body" json:"body" validate:"required,max=5000" label:"Catatan"`
+	Visibility     string              `bson:"visibility" json:"visibility" validate:"required,oneof=internal applicant" label:"Visibilitas"`
+	CreatedAt      primitive.DateTime  `bson:"created_at" json:"created_at"`
+	UpdatedAt      primitive.DateTime  `bson:"updated_at" json:"updated_at"`
+}
+
+// InterviewSlot sesuai dengan koleksi 'interview_slots'. RegistrationIDs berisi pendaftaran yang
+// sudah memesan slot ini, paling banyak sebanyak Capacity. Booked adalah jumlahnya, diubah bersama
+// RegistrationIDs dalam satu update agar kapasitas bisa dicek dan ditambah secara atomik.
+type InterviewSlot struct {
+	ID              primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	PeriodID        primitive.ObjectID   `bson:"period_id" json:"period_id"`
+	StartsAt        primitive.DateTime   `bson:"starts_at" json:"starts_at"`
+	EndsAt          primitive.DateTime   `bson:"ends_at" json:"ends_at"`
+	Location        string               `bson:"location,omitempty" json:"location,omitempty" validate:"max=200" label:"Lokasi"`
+	MeetingURL      string               `bson:"meeting_url,omitempty" json:"meeting_url,omitempty" validate:"max=500" label:"Tautan wawancara online"`
+	Interviewers    []primitive.ObjectID `bson:"interviewers" json:"interviewers"` // ID admin pewawancara
+	Capacity        int                  `bson:"capacity" json:"capacity" validate:"min=1,max=50" label:"Kapasitas"`
+	RegistrationIDs []primitive.ObjectID `bson:"registration_ids" json:"registration_ids"`
+	Booked          int                  `bson:"booked" json:"booked"`
+	CreatedAt       primitive.DateTime   `bson:"created_at" json:"created_at"`
+	UpdatedAt       primitive.DateTime   `bson:"updated_at" json:"updated_at"`
+}
+
+// InterviewRubric adalah kriteria penilaian wawancara sebuah divisi. Setiap kriteria dinilai
+// pada skala ScaleMin sampai ScaleMax dan diberi bobot.
+type InterviewRubric struct {
+	ScaleMin  int                `bson:"scale_min" json:"scale_min" validate:"min=0,max=10" label:"Nilai minimum"`
+	ScaleMax  int                `bson:"scale_max" json:"scale_max" validate:"min=1,max=100" label:"Nilai maksimum"`
+	Criteria  []RubricCriterion  `bson:"criteria" json:"criteria"`
+	UpdatedAt primitive.DateTime `bson:"updated_at" json:"updated_at"`
+}
+
+// RubricCriterion adalah satu kriteria pada rubrik wawancara
+type RubricCriterion struct {
+	Key         string `bson:"key" json:"key" validate:"required,max=50" label:"Kode kriteria"`
+	Name        string `bson:"name" json:"name" validate:"required,max=100" label:"Nama kriteria"`
+	Description string `bson:"description,omitempty" json:"description,omitempty" validate:"max=500" label:"Deskripsi kriteria"`
+	Weight      int    `bson:"weight" json:"weight" validate:"min=1,max=100" label:"Bobot"`
+}
+
+// InterviewScore sesuai dengan koleksi 'interview_scores'. Setiap pewawancara memiliki satu penilaian
+// per pendaftaran per divisi; Total adalah rata-rata berbobot yang dinormalisasi ke 0-100.
+type InterviewScore struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	RegistrationID primitive.ObjectID `bson:"registration_id" json:"registration_id"`
+	InterviewerID  primitive.ObjectID `bson:"interviewer_id" json:"interviewer_id"`
+	Division       string             `bson:"division" json:"division"`
+	Scores         []CriterionScore   `bson:"scores" json:"scores"`
+	Total          float64            `bson:"total" json:"total"`
+	Comment        string             `bson:"comment,omitempty" json:"comment,omitempty" validate:"max=2000" label:"Komentar"`
+	CreatedAt      primitive.DateTime `bson:"created_at" json:"created_at"`
+	UpdatedAt      primitive.DateTime `bson:"updated_at" json:"updated_at"`
+}
+
+// CriterionScore adalah nilai untuk satu kriteria rubrik
+type CriterionScore struct {
+	Key   string `bson:"key" json:"key"`
+	Score int    `bson:"score" json:"score"`
+}
+
 // ConfigCredential sesuai dengan koleksi 'configurasi' di database 'himatif'
 type ConfigCredential struct {
 	CloudinaryAPIKey    string `bson:"cloudinary_api_key,omitempty"`
@@ -71,4 +217,44 @@ type ConfigCredential struct {
 	CloudinaryCloudName string `bson:"cloudinary_cloud_name,omitempty"`
 	PasetoSecretKey     string `bson:"paseto_secret_key,omitempty"`
 	ServerPort          string `bson:"server_port,omitempty"`
+	SMTPPassword        string `bson:"smtp_password,omitempty"`
+}
+
+// RefreshToken sesuai dengan koleksi 'refresh_tokens'.
+// Token asli tidak disimpan, hanya hash SHA-256-nya.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	// TokenVersion harus sama dengan User.TokenVersion agar refresh token bisa dipakai
+	TokenVersion int                 `bson:"token_version" json:"-"`
+	ExpiresAt    primitive.DateTime  `bson:"expires_at" json:"expires_at"`
+	CreatedAt    primitive.DateTime  `bson:"created_at" json:"created_at"`
+	RevokedAt    *primitive.DateTime `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	ReplacedBy   primitive.ObjectID  `bson:"replaced_by,omitempty" json:"replaced_by,omitempty"`
+}
+
+// PasswordReset sesuai dengan koleksi 'password_resets'. Token hanya bisa dipakai sekali.
+type PasswordReset struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	TokenHash string              `bson:"token_hash" json:"-"`
+	ExpiresAt primitive.DateTime  `bson:"expires_at" json:"expires_at"`
+	CreatedAt primitive.DateTime  `bson:"created_at" json:"created_at"`
+	UsedAt    *primitive.DateTime `bson:"used_at,omitempty" json:"used_at,omitempty"`
+}
+
+// LoginAttempt sesuai dengan koleksi 'login_attempts'. Key berbentuk "nim:<nim>" atau "ip:<ip>".
+type LoginAttempt struct {
+	Key           string             `bson:"_id" json:"key"`
+	Failures      int                `bson:"failures" json:"failures"`
+	LastFailureAt primitive.DateTime `bson:"last_failure_at" json:"last_failure_at"`
+	LockedUntil   primitive.DateTime `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	ExpiresAt     primitive.DateTime `bson:"expires_at" json:"expires_at"`
+}
+
+// RevokedToken sesuai dengan koleksi 'revoked_tokens', berisi ID (jti) access token yang sudah dicabut
+type RevokedToken struct {
+	TokenID   string             `bson:"_id" json:"token_id"`
+	ExpiresAt primitive.DateTime `bson:"expires_at" json:"expires_at"`
 }
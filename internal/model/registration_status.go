@@ -0,0 +1,101 @@
+package model
+
+// Status pendaftaran
+const (
+	RegistrationStatusPending        = "pending"
+	RegistrationStatusDocumentReview = "document_review"
+	RegistrationStatusInterview      = "interview"
+	RegistrationStatusAccepted       = "accepted"
+	RegistrationStatusRejected       = "rejected"
+	RegistrationStatusWithdrawn      = "withdrawn"
+)
+
+// RegistrationStatusInfo menjelaskan satu status pada alur pendaftaran beserta status lanjutannya
+type RegistrationStatusInfo struct {
+	Value       string   `json:"value"`
+	Label       string   `json:"label"`
+	Description string   `json:"description"`
+	Next        []string `json:"next"`
+	Terminal    bool     `json:"terminal"`
+}
+
+// RegistrationStatuses adalah alur status pendaftaran, berurutan dari status awal.
+// Next berisi status yang boleh dituju dari status tersebut.
+var RegistrationStatuses = []RegistrationStatusInfo{
+	{
+		Value:       RegistrationStatusPending,
+		Label:       "Menunggu",
+		Description: "Formulir sudah dikirim dan masih bisa diubah pendaftar",
+		Next:        []string{RegistrationStatusDocumentReview, RegistrationStatusRejected, RegistrationStatusWithdrawn},
+	},
+	{
+		Value:       RegistrationStatusDocumentReview,
+		Label:       "Pemeriksaan Berkas",
+		Description: "Berkas sedang diperiksa panitia; dapat dikembalikan ke pendaftar untuk diperbaiki",
+		Next:        []string{RegistrationStatusPending, RegistrationStatusInterview, RegistrationStatusRejected, RegistrationStatusWithdrawn},
+	},
+	{
+		Value:       RegistrationStatusInterview,
+		Label:       "Wawancara",
+		Description: "Lolos berkas dan dijadwalkan wawancara",
+		Next:        []string{RegistrationStatusAccepted, RegistrationStatusRejected, RegistrationStatusWithdrawn},
+	},
+	{
+		Value:       RegistrationStatusAccepted,
+		Label:       "Diterima",
+		Description: "Diterima sebagai anggota",
+		Next:        []string{RegistrationStatusWithdrawn},
+	},
+	{
+		Value:       RegistrationStatusRejected,
+		Label:       "Ditolak",
+		Description: "Tidak lolos seleksi",
+		Next:        []string{},
+		Terminal:    true,
+	},
+	{
+		Value:       RegistrationStatusWithdrawn,
+		Label:       "Mengundurkan Diri",
+		Description: "Pendaftar mengundurkan diri",
+		Next:        []string{},
+		Terminal:    true,
+	},
+}
+
+// RegistrationStatusValues mengembalikan semua nilai status pendaftaran secara berurutan
+func RegistrationStatusValues() []string {
+	values := make([]string, len(RegistrationStatuses))
+	for i, status := range RegistrationStatuses {
+		values[i] = status.Value
+	}
+	return values
+}
+
+// FindRegistrationStatus mencari keterangan status pendaftaran berdasarkan nilainya
+func FindRegistrationStatus(value string) (RegistrationStatusInfo, bool) {
+	for _, status := range RegistrationStatuses {
+		if status.Value == value {
+			return status, true
+		}
+	}
+	return RegistrationStatusInfo{}, false
+}
+
+// CanTransitionRegistration mengecek apakah status pendaftaran boleh berubah dari from ke to.
+// Status yang sama selalu boleh. Status lama di luar daftar (data sebelum alur ini ada)
+// boleh diubah ke status mana pun agar bisa dirapikan.
+func CanTransitionRegistration(from, to string) bool {
+	if from == to {
+		return true
+	}
+	status, ok := FindRegistrationStatus(from)
+	if !ok {
+		return true
+	}
+	for _, next := range status.Next {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
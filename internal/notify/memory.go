@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryNotifier menyimpan semua pesan di memori, berguna untuk pengujian
+type MemoryNotifier struct {
+	mu       sync.RWMutex
+	messages []Message
+}
+
+// NewMemoryNotifier membuat MemoryNotifier kosong
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+func (n *MemoryNotifier) Send(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// Messages mengembalikan semua pesan yang sudah "dikirim"
+func (n *MemoryNotifier) Messages() []Message {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	result := make([]Message, len(n.messages))
+	copy(result, n.messages)
+	return result
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+)
+
+// Message adalah pesan yang dikirim ke user (misal email reset password)
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier mengirim pesan ke user. Implementasi bisa berupa email, log, dsb.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier tidak benar-benar mengirim pesan, hanya mencatatnya ke log dan
+// (opsional) menambahkannya ke file. Dipakai untuk pengembangan lokal.
+type LogNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewLogNotifier membuat LogNotifier. Jika path kosong, pesan hanya ditulis ke log.
+func NewLogNotifier(path string) *LogNotifier {
+	return &LogNotifier{path: path}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	log.Printf("📧 Notification to %s: %s", msg.To, msg.Subject)
+	if n.path == "" {
+		log.Println(msg.Body)
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %v", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "=== %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
+
+// New memilih Notifier sesuai konfigurasi: SMTP jika SMTP_HOST diisi, selain itu LogNotifier
+func New(cfg *config.Config) Notifier {
+	if cfg.SMTPHost != "" {
+		return NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+	return NewLogNotifier(cfg.NotifyLogFile)
+}
@@ -0,0 +1,171 @@
+// Package placement menempatkan pendaftar yang diterima ke divisi berdasarkan urutan pilihan,
+// nilai wawancara, dan kuota divisi.
+package placement
+
+import (
+	"bytes"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Alasan pendaftar tidak ditempatkan di salah satu pilihannya
+const (
+	ReasonQuotaFull        = "quota_full"        // Kuota terisi oleh pendaftar dengan peringkat lebih tinggi
+	ReasonUnknownDivision  = "unknown_division"  // Divisi tidak ada pada katalog
+	ReasonInactiveDivision = "inactive_division" // Divisi sedang dinonaktifkan
+)
+
+// Applicant adalah pendaftar yang akan ditempatkan
+type Applicant struct {
+	ID primitive.ObjectID
+	// Choices adalah divisi pilihan, urut dari yang paling diinginkan
+	Choices []string
+	// Scores adalah nilai pendaftar untuk setiap divisi pilihan; divisi tanpa nilai berada di peringkat terbawah
+	Scores map[string]float64
+}
+
+// Division adalah divisi tujuan penempatan
+type Division struct {
+	Name  string
+	Quota int // 0 = tidak dibatasi
+	// Inactive berarti divisi tidak menerima anggota; pelamarnya langsung dicoba ke pilihan berikutnya
+	Inactive bool
+}
+
+// Rejection menjelaskan mengapa pendaftar tidak ditempatkan di salah satu pilihannya
+type Rejection struct {
+	Division string
+	Choice   int // urutan pilihan, mulai dari 1
+	Reason   string
+	// Cutoff adalah nilai terendah yang ditempatkan di divisi tersebut (nil jika ia belum dinilai)
+	Cutoff *float64
+}
+
+// Assignment adalah hasil penempatan satu pendaftar
+type Assignment struct {
+	// Division kosong jika pendaftar tidak ditempatkan di pilihan mana pun
+	Division string
+	Choice   int // urutan pilihan yang didapat, 0 jika tidak ditempatkan
+	// Rank adalah peringkat pendaftar di antara anggota divisinya, mulai dari 1
+	Rank       int
+	Rejections []Rejection
+}
+
+// DivisionResult adalah anggota satu divisi, urut sesuai peringkat
+type DivisionResult struct {
+	Quota   int
+	Members []primitive.ObjectID
+	// Cutoff adalah nilai anggota berperingkat terakhir jika kuota terisi penuh
+	Cutoff *float64
+}
+
+// Result adalah hasil penempatan seluruh pendaftar
+type Result struct {
+	Assignments map[primitive.ObjectID]*Assignment
+	Divisions   map[string]*DivisionResult
+}
+
+// Run menempatkan pendaftar dengan algoritma deferred acceptance (Gale-Shapley) yang diajukan pendaftar:
+// setiap pendaftar melamar ke pilihan tertingginya yang belum menolak, dan setiap divisi hanya menahan
+// pendaftar berperingkat terbaik sebanyak kuotanya. Hasilnya stabil: tidak ada pendaftar yang lebih memilih
+// divisi lain yang sebenarnya akan menerimanya menggantikan anggota berperingkat lebih rendah.
+// Peringkat di suatu divisi ditentukan oleh nilai untuk divisi itu, lalu ID (waktu kirim) yang lebih awal.
+func Run(applicants []Applicant, divisions []Division) Result {
+	result := Result{
+		Assignments: make(map[primitive.ObjectID]*Assignment, len(applicants)),
+		Divisions:   make(map[string]*DivisionResult, len(divisions)),
+	}
+	inactive := make(map[string]bool)
+	for _, division := range divisions {
+		result.Divisions[division.Name] = &DivisionResult{Quota: division.Quota}
+		inactive[division.Name] = division.Inactive
+	}
+
+	byID := make(map[primitive.ObjectID]*Applicant, len(applicants))
+	next := make(map[primitive.ObjectID]int, len(applicants))
+	queue := make([]primitive.ObjectID, 0, len(applicants))
+	for i := range applicants {
+		applicant := &applicants[i]
+		byID[applicant.ID] = applicant
+		result.Assignments[applicant.ID] = &Assignment{}
+		queue = append(queue, applicant.ID)
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		applicant := byID[id]
+		if next[id] >= len(applicant.Choices) {
+			continue
+		}
+		choice := applicant.Choices[next[id]]
+		next[id]++
+
+		division, ok := result.Divisions[choice]
+		if !ok || inactive[choice] {
+			queue = append(queue, id)
+			continue
+		}
+		division.Members = append(division.Members, id)
+		sortMembers(division.Members, choice, byID)
+		if division.Quota > 0 && len(division.Members) > division.Quota {
+			// Anggota berperingkat terakhir dilepas dan melamar ke pilihan berikutnya
+			rejected := division.Members[division.Quota]
+			division.Members = division.Members[:division.Quota]
+			queue = append(queue, rejected)
+		}
+	}
+
+	for name, division := range result.Divisions {
+		for rank, id := range division.Members {
+			assignment := result.Assignments[id]
+			assignment.Division = name
+			assignment.Rank = rank + 1
+		}
+		if division.Quota > 0 && len(division.Members) == division.Quota {
+			last := byID[division.Members[len(division.Members)-1]]
+			if score, ok := last.Scores[name]; ok {
+				division.Cutoff = &score
+			}
+		}
+	}
+
+	// Penjelasan disusun dari hasil akhir: setiap pilihan yang lebih tinggi dari divisi yang didapat
+	// pasti menolak karena kuotanya terisi pendaftar berperingkat lebih tinggi
+	for _, applicant := range applicants {
+		assignment := result.Assignments[applicant.ID]
+		for i, choice := range applicant.Choices {
+			if choice == assignment.Division {
+				assignment.Choice = i + 1
+				break
+			}
+			rejection := Rejection{Division: choice, Choice: i + 1, Reason: ReasonQuotaFull}
+			if division, ok := result.Divisions[choice]; !ok {
+				rejection.Reason = ReasonUnknownDivision
+			} else if inactive[choice] {
+				rejection.Reason = ReasonInactiveDivision
+			} else {
+				rejection.Cutoff = division.Cutoff
+			}
+			assignment.Rejections = append(assignment.Rejections, rejection)
+		}
+	}
+	return result
+}
+
+// sortMembers mengurutkan anggota divisi dari peringkat tertinggi
+func sortMembers(members []primitive.ObjectID, division string, applicants map[primitive.ObjectID]*Applicant) {
+	sort.SliceStable(members, func(i, j int) bool {
+		a, b := applicants[members[i]], applicants[members[j]]
+		scoreA, okA := a.Scores[division]
+		scoreB, okB := b.Scores[division]
+		switch {
+		case okA != okB:
+			return okA
+		case okA && scoreA != scoreB:
+			return scoreA > scoreB
+		}
+		return bytes.Compare(a.ID[:], b.ID[:]) < 0
+	})
+}
@@ -0,0 +1,239 @@
+package placement
+
+import (
+	"bytes"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// id membuat ObjectID berurutan; n yang lebih kecil berarti mendaftar lebih awal
+func id(n byte) primitive.ObjectID {
+	var oid primitive.ObjectID
+	oid[len(oid)-1] = n
+	return oid
+}
+
+func applicant(n byte, choices []string, scores map[string]float64) Applicant {
+	return Applicant{ID: id(n), Choices: choices, Scores: scores}
+}
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		name       string
+		divisions  []Division
+		applicants []Applicant
+		// want memetakan nomor pendaftar ke divisi yang didapat ("" jika tidak ditempatkan)
+		want map[byte]string
+	}{
+		{
+			name:      "kuota penuh ke pilihan kedua",
+			divisions: []Division{{Name: "Web", Quota: 1}, {Name: "Data", Quota: 1}},
+			applicants: []Applicant{
+				applicant(1, []string{"Web", "Data"}, map[string]float64{"Web": 80, "Data": 80}),
+				applicant(2, []string{"Web", "Data"}, map[string]float64{"Web": 90, "Data": 70}),
+			},
+			want: map[byte]string{1: "Data", 2: "Web"},
+		},
+		{
+			name:      "nilai sama diurutkan dari yang mendaftar lebih awal",
+			divisions: []Division{{Name: "Web", Quota: 1}, {Name: "Data", Quota: 1}},
+			applicants: []Applicant{
+				applicant(2, []string{"Web", "Data"}, map[string]float64{"Web": 85, "Data": 85}),
+				applicant(1, []string{"Web", "Data"}, map[string]float64{"Web": 85, "Data": 85}),
+			},
+			want: map[byte]string{1: "Web", 2: "Data"},
+		},
+		{
+			name:      "tanpa nilai berada di peringkat terbawah",
+			divisions: []Division{{Name: "Web", Quota: 1}},
+			applicants: []Applicant{
+				applicant(1, []string{"Web"}, nil),
+				applicant(2, []string{"Web"}, map[string]float64{"Web": 10}),
+			},
+			want: map[byte]string{1: "", 2: "Web"},
+		},
+		{
+			name:      "pendaftar bernilai lebih tinggi menggeser anggota yang sudah ditahan",
+			divisions: []Division{{Name: "Web", Quota: 1}, {Name: "Data", Quota: 1}},
+			applicants: []Applicant{
+				applicant(1, []string{"Web", "Data"}, map[string]float64{"Web": 90, "Data": 80}),
+				applicant(2, []string{"Data", "Web"}, map[string]float64{"Data": 70, "Web": 95}),
+				applicant(3, []string{"Data"}, map[string]float64{"Data": 90}),
+			},
+			want: map[byte]string{1: "", 2: "Web", 3: "Data"},
+		},
+		{
+			name:      "kuota 0 tidak dibatasi",
+			divisions: []Division{{Name: "Web", Quota: 0}},
+			applicants: []Applicant{
+				applicant(1, []string{"Web"}, map[string]float64{"Web": 10}),
+				applicant(2, []string{"Web"}, map[string]float64{"Web": 20}),
+				applicant(3, []string{"Web"}, nil),
+			},
+			want: map[byte]string{1: "Web", 2: "Web", 3: "Web"},
+		},
+		{
+			name:      "divisi nonaktif dilewati",
+			divisions: []Division{{Name: "Web", Quota: 0, Inactive: true}, {Name: "Data", Quota: 1}},
+			applicants: []Applicant{
+				applicant(1, []string{"Web", "Data"}, map[string]float64{"Web": 99, "Data": 50}),
+				applicant(2, []string{"Web"}, map[string]float64{"Web": 99}),
+			},
+			want: map[byte]string{1: "Data", 2: ""},
+		},
+		{
+			name:      "divisi di luar katalog dilewati",
+			divisions: []Division{{Name: "Web", Quota: 1}},
+			applicants: []Applicant{
+				applicant(1, []string{"Game", "Web"}, map[string]float64{"Web": 50}),
+			},
+			want: map[byte]string{1: "Web"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Run(tc.applicants, tc.divisions)
+			for n, want := range tc.want {
+				if got := result.Assignments[id(n)].Division; got != want {
+					t.Errorf("pendaftar %d ditempatkan di %q, ingin %q", n, got, want)
+				}
+			}
+			assertStable(t, tc.applicants, tc.divisions, result)
+		})
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	divisions := []Division{{Name: "Web", Quota: 2}, {Name: "Data", Quota: 1}}
+	applicants := []Applicant{
+		applicant(1, []string{"Web", "Data"}, map[string]float64{"Web": 70, "Data": 70}),
+		applicant(2, []string{"Web", "Data"}, map[string]float64{"Web": 70, "Data": 70}),
+		applicant(3, []string{"Data", "Web"}, map[string]float64{"Web": 70, "Data": 70}),
+		applicant(4, []string{"Web", "Data"}, map[string]float64{"Web": 70, "Data": 70}),
+	}
+	reversed := make([]Applicant, len(applicants))
+	for i := range applicants {
+		reversed[len(applicants)-1-i] = applicants[i]
+	}
+
+	first, second := Run(applicants, divisions), Run(reversed, divisions)
+	for _, a := range applicants {
+		x, y := first.Assignments[a.ID], second.Assignments[a.ID]
+		if x.Division != y.Division || x.Rank != y.Rank {
+			t.Errorf("hasil %s bergantung pada urutan masukan: %s#%d dan %s#%d", a.ID.Hex(), x.Division, x.Rank, y.Division, y.Rank)
+		}
+	}
+	if members := first.Divisions["Web"].Members; len(members) != 2 || members[0] != id(1) || members[1] != id(2) {
+		t.Errorf("anggota Web %v, ingin pendaftar 1 lalu 2", members)
+	}
+}
+
+func TestRunExplainsRejections(t *testing.T) {
+	divisions := []Division{{Name: "Web", Quota: 1}, {Name: "Data", Quota: 1}, {Name: "Game", Inactive: true}}
+	applicants := []Applicant{
+		applicant(1, []string{"Web", "Data"}, map[string]float64{"Web": 90, "Data": 60}),
+		applicant(2, []string{"Game", "Web", "Data", "UI"}, map[string]float64{"Web": 80, "Data": 70}),
+		applicant(3, []string{"Web", "Data"}, map[string]float64{"Web": 70, "Data": 65}),
+	}
+	result := Run(applicants, divisions)
+
+	placed := result.Assignments[id(2)]
+	if placed.Division != "Data" || placed.Choice != 3 || placed.Rank != 1 {
+		t.Fatalf("pendaftar 2 = %+v, ingin Data pilihan 3 peringkat 1", placed)
+	}
+	wantReasons := []struct {
+		division string
+		reason   string
+		cutoff   float64
+	}{
+		{"Game", ReasonInactiveDivision, 0},
+		{"Web", ReasonQuotaFull, 90},
+	}
+	if len(placed.Rejections) != len(wantReasons) {
+		t.Fatalf("penolakan %+v, ingin %d", placed.Rejections, len(wantReasons))
+	}
+	for i, want := range wantReasons {
+		got := placed.Rejections[i]
+		if got.Division != want.division || got.Choice != i+1 || got.Reason != want.reason {
+			t.Errorf("penolakan %d = %+v, ingin %s pilihan %d karena %s", i, got, want.division, i+1, want.reason)
+		}
+		if want.reason == ReasonQuotaFull && (got.Cutoff == nil || *got.Cutoff != want.cutoff) {
+			t.Errorf("cutoff %s = %v, ingin %v", got.Division, got.Cutoff, want.cutoff)
+		}
+		if want.reason != ReasonQuotaFull && got.Cutoff != nil {
+			t.Errorf("cutoff %s = %v, ingin nil", got.Division, *got.Cutoff)
+		}
+	}
+
+	unplaced := result.Assignments[id(3)]
+	if unplaced.Division != "" || unplaced.Choice != 0 || len(unplaced.Rejections) != 2 {
+		t.Fatalf("pendaftar 3 = %+v, ingin tidak ditempatkan dengan dua penolakan", unplaced)
+	}
+	for _, rejection := range unplaced.Rejections {
+		if rejection.Reason != ReasonQuotaFull {
+			t.Errorf("penolakan %s karena %s, ingin %s", rejection.Division, rejection.Reason, ReasonQuotaFull)
+		}
+	}
+
+	if result.Assignments[id(1)].Rejections != nil {
+		t.Errorf("pendaftar yang mendapat pilihan pertama tidak boleh punya penolakan: %+v", result.Assignments[id(1)].Rejections)
+	}
+}
+
+func TestRunUnknownDivisionRejection(t *testing.T) {
+	result := Run([]Applicant{applicant(1, []string{"UI", "Web"}, nil)}, []Division{{Name: "Web"}})
+	rejections := result.Assignments[id(1)].Rejections
+	if len(rejections) != 1 || rejections[0].Division != "UI" || rejections[0].Reason != ReasonUnknownDivision {
+		t.Errorf("penolakan %+v, ingin UI karena %s", rejections, ReasonUnknownDivision)
+	}
+}
+
+// assertStable memastikan tidak ada blocking pair: pendaftar yang lebih memilih divisi aktif lain
+// yang masih punya kuota atau punya anggota berperingkat lebih rendah darinya
+func assertStable(t *testing.T, applicants []Applicant, divisions []Division, result Result) {
+	t.Helper()
+	active := make(map[string]bool)
+	for _, division := range divisions {
+		active[division.Name] = !division.Inactive
+	}
+	byID := make(map[primitive.ObjectID]Applicant)
+	for _, a := range applicants {
+		byID[a.ID] = a
+	}
+
+	for _, a := range applicants {
+		assignment := result.Assignments[a.ID]
+		for _, choice := range a.Choices {
+			if choice == assignment.Division {
+				break
+			}
+			if !active[choice] {
+				continue
+			}
+			division := result.Divisions[choice]
+			if division.Quota == 0 || len(division.Members) < division.Quota {
+				t.Errorf("%s lebih memilih %s yang masih punya kuota", a.ID.Hex(), choice)
+				continue
+			}
+			last := byID[division.Members[len(division.Members)-1]]
+			if ranksAbove(a, last, choice) {
+				t.Errorf("%s lebih memilih %s dan berperingkat di atas anggota %s", a.ID.Hex(), choice, last.ID.Hex())
+			}
+		}
+	}
+}
+
+// ranksAbove mengecek apakah a berperingkat di atas b pada divisi tertentu
+func ranksAbove(a, b Applicant, division string) bool {
+	scoreA, okA := a.Scores[division]
+	scoreB, okB := b.Scores[division]
+	if okA != okB {
+		return okA
+	}
+	if okA && scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
@@ -36,6 +36,14 @@ func ConnectDB(cfg *config.Config) {
 	log.Println("Successfully connected to MongoDB!")
 }
 
+// Database mengembalikan database aplikasi, atau nil jika MongoDB belum terkoneksi
+func Database() *mongo.Database {
+	if MongoClient == nil {
+		return nil
+	}
+	return MongoClient.Database(config.GetConfig().DatabaseName)
+}
+
 // GetConfigCredentials mengambil semua credentials dari collection configurasi.
 func GetConfigCredentials() (map[string]string, error) {
 	if MongoClient == nil {
@@ -79,6 +87,9 @@ func GetConfigCredentials() (map[string]string, error) {
 	if credentialDoc.ServerPort != "" {
 		credentials["SERVER_PORT"] = credentialDoc.ServerPort
 	}
+	if credentialDoc.SMTPPassword != "" {
+		credentials["SMTP_PASSWORD"] = credentialDoc.SMTPPassword
+	}
 
 	log.Printf("✅ Loaded %d credentials from database", len(credentials))
 	return credentials, nil
@@ -121,6 +132,8 @@ func GetConfigCredential(key string) (string, error) {
 		return credentialDoc.PasetoSecretKey, nil
 	case "SERVER_PORT":
 		return credentialDoc.ServerPort, nil
+	case "SMTP_PASSWORD":
+		return credentialDoc.SMTPPassword, nil
 	default:
 		return "", fmt.Errorf("credential '%s' not found in configuration", key)
 	}
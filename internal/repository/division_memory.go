@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryDivisionRepository adalah DivisionRepository in-memory untuk pengujian
+type MemoryDivisionRepository struct {
+	mu        sync.RWMutex
+	divisions []model.Division
+}
+
+// NewMemoryDivisionRepository membuat MemoryDivisionRepository kosong
+func NewMemoryDivisionRepository() *MemoryDivisionRepository {
+	return &MemoryDivisionRepository{}
+}
+
+func (r *MemoryDivisionRepository) Create(ctx context.Context, division *model.Division) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if division.ID.IsZero() {
+		division.ID = primitive.NewObjectID()
+	}
+	r.divisions = append(r.divisions, *division)
+	return nil
+}
+
+func (r *MemoryDivisionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Division, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, division := range r.divisions {
+		if division.ID == id {
+			return &division, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryDivisionRepository) FindByName(ctx context.Context, name string) (*model.Division, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, division := range r.divisions {
+		if strings.EqualFold(division.Name, name) {
+			return &division, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryDivisionRepository) List(ctx context.Context, activeOnly bool) ([]model.Division, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := []model.Division{}
+	for _, division := range r.divisions {
+		if activeOnly && !division.Active {
+			continue
+		}
+		results = append(results, division)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return strings.ToLower(results[i].Name) < strings.ToLower(results[j].Name)
+	})
+	return results, nil
+}
+
+func (r *MemoryDivisionRepository) Update(ctx context.Context, division *model.Division) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.divisions {
+		if r.divisions[i].ID == division.ID {
+			createdAt, rubric := r.divisions[i].CreatedAt, r.divisions[i].Rubric
+			r.divisions[i] = *division
+			r.divisions[i].CreatedAt = createdAt
+			r.divisions[i].Rubric = rubric
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryDivisionRepository) SetRubric(ctx context.Context, id primitive.ObjectID, rubric *model.InterviewRubric, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.divisions {
+		if r.divisions[i].ID == id {
+			if rubric != nil {
+				stored := *rubric
+				stored.Criteria = append([]model.RubricCriterion(nil), rubric.Criteria...)
+				rubric = &stored
+			}
+			r.divisions[i].Rubric = rubric
+			r.divisions[i].UpdatedAt = primitive.NewDateTimeFromTime(updatedAt)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryDivisionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.divisions {
+		if r.divisions[i].ID == id {
+			r.divisions = append(r.divisions[:i], r.divisions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
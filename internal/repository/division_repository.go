@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// divisionNameCollation membuat pencarian dan index unik nama divisi tidak membedakan huruf besar/kecil
+var divisionNameCollation = &options.Collation{Locale: "en", Strength: 2}
+
+type mongoDivisionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoDivisionRepository membuat DivisionRepository yang memakai koleksi 'divisions'
+func NewMongoDivisionRepository(db *mongo.Database) DivisionRepository {
+	return &mongoDivisionRepository{collection: db.Collection("divisions")}
+}
+
+func (r *mongoDivisionRepository) Create(ctx context.Context, division *model.Division) error {
+	if division.ID.IsZero() {
+		division.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, division)
+	return err
+}
+
+func (r *mongoDivisionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Division, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoDivisionRepository) FindByName(ctx context.Context, name string) (*model.Division, error) {
+	return r.findOne(ctx, bson.M{"name": name}, options.FindOne().SetCollation(divisionNameCollation))
+}
+
+func (r *mongoDivisionRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*model.Division, error) {
+	var division model.Division
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&division)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &division, nil
+}
+
+func (r *mongoDivisionRepository) List(ctx context.Context, activeOnly bool) ([]model.Division, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetCollation(divisionNameCollation)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.Division{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoDivisionRepository) Update(ctx context.Context, division *model.Division) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":         division.Name,
+			"description":  division.Description,
+			"quota":        division.Quota,
+			"active":       division.Active,
+			"coordinators": division.Coordinators,
+			"updated_at":   division.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": division.ID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoDivisionRepository) SetRubric(ctx context.Context, id primitive.ObjectID, rubric *model.InterviewRubric, updatedAt time.Time) error {
+	update := bson.M{"$set": bson.M{"updated_at": primitive.NewDateTimeFromTime(updatedAt)}}
+	if rubric != nil {
+		update["$set"].(bson.M)["rubric"] = rubric
+	} else {
+		update["$unset"] = bson.M{"rubric": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoDivisionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes membuat index yang dibutuhkan aplikasi dan melengkapi field baru pada data lama.
+// Aman dipanggil berulang kali.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		"divisions": {
+			// Nama divisi unik tanpa membedakan huruf besar/kecil
+			{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true).SetCollation(divisionNameCollation)},
+		},
+		"recruitment_periods": {
+			// Paling banyak satu periode berstatus active
+			{Keys: bson.D{{Key: "status", Value: 1}}, Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "active"})},
+			{Keys: bson.D{{Key: "opens_at", Value: -1}}},
+		},
+		"users": {
+			// Satu akun per NIM; pengecekan ExistsByNIM saja tidak cukup untuk registrasi yang bersamaan
+			{Keys: bson.D{{Key: "nim", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// Pencarian admin (UserRepository.Search); bahasa "none" agar kata tidak di-stem sebagai bahasa Inggris
+			{Keys: bson.D{{Key: "name", Value: "text"}, {Key: "nim", Value: "text"}, {Key: "email", Value: "text"}, {Key: "phone_number", Value: "text"}},
+				Options: options.Index().SetName("users_search").SetDefaultLanguage("none").
+					SetWeights(bson.D{{Key: "name", Value: 5}, {Key: "nim", Value: 5}, {Key: "email", Value: 2}, {Key: "phone_number", Value: 1}})},
+		},
+		"registrations": {
+			{Keys: bson.D{{Key: "period_id", Value: 1}}},
+			// Pencarian jawaban pendaftaran (SearchAnswers)
+			{Keys: bson.D{{Key: "motivation", Value: "text"}, {Key: "vision_mission", Value: "text"}},
+				Options: options.Index().SetName("registrations_search").SetDefaultLanguage("none")},
+			// Filter dan urutan daftar pendaftar admin (ListDetails); _id sekaligus waktu kirim
+			{Keys: bson.D{{Key: "period_id", Value: 1}, {Key: "status", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "period_id", Value: 1}, {Key: "division1", Value: 1}}},
+			{Keys: bson.D{{Key: "period_id", Value: 1}, {Key: "division2", Value: 1}}},
+			{Keys: bson.D{{Key: "period_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+			// Satu pendaftaran per user per periode; pendaftaran lama tanpa periode tidak ikut dibatasi
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "period_id", Value: 1}}, Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"period_id": bson.M{"$exists": true}})},
+		},
+		"registration_events": {
+			{Keys: bson.D{{Key: "registration_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		},
+		"registration_notes": {
+			{Keys: bson.D{{Key: "registration_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			{Keys: bson.D{{Key: "parent_id", Value: 1}}},
+		},
+		"interview_slots": {
+			{Keys: bson.D{{Key: "period_id", Value: 1}, {Key: "starts_at", Value: 1}}},
+			// Untuk mendeteksi jadwal pewawancara yang bentrok
+			{Keys: bson.D{{Key: "interviewers", Value: 1}, {Key: "starts_at", Value: 1}}},
+			{Keys: bson.D{{Key: "registration_ids", Value: 1}}},
+		},
+		"interview_scores": {
+			// Satu penilaian per pewawancara per pendaftaran per divisi; juga dipakai $lookup ListDetails
+			{Keys: bson.D{{Key: "registration_id", Value: 1}, {Key: "interviewer_id", Value: 1}, {Key: "division", Value: 1}},
+				Options: options.Index().SetUnique(true)},
+		},
+		"refresh_tokens": {
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			// Hapus otomatis refresh token yang sudah kedaluwarsa
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"password_resets": {
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"login_attempts": {
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"rate_limits": {
+			// Bucket yang sudah penuh kembali tidak perlu disimpan
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		"revoked_tokens": {
+			// Daftar token yang dicabut cukup disimpan sampai token aslinya kedaluwarsa
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %v", collection, err)
+		}
+	}
+
+	// Slot yang dibuat sebelum ada penghitung booked diisi dari jumlah pemesannya,
+	// karena Book hanya membandingkan booked dengan capacity
+	_, err := db.Collection("interview_slots").UpdateMany(ctx,
+		bson.M{"booked": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"booked": bson.M{"$size": bson.M{"$ifNull": bson.A{"$registration_ids", bson.A{}}}}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill interview slot bookings: %v", err)
+	}
+	return nil
+}
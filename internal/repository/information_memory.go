@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryInformationRepository adalah InformationRepository in-memory untuk pengujian
+type MemoryInformationRepository struct {
+	mu    sync.RWMutex
+	infos []model.Information
+}
+
+// NewMemoryInformationRepository membuat MemoryInformationRepository kosong
+func NewMemoryInformationRepository() *MemoryInformationRepository {
+	return &MemoryInformationRepository{}
+}
+
+func (r *MemoryInformationRepository) Create(ctx context.Context, info *model.Information) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info.ID.IsZero() {
+		info.ID = primitive.NewObjectID()
+	}
+	r.infos = append(r.infos, *info)
+	return nil
+}
+
+func (r *MemoryInformationRepository) List(ctx context.Context) ([]model.Information, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]model.Information, len(r.infos))
+	copy(results, r.infos)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt > results[j].CreatedAt
+	})
+	return results, nil
+}
+
+func (r *MemoryInformationRepository) Update(ctx context.Context, id primitive.ObjectID, title, content string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.infos {
+		if r.infos[i].ID == id {
+			r.infos[i].Title = title
+			r.infos[i].Content = content
+			r.infos[i].UpdatedAt = primitive.NewDateTimeFromTime(updatedAt)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryInformationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.infos {
+		if r.infos[i].ID == id {
+			r.infos = append(r.infos[:i], r.infos[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoInformationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoInformationRepository membuat InformationRepository yang memakai koleksi 'informations'
+func NewMongoInformationRepository(db *mongo.Database) InformationRepository {
+	return &mongoInformationRepository{collection: db.Collection("informations")}
+}
+
+func (r *mongoInformationRepository) Create(ctx context.Context, info *model.Information) error {
+	if info.ID.IsZero() {
+		info.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, info)
+	return err
+}
+
+func (r *mongoInformationRepository) List(ctx context.Context) ([]model.Information, error) {
+	// Urutkan berdasarkan yang terbaru
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.Information{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoInformationRepository) Update(ctx context.Context, id primitive.ObjectID, title, content string, updatedAt time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"title":      title,
+			"content":    content,
+			"updated_at": primitive.NewDateTimeFromTime(updatedAt),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoInformationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
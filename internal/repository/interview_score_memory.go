@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryInterviewScoreRepository adalah InterviewScoreRepository in-memory untuk pengujian
+type MemoryInterviewScoreRepository struct {
+	mu     sync.RWMutex
+	scores []model.InterviewScore
+}
+
+// NewMemoryInterviewScoreRepository membuat MemoryInterviewScoreRepository kosong
+func NewMemoryInterviewScoreRepository() *MemoryInterviewScoreRepository {
+	return &MemoryInterviewScoreRepository{}
+}
+
+func (r *MemoryInterviewScoreRepository) Upsert(ctx context.Context, score *model.InterviewScore) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *score
+	stored.Scores = append([]model.CriterionScore(nil), score.Scores...)
+	for i, existing := range r.scores {
+		if existing.RegistrationID == score.RegistrationID && existing.InterviewerID == score.InterviewerID && existing.Division == score.Division {
+			stored.ID = existing.ID
+			stored.CreatedAt = existing.CreatedAt
+			r.scores[i] = stored
+			score.ID, score.CreatedAt = stored.ID, stored.CreatedAt
+			return nil
+		}
+	}
+
+	stored.ID = primitive.NewObjectID()
+	r.scores = append(r.scores, stored)
+	score.ID = stored.ID
+	return nil
+}
+
+func (r *MemoryInterviewScoreRepository) ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.InterviewScore, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Penilaian disimpan berurutan sehingga sudah terurut dari yang terlama
+	results := []model.InterviewScore{}
+	for _, score := range r.scores {
+		if score.RegistrationID == registrationID {
+			results = append(results, score)
+		}
+	}
+	return results, nil
+}
+
+func (r *MemoryInterviewScoreRepository) ListByRegistrations(ctx context.Context, registrationIDs []primitive.ObjectID) ([]model.InterviewScore, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[primitive.ObjectID]struct{}, len(registrationIDs))
+	for _, id := range registrationIDs {
+		wanted[id] = struct{}{}
+	}
+	results := []model.InterviewScore{}
+	for _, score := range r.scores {
+		if _, ok := wanted[score.RegistrationID]; ok {
+			results = append(results, score)
+		}
+	}
+	return results, nil
+}
+
+func (r *MemoryInterviewScoreRepository) DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.scores[:0]
+	for _, score := range r.scores {
+		if score.RegistrationID != registrationID {
+			kept = append(kept, score)
+		}
+	}
+	r.scores = kept
+	return nil
+}
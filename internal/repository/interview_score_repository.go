@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoInterviewScoreRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoInterviewScoreRepository membuat InterviewScoreRepository yang memakai koleksi 'interview_scores'
+func NewMongoInterviewScoreRepository(db *mongo.Database) InterviewScoreRepository {
+	return &mongoInterviewScoreRepository{collection: db.Collection("interview_scores")}
+}
+
+func (r *mongoInterviewScoreRepository) Upsert(ctx context.Context, score *model.InterviewScore) error {
+	filter := bson.M{
+		"registration_id": score.RegistrationID,
+		"interviewer_id":  score.InterviewerID,
+		"division":        score.Division,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"scores":     score.Scores,
+			"total":      score.Total,
+			"comment":    score.Comment,
+			"updated_at": score.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"created_at": score.CreatedAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(score)
+}
+
+func (r *mongoInterviewScoreRepository) ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.InterviewScore, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	return r.find(ctx, bson.M{"registration_id": registrationID}, opts)
+}
+
+func (r *mongoInterviewScoreRepository) ListByRegistrations(ctx context.Context, registrationIDs []primitive.ObjectID) ([]model.InterviewScore, error) {
+	return r.find(ctx, bson.M{"registration_id": bson.M{"$in": registrationIDs}})
+}
+
+func (r *mongoInterviewScoreRepository) find(ctx context.Context, filter bson.M, opts ...*options.FindOptions) ([]model.InterviewScore, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.InterviewScore{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoInterviewScoreRepository) DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"registration_id": registrationID})
+	return err
+}
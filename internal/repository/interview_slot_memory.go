@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryInterviewSlotRepository adalah InterviewSlotRepository in-memory untuk pengujian
+type MemoryInterviewSlotRepository struct {
+	mu    sync.RWMutex
+	slots []model.InterviewSlot
+}
+
+// NewMemoryInterviewSlotRepository membuat MemoryInterviewSlotRepository kosong
+func NewMemoryInterviewSlotRepository() *MemoryInterviewSlotRepository {
+	return &MemoryInterviewSlotRepository{}
+}
+
+// copySlot menyalin slot beserta slice-nya agar data di repository tidak ikut berubah
+func copySlot(slot model.InterviewSlot) model.InterviewSlot {
+	slot.Interviewers = append([]primitive.ObjectID{}, slot.Interviewers...)
+	slot.RegistrationIDs = append([]primitive.ObjectID{}, slot.RegistrationIDs...)
+	return slot
+}
+
+func (r *MemoryInterviewSlotRepository) CreateMany(ctx context.Context, slots []model.InterviewSlot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range slots {
+		if slots[i].ID.IsZero() {
+			slots[i].ID = primitive.NewObjectID()
+		}
+		if slots[i].RegistrationIDs == nil {
+			slots[i].RegistrationIDs = []primitive.ObjectID{}
+		}
+		slots[i].Booked = len(slots[i].RegistrationIDs)
+		r.slots = append(r.slots, copySlot(slots[i]))
+	}
+	return nil
+}
+
+func (r *MemoryInterviewSlotRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.InterviewSlot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, slot := range r.slots {
+		if slot.ID == id {
+			found := copySlot(slot)
+			return &found, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryInterviewSlotRepository) List(ctx context.Context, filter InterviewSlotFilter) ([]model.InterviewSlot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := []model.InterviewSlot{}
+	for _, slot := range r.slots {
+		if !filter.PeriodID.IsZero() && slot.PeriodID != filter.PeriodID {
+			continue
+		}
+		if !filter.StartsAfter.IsZero() && !slot.StartsAt.Time().After(filter.StartsAfter) {
+			continue
+		}
+		if filter.AvailableOnly && slot.Booked >= slot.Capacity {
+			continue
+		}
+		results = append(results, copySlot(slot))
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].StartsAt < results[j].StartsAt })
+	return results, nil
+}
+
+func (r *MemoryInterviewSlotRepository) FindInterviewerConflicts(ctx context.Context, interviewers []primitive.ObjectID, startsAt, endsAt time.Time, excludeID primitive.ObjectID) ([]model.InterviewSlot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[primitive.ObjectID]struct{}, len(interviewers))
+	for _, id := range interviewers {
+		wanted[id] = struct{}{}
+	}
+
+	results := []model.InterviewSlot{}
+	for _, slot := range r.slots {
+		if slot.ID == excludeID || !slot.StartsAt.Time().Before(endsAt) || !slot.EndsAt.Time().After(startsAt) {
+			continue
+		}
+		for _, id := range slot.Interviewers {
+			if _, ok := wanted[id]; ok {
+				results = append(results, copySlot(slot))
+				break
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].StartsAt < results[j].StartsAt })
+	return results, nil
+}
+
+func (r *MemoryInterviewSlotRepository) Update(ctx context.Context, slot *model.InterviewSlot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.slots {
+		existing := &r.slots[i]
+		if existing.ID != slot.ID {
+			continue
+		}
+		if slot.Capacity < existing.Booked {
+			return ErrSlotInUse
+		}
+		existing.StartsAt = slot.StartsAt
+		existing.EndsAt = slot.EndsAt
+		existing.Location = slot.Location
+		existing.MeetingURL = slot.MeetingURL
+		existing.Interviewers = append([]primitive.ObjectID{}, slot.Interviewers...)
+		existing.Capacity = slot.Capacity
+		existing.UpdatedAt = slot.UpdatedAt
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryInterviewSlotRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.slots {
+		if r.slots[i].ID == id {
+			r.slots = append(r.slots[:i], r.slots[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryInterviewSlotRepository) Book(ctx context.Context, slotID, registrationID primitive.ObjectID, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.slots {
+		slot := &r.slots[i]
+		if slot.ID != slotID {
+			continue
+		}
+		for _, id := range slot.RegistrationIDs {
+			if id == registrationID {
+				return nil
+			}
+		}
+		if slot.Booked >= slot.Capacity {
+			return ErrSlotFull
+		}
+		slot.RegistrationIDs = append(slot.RegistrationIDs, registrationID)
+		slot.Booked++
+		slot.UpdatedAt = primitive.NewDateTimeFromTime(updatedAt)
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryInterviewSlotRepository) Release(ctx context.Context, slotID, registrationID primitive.ObjectID, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.slots {
+		slot := &r.slots[i]
+		if slot.ID != slotID {
+			continue
+		}
+		for j, id := range slot.RegistrationIDs {
+			if id == registrationID {
+				slot.RegistrationIDs = append(slot.RegistrationIDs[:j], slot.RegistrationIDs[j+1:]...)
+				slot.Booked--
+				slot.UpdatedAt = primitive.NewDateTimeFromTime(updatedAt)
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoInterviewSlotRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoInterviewSlotRepository membuat InterviewSlotRepository yang memakai koleksi 'interview_slots'
+func NewMongoInterviewSlotRepository(db *mongo.Database) InterviewSlotRepository {
+	return &mongoInterviewSlotRepository{collection: db.Collection("interview_slots")}
+}
+
+func (r *mongoInterviewSlotRepository) CreateMany(ctx context.Context, slots []model.InterviewSlot) error {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(slots))
+	for i := range slots {
+		if slots[i].ID.IsZero() {
+			slots[i].ID = primitive.NewObjectID()
+		}
+		// $push/$pull pada Book dan Release membutuhkan array, bukan null
+		if slots[i].RegistrationIDs == nil {
+			slots[i].RegistrationIDs = []primitive.ObjectID{}
+		}
+		slots[i].Booked = len(slots[i].RegistrationIDs)
+		docs[i] = slots[i]
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (r *mongoInterviewSlotRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.InterviewSlot, error) {
+	var slot model.InterviewSlot
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&slot)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (r *mongoInterviewSlotRepository) List(ctx context.Context, filter InterviewSlotFilter) ([]model.InterviewSlot, error) {
+	query := bson.M{}
+	if !filter.PeriodID.IsZero() {
+		query["period_id"] = filter.PeriodID
+	}
+	if !filter.StartsAfter.IsZero() {
+		query["starts_at"] = bson.M{"$gt": primitive.NewDateTimeFromTime(filter.StartsAfter)}
+	}
+	if filter.AvailableOnly {
+		query["$expr"] = bson.M{"$lt": bson.A{"$booked", "$capacity"}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}})
+	return r.find(ctx, query, opts)
+}
+
+func (r *mongoInterviewSlotRepository) FindInterviewerConflicts(ctx context.Context, interviewers []primitive.ObjectID, startsAt, endsAt time.Time, excludeID primitive.ObjectID) ([]model.InterviewSlot, error) {
+	if len(interviewers) == 0 {
+		return []model.InterviewSlot{}, nil
+	}
+
+	query := bson.M{
+		"interviewers": bson.M{"$in": interviewers},
+		"starts_at":    bson.M{"$lt": primitive.NewDateTimeFromTime(endsAt)},
+		"ends_at":      bson.M{"$gt": primitive.NewDateTimeFromTime(startsAt)},
+	}
+	if !excludeID.IsZero() {
+		query["_id"] = bson.M{"$ne": excludeID}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}})
+	return r.find(ctx, query, opts)
+}
+
+func (r *mongoInterviewSlotRepository) find(ctx context.Context, query bson.M, opts *options.FindOptions) ([]model.InterviewSlot, error) {
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.InterviewSlot{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoInterviewSlotRepository) Update(ctx context.Context, slot *model.InterviewSlot) error {
+	update := bson.M{"$set": bson.M{
+		"starts_at":    slot.StartsAt,
+		"ends_at":      slot.EndsAt,
+		"location":     slot.Location,
+		"meeting_url":  slot.MeetingURL,
+		"interviewers": slot.Interviewers,
+		"capacity":     slot.Capacity,
+		"updated_at":   slot.UpdatedAt,
+	}}
+
+	// Kapasitas baru dicek di filter agar tidak lebih kecil dari pemesanan yang masuk bersamaan
+	filter := bson.M{"_id": slot.ID, "booked": bson.M{"$lte": slot.Capacity}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	if _, err := r.FindByID(ctx, slot.ID); err != nil {
+		return err
+	}
+	return ErrSlotInUse
+}
+
+func (r *mongoInterviewSlotRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoInterviewSlotRepository) Book(ctx context.Context, slotID, registrationID primitive.ObjectID, updatedAt time.Time) error {
+	// booked < capacity dicek di filter dan booked ditambah pada update yang sama, sehingga dua
+	// pemesanan bersamaan tidak bisa sama-sama lolos; 0 dokumen cocok berarti slot penuh
+	filter := bson.M{
+		"_id":              slotID,
+		"registration_ids": bson.M{"$ne": registrationID},
+		"$expr":            bson.M{"$lt": bson.A{"$booked", "$capacity"}},
+	}
+	update := bson.M{
+		"$push": bson.M{"registration_ids": registrationID},
+		"$inc":  bson.M{"booked": 1},
+		"$set":  bson.M{"updated_at": primitive.NewDateTimeFromTime(updatedAt)},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	slot, err := r.FindByID(ctx, slotID)
+	if err != nil {
+		return err
+	}
+	for _, id := range slot.RegistrationIDs {
+		if id == registrationID {
+			return nil
+		}
+	}
+	return ErrSlotFull
+}
+
+func (r *mongoInterviewSlotRepository) Release(ctx context.Context, slotID, registrationID primitive.ObjectID, updatedAt time.Time) error {
+	update := bson.M{
+		"$pull": bson.M{"registration_ids": registrationID},
+		"$inc":  bson.M{"booked": -1},
+		"$set":  bson.M{"updated_at": primitive.NewDateTimeFromTime(updatedAt)},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": slotID, "registration_ids": registrationID}, update)
+	return err
+}
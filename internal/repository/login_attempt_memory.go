@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+)
+
+// MemoryLoginAttemptRepository adalah LoginAttemptRepository in-memory untuk pengujian
+type MemoryLoginAttemptRepository struct {
+	mu       sync.RWMutex
+	attempts map[string]model.LoginAttempt
+}
+
+// NewMemoryLoginAttemptRepository membuat MemoryLoginAttemptRepository kosong
+func NewMemoryLoginAttemptRepository() *MemoryLoginAttemptRepository {
+	return &MemoryLoginAttemptRepository{attempts: make(map[string]model.LoginAttempt)}
+}
+
+func (r *MemoryLoginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &attempt, nil
+}
+
+func (r *MemoryLoginAttemptRepository) Save(ctx context.Context, attempt *model.LoginAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts[attempt.Key] = *attempt
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLoginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoLoginAttemptRepository membuat LoginAttemptRepository yang memakai koleksi 'login_attempts'
+func NewMongoLoginAttemptRepository(db *mongo.Database) LoginAttemptRepository {
+	return &mongoLoginAttemptRepository{collection: db.Collection("login_attempts")}
+}
+
+func (r *mongoLoginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := r.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&attempt)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *mongoLoginAttemptRepository) Save(ctx context.Context, attempt *model.LoginAttempt) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": attempt.Key}, attempt, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *mongoLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
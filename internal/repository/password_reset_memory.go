@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryPasswordResetRepository adalah PasswordResetRepository in-memory untuk pengujian
+type MemoryPasswordResetRepository struct {
+	mu     sync.RWMutex
+	resets []model.PasswordReset
+}
+
+// NewMemoryPasswordResetRepository membuat MemoryPasswordResetRepository kosong
+func NewMemoryPasswordResetRepository() *MemoryPasswordResetRepository {
+	return &MemoryPasswordResetRepository{}
+}
+
+func (r *MemoryPasswordResetRepository) Create(ctx context.Context, reset *model.PasswordReset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reset.ID.IsZero() {
+		reset.ID = primitive.NewObjectID()
+	}
+	r.resets = append(r.resets, *reset)
+	return nil
+}
+
+func (r *MemoryPasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, reset := range r.resets {
+		if reset.TokenHash == tokenHash {
+			return &reset, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryPasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.resets {
+		if r.resets[i].ID == id && r.resets[i].UsedAt == nil {
+			at := primitive.NewDateTimeFromTime(usedAt)
+			r.resets[i].UsedAt = &at
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryPasswordResetRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.resets[:0]
+	for _, reset := range r.resets {
+		if reset.UserID != userID {
+			kept = append(kept, reset)
+		}
+	}
+	r.resets = kept
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoPasswordResetRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoPasswordResetRepository membuat PasswordResetRepository yang memakai koleksi 'password_resets'
+func NewMongoPasswordResetRepository(db *mongo.Database) PasswordResetRepository {
+	return &mongoPasswordResetRepository{collection: db.Collection("password_resets")}
+}
+
+func (r *mongoPasswordResetRepository) Create(ctx context.Context, reset *model.PasswordReset) error {
+	if reset.ID.IsZero() {
+		reset.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, reset)
+	return err
+}
+
+func (r *mongoPasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	var reset model.PasswordReset
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&reset)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+func (r *mongoPasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": primitive.NewDateTimeFromTime(usedAt)}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoPasswordResetRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimitRepository menyimpan token bucket di memori proses. Cukup untuk satu instance;
+// untuk beberapa instance Cloud Function gunakan NewMongoRateLimitRepository.
+type MemoryRateLimitRepository struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+// NewMemoryRateLimitRepository membuat MemoryRateLimitRepository kosong
+func NewMemoryRateLimitRepository() *MemoryRateLimitRepository {
+	return &MemoryRateLimitRepository{buckets: make(map[string]*memoryBucket)}
+}
+
+func (r *MemoryRateLimitRepository) Take(ctx context.Context, key string, capacity int, refillPerSecond float64, now time.Time) (bool, time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(now)
+
+	capacityF := float64(capacity)
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: capacityF, updatedAt: now}
+		r.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.updatedAt).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(capacityF, bucket.tokens+elapsed*refillPerSecond)
+	}
+	bucket.updatedAt = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	bucket.fullAt = now.Add(time.Duration((capacityF - bucket.tokens) / refillPerSecond * float64(time.Second)))
+
+	if allowed {
+		return true, 0, nil
+	}
+	return false, retryAfter(bucket.tokens, refillPerSecond), nil
+}
+
+// sweep menghapus bucket yang sudah penuh kembali, paling sering sekali per menit
+func (r *MemoryRateLimitRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < time.Minute {
+		return
+	}
+	r.lastSweep = now
+
+	for key, bucket := range r.buckets {
+		if !bucket.fullAt.After(now) {
+			delete(r.buckets, key)
+		}
+	}
+}
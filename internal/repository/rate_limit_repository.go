@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRateLimitRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRateLimitRepository membuat RateLimitRepository yang memakai koleksi 'rate_limits',
+// sehingga batas request berlaku bersama untuk semua instance Cloud Function
+func NewMongoRateLimitRepository(db *mongo.Database) RateLimitRepository {
+	return &mongoRateLimitRepository{collection: db.Collection("rate_limits")}
+}
+
+func (r *mongoRateLimitRepository) Take(ctx context.Context, key string, capacity int, refillPerSecond float64, now time.Time) (bool, time.Duration, error) {
+	nowMs := now.UnixMilli()
+	capacityF := float64(capacity)
+
+	// Pengisian ulang dan pengambilan token dilakukan atomik dalam satu update pipeline
+	elapsedSeconds := bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{nowMs, bson.M{"$ifNull": bson.A{"$updated_at", nowMs}}}},
+		1000,
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tokens": bson.M{"$min": bson.A{capacityF, bson.M{"$add": bson.A{
+				bson.M{"$ifNull": bson.A{"$tokens", capacityF}},
+				bson.M{"$multiply": bson.A{elapsedSeconds, refillPerSecond}},
+			}}}},
+			"updated_at": nowMs,
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"allowed": bson.M{"$gte": bson.A{"$tokens", 1}},
+			"tokens": bson.M{"$cond": bson.A{
+				bson.M{"$gte": bson.A{"$tokens", 1}},
+				bson.M{"$subtract": bson.A{"$tokens", 1}},
+				"$tokens",
+			}},
+			// Bucket boleh dihapus setelah kembali penuh
+			"expires_at": now.Add(time.Duration(capacityF / refillPerSecond * float64(time.Second))),
+		}}},
+	}
+
+	var bucket struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&bucket)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if bucket.Allowed {
+		return true, 0, nil
+	}
+	return false, retryAfter(bucket.Tokens, refillPerSecond), nil
+}
+
+// retryAfter menghitung waktu tunggu sampai bucket berisi minimal satu token
+func retryAfter(tokens, refillPerSecond float64) time.Duration {
+	seconds := (1 - tokens) / refillPerSecond
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRecruitmentPeriodRepository adalah RecruitmentPeriodRepository in-memory untuk pengujian
+type MemoryRecruitmentPeriodRepository struct {
+	mu      sync.RWMutex
+	periods []model.RecruitmentPeriod
+}
+
+// NewMemoryRecruitmentPeriodRepository membuat MemoryRecruitmentPeriodRepository kosong
+func NewMemoryRecruitmentPeriodRepository() *MemoryRecruitmentPeriodRepository {
+	return &MemoryRecruitmentPeriodRepository{}
+}
+
+func (r *MemoryRecruitmentPeriodRepository) Create(ctx context.Context, period *model.RecruitmentPeriod) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if period.ID.IsZero() {
+		period.ID = primitive.NewObjectID()
+	}
+	r.periods = append(r.periods, *period)
+	return nil
+}
+
+func (r *MemoryRecruitmentPeriodRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.RecruitmentPeriod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, period := range r.periods {
+		if period.ID == id {
+			return &period, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRecruitmentPeriodRepository) FindActive(ctx context.Context) (*model.RecruitmentPeriod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, period := range r.periods {
+		if period.Status == model.PeriodStatusActive {
+			return &period, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRecruitmentPeriodRepository) List(ctx context.Context) ([]model.RecruitmentPeriod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]model.RecruitmentPeriod, len(r.periods))
+	copy(results, r.periods)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].OpensAt > results[j].OpensAt
+	})
+	return results, nil
+}
+
+func (r *MemoryRecruitmentPeriodRepository) Update(ctx context.Context, period *model.RecruitmentPeriod) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.periods {
+		if r.periods[i].ID == period.ID {
+			createdAt := r.periods[i].CreatedAt
+			r.periods[i] = *period
+			r.periods[i].CreatedAt = createdAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryRecruitmentPeriodRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.periods {
+		if r.periods[i].ID == id {
+			r.periods = append(r.periods[:i], r.periods[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
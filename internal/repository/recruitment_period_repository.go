@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRecruitmentPeriodRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRecruitmentPeriodRepository membuat RecruitmentPeriodRepository yang memakai koleksi 'recruitment_periods'
+func NewMongoRecruitmentPeriodRepository(db *mongo.Database) RecruitmentPeriodRepository {
+	return &mongoRecruitmentPeriodRepository{collection: db.Collection("recruitment_periods")}
+}
+
+func (r *mongoRecruitmentPeriodRepository) Create(ctx context.Context, period *model.RecruitmentPeriod) error {
+	if period.ID.IsZero() {
+		period.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, period)
+	return err
+}
+
+func (r *mongoRecruitmentPeriodRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.RecruitmentPeriod, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoRecruitmentPeriodRepository) FindActive(ctx context.Context) (*model.RecruitmentPeriod, error) {
+	return r.findOne(ctx, bson.M{"status": model.PeriodStatusActive})
+}
+
+func (r *mongoRecruitmentPeriodRepository) findOne(ctx context.Context, filter bson.M) (*model.RecruitmentPeriod, error) {
+	var period model.RecruitmentPeriod
+	err := r.collection.FindOne(ctx, filter).Decode(&period)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+func (r *mongoRecruitmentPeriodRepository) List(ctx context.Context) ([]model.RecruitmentPeriod, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "opens_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.RecruitmentPeriod{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoRecruitmentPeriodRepository) Update(ctx context.Context, period *model.RecruitmentPeriod) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":          period.Name,
+			"academic_year": period.AcademicYear,
+			"opens_at":      period.OpensAt,
+			"closes_at":     period.ClosesAt,
+			"status":        period.Status,
+			"updated_at":    period.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": period.ID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRecruitmentPeriodRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRegistrationEventRepository adalah RegistrationEventRepository in-memory untuk pengujian
+type MemoryRegistrationEventRepository struct {
+	mu     sync.RWMutex
+	events []model.RegistrationEvent
+}
+
+// NewMemoryRegistrationEventRepository membuat MemoryRegistrationEventRepository kosong
+func NewMemoryRegistrationEventRepository() *MemoryRegistrationEventRepository {
+	return &MemoryRegistrationEventRepository{}
+}
+
+func (r *MemoryRegistrationEventRepository) Create(ctx context.Context, event *model.RegistrationEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *MemoryRegistrationEventRepository) CreateMany(ctx context.Context, events []model.RegistrationEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range events {
+		if events[i].ID.IsZero() {
+			events[i].ID = primitive.NewObjectID()
+		}
+		r.events = append(r.events, events[i])
+	}
+	return nil
+}
+
+func (r *MemoryRegistrationEventRepository) ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.RegistrationEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Event disimpan berurutan sehingga sudah terurut dari yang terlama
+	results := []model.RegistrationEvent{}
+	for _, event := range r.events {
+		if event.RegistrationID == registrationID {
+			results = append(results, event)
+		}
+	}
+	return results, nil
+}
+
+func (r *MemoryRegistrationEventRepository) DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.RegistrationID != registrationID {
+			kept = append(kept, event)
+		}
+	}
+	r.events = kept
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRegistrationEventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRegistrationEventRepository membuat RegistrationEventRepository yang memakai koleksi 'registration_events'
+func NewMongoRegistrationEventRepository(db *mongo.Database) RegistrationEventRepository {
+	return &mongoRegistrationEventRepository{collection: db.Collection("registration_events")}
+}
+
+func (r *mongoRegistrationEventRepository) Create(ctx context.Context, event *model.RegistrationEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+func (r *mongoRegistrationEventRepository) CreateMany(ctx context.Context, events []model.RegistrationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(events))
+	for i := range events {
+		if events[i].ID.IsZero() {
+			events[i].ID = primitive.NewObjectID()
+		}
+		docs[i] = events[i]
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (r *mongoRegistrationEventRepository) ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.RegistrationEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"registration_id": registrationID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.RegistrationEvent{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoRegistrationEventRepository) DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"registration_id": registrationID})
+	return err
+}
@@ -0,0 +1,604 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRegistrationRepository adalah RegistrationRepository in-memory untuk pengujian
+type MemoryRegistrationRepository struct {
+	mu            sync.RWMutex
+	users         UserRepository
+	scores        InterviewScoreRepository
+	events        RegistrationEventRepository
+	registrations []model.Registration
+}
+
+// NewMemoryRegistrationRepository membuat MemoryRegistrationRepository kosong.
+// users dan scores dipakai untuk menggabungkan data user dan nilai wawancara pada ListDetails,
+// events untuk riwayat status pada Stats.
+func NewMemoryRegistrationRepository(users UserRepository, scores InterviewScoreRepository, events RegistrationEventRepository) *MemoryRegistrationRepository {
+	return &MemoryRegistrationRepository{users: users, scores: scores, events: events}
+}
+
+func (r *MemoryRegistrationRepository) Create(ctx context.Context, registration *model.Registration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sama seperti index unik (user_id, period_id) pada MongoDB
+	if !registration.PeriodID.IsZero() {
+		for _, existing := range r.registrations {
+			if existing.UserID == registration.UserID && existing.PeriodID == registration.PeriodID {
+				return ErrDuplicate
+			}
+		}
+	}
+
+	if registration.ID.IsZero() {
+		registration.ID = primitive.NewObjectID()
+	}
+	r.registrations = append(r.registrations, *registration)
+	return nil
+}
+
+func (r *MemoryRegistrationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Registration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, registration := range r.registrations {
+		if registration.ID == id {
+			return &registration, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Registration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[primitive.ObjectID]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+
+	registrations := []model.Registration{}
+	for _, registration := range r.registrations {
+		if _, ok := wanted[registration.ID]; ok {
+			registrations = append(registrations, registration)
+		}
+	}
+	return registrations, nil
+}
+
+func (r *MemoryRegistrationRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Registration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Pendaftaran disimpan berurutan, jadi yang terakhir adalah yang terbaru
+	for i := len(r.registrations) - 1; i >= 0; i-- {
+		if r.registrations[i].UserID == userID {
+			registration := r.registrations[i]
+			return &registration, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) FindByUserAndPeriod(ctx context.Context, userID, periodID primitive.ObjectID) (*model.Registration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, registration := range r.registrations {
+		if registration.UserID == userID && registration.PeriodID == periodID {
+			return &registration, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Registration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := []model.Registration{}
+	for _, registration := range r.registrations {
+		if registration.UserID == userID {
+			results = append(results, registration)
+		}
+	}
+	return results, nil
+}
+
+func (r *MemoryRegistrationRepository) ListDetails(ctx context.Context, filter RegistrationFilter) ([]model.RegistrationDetail, int64, error) {
+	r.mu.RLock()
+	registrations := make([]model.Registration, len(r.registrations))
+	copy(registrations, r.registrations)
+	r.mu.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+
+	results := []model.RegistrationDetail{}
+	for _, reg := range registrations {
+		if !matchesRegistrationFilter(&reg, filter) {
+			continue
+		}
+
+		// Sama seperti $unwind, pendaftaran tanpa user dilewati
+		user, err := r.users.FindByID(ctx, reg.UserID)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if search != "" && !strings.Contains(strings.ToLower(user.Name), search) && !strings.Contains(strings.ToLower(user.NIM), search) {
+			continue
+		}
+
+		scores, err := r.scores.ListByRegistration(ctx, reg.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		var average *float64
+		if len(scores) > 0 {
+			var sum float64
+			for _, score := range scores {
+				sum += score.Total
+			}
+			avg := math.Round(sum/float64(len(scores))*100) / 100
+			average = &avg
+		}
+
+		results = append(results, model.RegistrationDetail{
+			ID:                     reg.ID,
+			UserID:                 reg.UserID,
+			PeriodID:               reg.PeriodID,
+			Name:                   user.Name,
+			NIM:                    user.NIM,
+			Email:                  user.Email,
+			PhoneNumber:            user.PhoneNumber,
+			Division1:              reg.Division1,
+			Division2:              reg.Division2,
+			Motivation:             reg.Motivation,
+			VisionMission:          reg.VisionMission,
+			InterviewSchedule:      reg.InterviewSchedule,
+			InterviewLocation:      reg.InterviewLocation,
+			InterviewSlotID:        reg.InterviewSlotID,
+			CvUrl:                  reg.CvUrl,
+			CertificateUrl:         reg.CertificateUrl,
+			OptionalCertificateUrl: reg.OptionalCertificateUrl,
+			FormalPhotoUrl:         reg.FormalPhotoUrl,
+			Status:                 reg.Status,
+			PlacedDivision:         reg.PlacedDivision,
+			Note:                   reg.Note,
+			UpdatedAt:              reg.UpdatedAt,
+			ScoreAverage:           average,
+			ScoreCount:             len(scores),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		c := compareDetails(&results[i], &results[j], filter.Sort)
+		if filter.Descending {
+			c = -c
+		}
+		if c == 0 {
+			return compareIDs(results[i].ID, results[j].ID) < 0
+		}
+		return c < 0
+	})
+
+	total := int64(len(results))
+	if filter.PerPage > 0 {
+		start := (filter.Page - 1) * filter.PerPage
+		if start > len(results) {
+			start = len(results)
+		}
+		end := start + filter.PerPage
+		if end > len(results) {
+			end = len(results)
+		}
+		results = results[start:end]
+	}
+	return results, total, nil
+}
+
+// matchesRegistrationFilter mengecek field pendaftaran terhadap filter, sama seperti registrationMatch pada MongoDB
+func matchesRegistrationFilter(reg *model.Registration, filter RegistrationFilter) bool {
+	if !filter.PeriodID.IsZero() && reg.PeriodID != filter.PeriodID {
+		return false
+	}
+	if filter.IDs != nil && !containsObjectID(filter.IDs, reg.ID) {
+		return false
+	}
+	if filter.UserIDs != nil && !containsObjectID(filter.UserIDs, reg.UserID) {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !containsString(filter.Statuses, reg.Status) {
+		return false
+	}
+	if filter.Division != "" && reg.Division1 != filter.Division && reg.Division2 != filter.Division {
+		return false
+	}
+	// Batas waktu kirim dibandingkan sebagai ObjectID, sama seperti filter _id pada MongoDB
+	if !filter.SubmittedFrom.IsZero() && compareIDs(reg.ID, primitive.NewObjectIDFromTimestamp(filter.SubmittedFrom)) < 0 {
+		return false
+	}
+	if !filter.SubmittedBefore.IsZero() && compareIDs(reg.ID, primitive.NewObjectIDFromTimestamp(filter.SubmittedBefore)) >= 0 {
+		return false
+	}
+	if !filter.AfterID.IsZero() {
+		if cmp := compareIDs(reg.ID, filter.AfterID); cmp == 0 || (cmp < 0) != filter.Descending {
+			return false
+		}
+	}
+	return true
+}
+
+// userIDs mengembalikan user yang memiliki pendaftaran sesuai filter
+func (r *MemoryRegistrationRepository) userIDs(filter RegistrationFilter) map[primitive.ObjectID]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make(map[primitive.ObjectID]bool)
+	for _, reg := range r.registrations {
+		if matchesRegistrationFilter(&reg, filter) {
+			ids[reg.UserID] = true
+		}
+	}
+	return ids
+}
+
+func (r *MemoryRegistrationRepository) SearchAnswers(ctx context.Context, query string, filter RegistrationFilter, limit int) ([]RegistrationMatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := textTerms(query)
+	matches := []RegistrationMatch{}
+	for _, reg := range r.registrations {
+		if !matchesRegistrationFilter(&reg, filter) {
+			continue
+		}
+		if score := textScore(terms, reg.Motivation, reg.VisionMission); score > 0 {
+			matches = append(matches, RegistrationMatch{Registration: reg, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return compareIDs(matches[i].Registration.ID, matches[j].Registration.ID) < 0
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// textTerms memecah teks menjadi kata huruf kecil, mendekati tokenizer text index MongoDB (bahasa "none")
+func textTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// textScore menghitung jumlah kata pada fields yang sama dengan salah satu terms.
+// Ini hanya pendekatan untuk $meta textScore, cukup untuk pengujian urutan hasil.
+func textScore(terms []string, fields ...string) float64 {
+	var score float64
+	for _, field := range fields {
+		for _, word := range textTerms(field) {
+			if containsString(terms, word) {
+				score++
+			}
+		}
+	}
+	return score
+}
+
+// compareDetails membandingkan dua pendaftaran berdasarkan urutan RegistrationFilter.Sort,
+// mengikuti aturan $sort MongoDB (null lebih kecil dari angka)
+func compareDetails(a, b *model.RegistrationDetail, sortBy string) int {
+	switch sortBy {
+	case RegistrationSortUpdated:
+		return compareInts(int64(a.UpdatedAt), int64(b.UpdatedAt))
+	case RegistrationSortName:
+		return strings.Compare(a.Name, b.Name)
+	case RegistrationSortNIM:
+		return strings.Compare(a.NIM, b.NIM)
+	case RegistrationSortStatus:
+		return strings.Compare(a.Status, b.Status)
+	case RegistrationSortScore:
+		switch {
+		case a.ScoreAverage == nil && b.ScoreAverage == nil:
+			return 0
+		case a.ScoreAverage == nil:
+			return -1
+		case b.ScoreAverage == nil:
+			return 1
+		case *a.ScoreAverage < *b.ScoreAverage:
+			return -1
+		case *a.ScoreAverage > *b.ScoreAverage:
+			return 1
+		}
+		return 0
+	default:
+		return compareIDs(a.ID, b.ID)
+	}
+}
+
+func compareIDs(a, b primitive.ObjectID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func containsObjectID(values []primitive.ObjectID, value primitive.ObjectID) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryRegistrationRepository) Update(ctx context.Context, id primitive.ObjectID, update RegistrationUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.registrations {
+		reg := &r.registrations[i]
+		if reg.ID != id {
+			continue
+		}
+		if update.Status != nil {
+			reg.Status = *update.Status
+		}
+		if update.InterviewSchedule != nil {
+			reg.InterviewSchedule = *update.InterviewSchedule
+		}
+		if update.InterviewLocation != nil {
+			reg.InterviewLocation = *update.InterviewLocation
+		}
+		reg.UpdatedAt = primitive.NewDateTimeFromTime(update.UpdatedAt)
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) UpdateSubmission(ctx context.Context, registration *model.Registration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.registrations {
+		reg := &r.registrations[i]
+		if reg.ID != registration.ID || reg.Status != model.RegistrationStatusPending {
+			continue
+		}
+		reg.Division1 = registration.Division1
+		reg.Division2 = registration.Division2
+		reg.Motivation = registration.Motivation
+		reg.VisionMission = registration.VisionMission
+		reg.CvUrl = registration.CvUrl
+		reg.CertificateUrl = registration.CertificateUrl
+		reg.OptionalCertificateUrl = registration.OptionalCertificateUrl
+		reg.FormalPhotoUrl = registration.FormalPhotoUrl
+		reg.UpdatedAt = registration.UpdatedAt
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) SetInterviewSlot(ctx context.Context, id primitive.ObjectID, slotID *primitive.ObjectID, schedule, location string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.registrations {
+		reg := &r.registrations[i]
+		if reg.ID != id {
+			continue
+		}
+		reg.InterviewSlotID = slotID
+		reg.InterviewSchedule = schedule
+		reg.InterviewLocation = location
+		reg.UpdatedAt = primitive.NewDateTimeFromTime(updatedAt)
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) BulkUpdateStatus(ctx context.Context, ids []primitive.ObjectID, allowedFrom []string, status string, updatedAt time.Time) ([]model.Registration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wanted := make(map[primitive.ObjectID]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+	allowed := make(map[string]struct{}, len(allowedFrom))
+	for _, from := range allowedFrom {
+		allowed[from] = struct{}{}
+	}
+
+	var previous []model.Registration
+	for i := range r.registrations {
+		if _, ok := wanted[r.registrations[i].ID]; !ok {
+			continue
+		}
+		if _, ok := allowed[r.registrations[i].Status]; !ok {
+			continue
+		}
+		previous = append(previous, r.registrations[i])
+		r.registrations[i].Status = status
+		r.registrations[i].UpdatedAt = primitive.NewDateTimeFromTime(updatedAt)
+	}
+	return previous, nil
+}
+
+func (r *MemoryRegistrationRepository) SetPlacements(ctx context.Context, periodID primitive.ObjectID, placements map[primitive.ObjectID]string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := primitive.NewDateTimeFromTime(updatedAt)
+	for i := range r.registrations {
+		reg := &r.registrations[i]
+		if reg.PeriodID != periodID {
+			continue
+		}
+		division, ok := placements[reg.ID]
+		if !ok && reg.PlacedDivision == "" {
+			continue
+		}
+		reg.PlacedDivision = division
+		reg.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *MemoryRegistrationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.registrations {
+		if r.registrations[i].ID == id {
+			r.registrations = append(r.registrations[:i], r.registrations[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryRegistrationRepository) CountByDivision(ctx context.Context, division string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, reg := range r.registrations {
+		if strings.EqualFold(reg.Division1, division) || strings.EqualFold(reg.Division2, division) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryRegistrationRepository) RenameDivision(ctx context.Context, oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.registrations {
+		if strings.EqualFold(r.registrations[i].Division1, oldName) {
+			r.registrations[i].Division1 = newName
+		}
+		if strings.EqualFold(r.registrations[i].Division2, oldName) {
+			r.registrations[i].Division2 = newName
+		}
+		if strings.EqualFold(r.registrations[i].PlacedDivision, oldName) {
+			r.registrations[i].PlacedDivision = newName
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRegistrationRepository) CountByPeriod(ctx context.Context, periodID primitive.ObjectID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, reg := range r.registrations {
+		if reg.PeriodID == periodID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// statsLocation adalah zona waktu WIB untuk pengelompokan per tanggal, sama dengan statsTimeZone
+var statsLocation = time.FixedZone("WIB", 7*60*60)
+
+func (r *MemoryRegistrationRepository) Stats(ctx context.Context, filter RegistrationFilter) (*RegistrationStats, error) {
+	r.mu.RLock()
+	registrations := make([]model.Registration, 0, len(r.registrations))
+	for _, reg := range r.registrations {
+		if matchesRegistrationFilter(&reg, filter) {
+			registrations = append(registrations, reg)
+		}
+	}
+	r.mu.RUnlock()
+
+	stats := &RegistrationStats{
+		Total:       int64(len(registrations)),
+		ByStatus:    map[string]int64{},
+		ByDivision1: map[string]int64{},
+		ByDivision2: map[string]int64{},
+		Reached:     map[string]int64{},
+		Durations:   map[string]StatusDuration{},
+	}
+	perDay := map[string]int64{}
+	totals := map[string]time.Duration{}
+	for _, reg := range registrations {
+		stats.ByStatus[reg.Status]++
+		stats.ByDivision1[reg.Division1]++
+		stats.ByDivision2[reg.Division2]++
+		perDay[reg.ID.Timestamp().In(statsLocation).Format("2006-01-02")]++
+
+		events, err := r.events.ListByRegistration(ctx, reg.ID)
+		if err != nil {
+			return nil, err
+		}
+		reached := map[string]bool{reg.Status: true}
+		var previous *model.RegistrationEvent
+		for i := range events {
+			if events[i].NewStatus == "" {
+				continue
+			}
+			reached[events[i].NewStatus] = true
+			if previous != nil {
+				duration := stats.Durations[previous.NewStatus]
+				duration.Count++
+				stats.Durations[previous.NewStatus] = duration
+				totals[previous.NewStatus] += events[i].CreatedAt.Time().Sub(previous.CreatedAt.Time())
+			}
+			previous = &events[i]
+		}
+		for status := range reached {
+			stats.Reached[status]++
+		}
+	}
+
+	for status, duration := range stats.Durations {
+		duration.Average = totals[status] / time.Duration(duration.Count)
+		stats.Durations[status] = duration
+	}
+	for date, count := range perDay {
+		stats.PerDay = append(stats.PerDay, DailyCount{Date: date, Count: count})
+	}
+	sort.Slice(stats.PerDay, func(i, j int) bool { return stats.PerDay[i].Date < stats.PerDay[j].Date })
+	return stats, nil
+}
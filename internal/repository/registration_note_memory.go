@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRegistrationNoteRepository adalah RegistrationNoteRepository in-memory untuk pengujian
+type MemoryRegistrationNoteRepository struct {
+	mu    sync.RWMutex
+	notes []model.RegistrationNote
+}
+
+// NewMemoryRegistrationNoteRepository membuat MemoryRegistrationNoteRepository kosong
+func NewMemoryRegistrationNoteRepository() *MemoryRegistrationNoteRepository {
+	return &MemoryRegistrationNoteRepository{}
+}
+
+func (r *MemoryRegistrationNoteRepository) Create(ctx context.Context, note *model.RegistrationNote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+	r.notes = append(r.notes, *note)
+	return nil
+}
+
+func (r *MemoryRegistrationNoteRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.RegistrationNote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, note := range r.notes {
+		if note.ID == id {
+			return &note, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryRegistrationNoteRepository) ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.RegistrationNote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Catatan disimpan berurutan sehingga sudah terurut dari yang terlama
+	results := []model.RegistrationNote{}
+	for _, note := range r.notes {
+		if note.RegistrationID == registrationID {
+			results = append(results, note)
+		}
+	}
+	return results, nil
+}
+
+func (r *MemoryRegistrationNoteRepository) Update(ctx context.Context, note *model.RegistrationNote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.notes {
+		if r.notes[i].ID == note.ID {
+			r.notes[i].Body = note.Body
+			r.notes[i].Visibility = note.Visibility
+			r.notes[i].UpdatedAt = note.UpdatedAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryRegistrationNoteRepository) SetThreadVisibility(ctx context.Context, rootID primitive.ObjectID, visibility string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.notes {
+		if r.notes[i].ID == rootID || (r.notes[i].ParentID != nil && *r.notes[i].ParentID == rootID) {
+			r.notes[i].Visibility = visibility
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRegistrationNoteRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := false
+	kept := r.notes[:0]
+	for _, note := range r.notes {
+		if note.ID == id {
+			found = true
+			continue
+		}
+		if note.ParentID != nil && *note.ParentID == id {
+			continue
+		}
+		kept = append(kept, note)
+	}
+	r.notes = kept
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MemoryRegistrationNoteRepository) DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.notes[:0]
+	for _, note := range r.notes {
+		if note.RegistrationID != registrationID {
+			kept = append(kept, note)
+		}
+	}
+	r.notes = kept
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRegistrationNoteRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRegistrationNoteRepository membuat RegistrationNoteRepository yang memakai koleksi 'registration_notes'
+func NewMongoRegistrationNoteRepository(db *mongo.Database) RegistrationNoteRepository {
+	return &mongoRegistrationNoteRepository{collection: db.Collection("registration_notes")}
+}
+
+func (r *mongoRegistrationNoteRepository) Create(ctx context.Context, note *model.RegistrationNote) error {
+	if note.ID.IsZero() {
+		note.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, note)
+	return err
+}
+
+func (r *mongoRegistrationNoteRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.RegistrationNote, error) {
+	var note model.RegistrationNote
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&note)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *mongoRegistrationNoteRepository) ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.RegistrationNote, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"registration_id": registrationID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.RegistrationNote{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoRegistrationNoteRepository) Update(ctx context.Context, note *model.RegistrationNote) error {
+	update := bson.M{"$set": bson.M{
+		"body":       note.Body,
+		"visibility": note.Visibility,
+		"updated_at": note.UpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": note.ID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRegistrationNoteRepository) SetThreadVisibility(ctx context.Context, rootID primitive.ObjectID, visibility string) error {
+	filter := bson.M{"$or": bson.A{bson.M{"_id": rootID}, bson.M{"parent_id": rootID}}}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"visibility": visibility}})
+	return err
+}
+
+func (r *mongoRegistrationNoteRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	_, err = r.collection.DeleteMany(ctx, bson.M{"parent_id": id})
+	return err
+}
+
+func (r *mongoRegistrationNoteRepository) DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"registration_id": registrationID})
+	return err
+}
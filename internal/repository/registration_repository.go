@@ -0,0 +1,547 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRegistrationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRegistrationRepository membuat RegistrationRepository yang memakai koleksi 'registrations'
+func NewMongoRegistrationRepository(db *mongo.Database) RegistrationRepository {
+	return &mongoRegistrationRepository{collection: db.Collection("registrations")}
+}
+
+func (r *mongoRegistrationRepository) Create(ctx context.Context, registration *model.Registration) error {
+	if registration.ID.IsZero() {
+		registration.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, registration)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoRegistrationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Registration, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoRegistrationRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Registration, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	registrations := []model.Registration{}
+	if err := cursor.All(ctx, &registrations); err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+func (r *mongoRegistrationRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Registration, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	return r.findOne(ctx, bson.M{"user_id": userID}, opts)
+}
+
+func (r *mongoRegistrationRepository) FindByUserAndPeriod(ctx context.Context, userID, periodID primitive.ObjectID) (*model.Registration, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID, "period_id": periodID})
+}
+
+func (r *mongoRegistrationRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Registration, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.Registration{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *mongoRegistrationRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*model.Registration, error) {
+	var registration model.Registration
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&registration)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// registrationSortFields memetakan RegistrationFilter.Sort ke field pada pipeline ListDetails
+var registrationSortFields = map[string]string{
+	RegistrationSortSubmitted: "_id",
+	RegistrationSortUpdated:   "updated_at",
+	RegistrationSortName:      "userDetails.name",
+	RegistrationSortNIM:       "userDetails.nim",
+	RegistrationSortStatus:    "status",
+	RegistrationSortScore:     "score_average",
+}
+
+// registrationMatch membangun filter pada field pendaftaran dari RegistrationFilter
+func registrationMatch(filter RegistrationFilter) bson.D {
+	match := bson.D{}
+	if !filter.PeriodID.IsZero() {
+		match = append(match, bson.E{Key: "period_id", Value: filter.PeriodID})
+	}
+	if filter.UserIDs != nil {
+		match = append(match, bson.E{Key: "user_id", Value: bson.M{"$in": filter.UserIDs}})
+	}
+	if len(filter.Statuses) > 0 {
+		match = append(match, bson.E{Key: "status", Value: bson.M{"$in": filter.Statuses}})
+	}
+	if filter.Division != "" {
+		match = append(match, bson.E{Key: "$or", Value: bson.A{bson.M{"division1": filter.Division}, bson.M{"division2": filter.Division}}})
+	}
+	// Waktu kirim diambil dari timestamp ObjectID sehingga digabung dengan filter IDs pada field _id
+	ids := bson.M{}
+	if filter.IDs != nil {
+		ids["$in"] = filter.IDs
+	}
+	if !filter.SubmittedFrom.IsZero() {
+		ids["$gte"] = primitive.NewObjectIDFromTimestamp(filter.SubmittedFrom)
+	}
+	if !filter.SubmittedBefore.IsZero() {
+		ids["$lt"] = primitive.NewObjectIDFromTimestamp(filter.SubmittedBefore)
+	}
+	if len(ids) > 0 {
+		match = append(match, bson.E{Key: "_id", Value: ids})
+	}
+	// Dipisah lewat $and karena $lt bisa sudah dipakai SubmittedBefore pada kondisi _id di atas
+	if !filter.AfterID.IsZero() {
+		operator := "$gt"
+		if filter.Descending {
+			operator = "$lt"
+		}
+		match = append(match, bson.E{Key: "$and", Value: bson.A{bson.M{"_id": bson.M{operator: filter.AfterID}}}})
+	}
+	return match
+}
+
+func (r *mongoRegistrationRepository) SearchAnswers(ctx context.Context, query string, filter RegistrationFilter, limit int) ([]RegistrationMatch, error) {
+	match := append(registrationMatch(filter), bson.E{Key: "$text", Value: bson.M{"$search": query}})
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, match, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		model.Registration `bson:",inline"`
+		Score              float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	matches := make([]RegistrationMatch, 0, len(docs))
+	for _, doc := range docs {
+		matches = append(matches, RegistrationMatch{Registration: doc.Registration, Score: doc.Score})
+	}
+	return matches, nil
+}
+
+func (r *mongoRegistrationRepository) ListDetails(ctx context.Context, filter RegistrationFilter) ([]model.RegistrationDetail, int64, error) {
+	// Filter pada field pendaftaran dijalankan paling awal agar memakai index koleksi 'registrations'
+	match := registrationMatch(filter)
+
+	base := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"}, {Key: "localField", Value: "user_id"},
+			{Key: "foreignField", Value: "_id"}, {Key: "as", Value: "userDetails"},
+		}}},
+		bson.D{{Key: "$unwind", Value: "$userDetails"}},
+	}
+	if filter.Search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search), Options: "i"}
+		base = append(base, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"userDetails.name": pattern}, bson.M{"userDetails.nim": pattern},
+		}}}})
+	}
+
+	scoreStages := mongo.Pipeline{
+		bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "interview_scores"}, {Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "registration_id"}, {Key: "as", Value: "scores"},
+		}}},
+		// $avg dari array kosong menghasilkan null sehingga pendaftaran yang belum dinilai tidak punya rata-rata
+		bson.D{{Key: "$addFields", Value: bson.D{
+			{Key: "score_average", Value: bson.D{{Key: "$round", Value: bson.A{bson.D{{Key: "$avg", Value: "$scores.total"}}, 2}}}},
+			{Key: "score_count", Value: bson.D{{Key: "$size", Value: "$scores"}}},
+		}}},
+	}
+
+	sortField, ok := registrationSortFields[filter.Sort]
+	if !ok {
+		sortField = "_id"
+	}
+	direction := 1
+	if filter.Descending {
+		direction = -1
+	}
+	sort := bson.D{{Key: sortField, Value: direction}}
+	if sortField != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: 1})
+	}
+
+	pipeline := append(mongo.Pipeline{}, base...)
+	// Nilai wawancara hanya perlu digabung sebelum paginasi jika dipakai untuk mengurutkan
+	if filter.Sort == RegistrationSortScore {
+		pipeline = append(pipeline, scoreStages...)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	if filter.PerPage > 0 {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$skip", Value: int64(filter.Page-1) * int64(filter.PerPage)}},
+			bson.D{{Key: "$limit", Value: filter.PerPage}},
+		)
+	}
+	if filter.Sort != RegistrationSortScore {
+		pipeline = append(pipeline, scoreStages...)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.D{
+		{Key: "_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "period_id", Value: 1}, {Key: "division1", Value: 1},
+		{Key: "division2", Value: 1}, {Key: "motivation", Value: 1}, {Key: "vision_mission", Value: 1},
+		{Key: "interview_schedule", Value: 1}, {Key: "interview_location", Value: 1}, {Key: "interview_slot_id", Value: 1},
+		{Key: "cv_url", Value: 1}, {Key: "certificate_url", Value: 1}, {Key: "optional_certificate_url", Value: 1}, {Key: "formal_photo_url", Value: 1}, {Key: "status", Value: 1},
+		{Key: "placed_division", Value: 1}, {Key: "note", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "name", Value: "$userDetails.name"},
+		{Key: "nim", Value: "$userDetails.nim"}, {Key: "email", Value: "$userDetails.email"},
+		{Key: "phone_number", Value: "$userDetails.phone_number"}, {Key: "score_average", Value: 1}, {Key: "score_count", Value: 1},
+	}}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []model.RegistrationDetail{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.PerPage == 0 {
+		return results, int64(len(results)), nil
+	}
+	total, err := r.count(ctx, base)
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// count menghitung dokumen yang dihasilkan pipeline
+func (r *mongoRegistrationRepository) count(ctx context.Context, pipeline mongo.Pipeline) (int64, error) {
+	pipeline = append(append(mongo.Pipeline{}, pipeline...), bson.D{{Key: "$count", Value: "count"}})
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []struct {
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0].Count, nil
+}
+
+func (r *mongoRegistrationRepository) Update(ctx context.Context, id primitive.ObjectID, update RegistrationUpdate) error {
+	fields := bson.M{"updated_at": primitive.NewDateTimeFromTime(update.UpdatedAt)}
+	if update.Status != nil {
+		fields["status"] = *update.Status
+	}
+	if update.InterviewSchedule != nil {
+		fields["interview_schedule"] = *update.InterviewSchedule
+	}
+	if update.InterviewLocation != nil {
+		fields["interview_location"] = *update.InterviewLocation
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRegistrationRepository) UpdateSubmission(ctx context.Context, registration *model.Registration) error {
+	fields := bson.M{
+		"division1":                registration.Division1,
+		"division2":                registration.Division2,
+		"motivation":               registration.Motivation,
+		"vision_mission":           registration.VisionMission,
+		"cv_url":                   registration.CvUrl,
+		"certificate_url":          registration.CertificateUrl,
+		"optional_certificate_url": registration.OptionalCertificateUrl,
+		"formal_photo_url":         registration.FormalPhotoUrl,
+		"updated_at":               registration.UpdatedAt,
+	}
+
+	// Syarat status ikut di filter agar perubahan status oleh admin di saat bersamaan tidak tertimpa
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": registration.ID, "status": model.RegistrationStatusPending}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRegistrationRepository) SetInterviewSlot(ctx context.Context, id primitive.ObjectID, slotID *primitive.ObjectID, schedule, location string, updatedAt time.Time) error {
+	set := bson.M{
+		"interview_schedule": schedule,
+		"interview_location": location,
+		"updated_at":         primitive.NewDateTimeFromTime(updatedAt),
+	}
+	update := bson.M{"$set": set}
+	if slotID != nil {
+		set["interview_slot_id"] = *slotID
+	} else {
+		update["$unset"] = bson.M{"interview_slot_id": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRegistrationRepository) BulkUpdateStatus(ctx context.Context, ids []primitive.ObjectID, allowedFrom []string, status string, updatedAt time.Time) ([]model.Registration, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"updated_at": primitive.NewDateTimeFromTime(updatedAt),
+		},
+	}
+	// Satu per satu agar status lama yang sebenarnya ikut terbaca; pendaftaran yang statusnya
+	// sudah diubah admin lain di luar allowedFrom tidak tersentuh
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+	var previous []model.Registration
+	for _, id := range ids {
+		filter := bson.M{"_id": id, "status": bson.M{"$in": allowedFrom}}
+		var registration model.Registration
+		err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&registration)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return previous, err
+		}
+		previous = append(previous, registration)
+	}
+	return previous, nil
+}
+
+func (r *mongoRegistrationRepository) SetPlacements(ctx context.Context, periodID primitive.ObjectID, placements map[primitive.ObjectID]string, updatedAt time.Time) error {
+	now := primitive.NewDateTimeFromTime(updatedAt)
+	ids := make([]primitive.ObjectID, 0, len(placements))
+	models := make([]mongo.WriteModel, 0, len(placements)+1)
+	for id, division := range placements {
+		ids = append(ids, id)
+		update := bson.M{"$set": bson.M{"placed_division": division, "updated_at": now}}
+		if division == "" {
+			update = bson.M{"$unset": bson.M{"placed_division": ""}, "$set": bson.M{"updated_at": now}}
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": id, "period_id": periodID}).SetUpdate(update))
+	}
+	models = append(models, mongo.NewUpdateManyModel().
+		SetFilter(bson.M{"period_id": periodID, "_id": bson.M{"$nin": ids}, "placed_division": bson.M{"$exists": true}}).
+		SetUpdate(bson.M{"$unset": bson.M{"placed_division": ""}, "$set": bson.M{"updated_at": now}}))
+
+	_, err := r.collection.BulkWrite(ctx, models)
+	return err
+}
+
+func (r *mongoRegistrationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRegistrationRepository) CountByDivision(ctx context.Context, division string) (int64, error) {
+	filter := bson.M{"$or": bson.A{bson.M{"division1": division}, bson.M{"division2": division}}}
+	return r.collection.CountDocuments(ctx, filter, options.Count().SetCollation(divisionNameCollation))
+}
+
+func (r *mongoRegistrationRepository) RenameDivision(ctx context.Context, oldName, newName string) error {
+	opts := options.Update().SetCollation(divisionNameCollation)
+	for _, field := range []string{"division1", "division2", "placed_division"} {
+		if _, err := r.collection.UpdateMany(ctx, bson.M{field: oldName}, bson.M{"$set": bson.M{field: newName}}, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *mongoRegistrationRepository) CountByPeriod(ctx context.Context, periodID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"period_id": periodID})
+}
+
+// statsTimeZone dipakai untuk mengelompokkan pendaftaran per tanggal kirim
+const statsTimeZone = "Asia/Jakarta"
+
+// statsBucket adalah hasil $group { _id, count }
+type statsBucket struct {
+	ID    string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+func bucketMap(buckets []statsBucket) map[string]int64 {
+	counts := make(map[string]int64, len(buckets))
+	for _, bucket := range buckets {
+		counts[bucket.ID] = bucket.Count
+	}
+	return counts
+}
+
+func (r *mongoRegistrationRepository) Stats(ctx context.Context, filter RegistrationFilter) (*RegistrationStats, error) {
+	match := bson.D{{Key: "$match", Value: registrationMatch(filter)}}
+	countBy := func(field interface{}) mongo.Pipeline {
+		return mongo.Pipeline{bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: field}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}}}
+	}
+	perDay := append(countBy(bson.D{{Key: "$dateToString", Value: bson.D{
+		{Key: "format", Value: "%Y-%m-%d"}, {Key: "date", Value: bson.D{{Key: "$toDate", Value: "$_id"}}}, {Key: "timezone", Value: statsTimeZone},
+	}}}), bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}})
+
+	var counts []struct {
+		ByStatus    []statsBucket `bson:"by_status"`
+		ByDivision1 []statsBucket `bson:"by_division1"`
+		ByDivision2 []statsBucket `bson:"by_division2"`
+		PerDay      []statsBucket `bson:"per_day"`
+	}
+	if err := r.aggregate(ctx, mongo.Pipeline{match, bson.D{{Key: "$facet", Value: bson.D{
+		{Key: "by_status", Value: countBy("$status")},
+		{Key: "by_division1", Value: countBy("$division1")},
+		{Key: "by_division2", Value: countBy("$division2")},
+		{Key: "per_day", Value: perDay},
+	}}}}, &counts); err != nil {
+		return nil, err
+	}
+
+	// Riwayat status diambil per pendaftaran; pasangan event berurutan ($zip dengan dirinya sendiri
+	// yang digeser satu) menghasilkan lama berada pada setiap status
+	var history []struct {
+		Reached   []statsBucket `bson:"reached"`
+		Durations []struct {
+			ID      string  `bson:"_id"`
+			Count   int64   `bson:"count"`
+			Average float64 `bson:"average_ms"`
+		} `bson:"durations"`
+	}
+	if err := r.aggregate(ctx, mongo.Pipeline{
+		match,
+		bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "registration_events"},
+			{Key: "let", Value: bson.D{{Key: "id", Value: "$_id"}}},
+			{Key: "pipeline", Value: mongo.Pipeline{
+				bson.D{{Key: "$match", Value: bson.D{
+					{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$registration_id", "$$id"}}}},
+					{Key: "new_status", Value: bson.D{{Key: "$exists", Value: true}}},
+				}}},
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}}}},
+				bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}, {Key: "new_status", Value: 1}, {Key: "created_at", Value: 1}}}},
+			}},
+			{Key: "as", Value: "events"},
+		}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "statuses", Value: bson.D{{Key: "$setUnion", Value: bson.A{bson.A{"$status"}, "$events.new_status"}}}},
+			{Key: "pairs", Value: bson.D{{Key: "$zip", Value: bson.D{{Key: "inputs", Value: bson.A{
+				"$events",
+				bson.D{{Key: "$slice", Value: bson.A{"$events", 1, bson.D{{Key: "$max", Value: bson.A{bson.D{{Key: "$size", Value: "$events"}}, 1}}}}}},
+			}}}}}},
+		}}},
+		bson.D{{Key: "$facet", Value: bson.D{
+			{Key: "reached", Value: append(mongo.Pipeline{bson.D{{Key: "$unwind", Value: "$statuses"}}}, countBy("$statuses")...)},
+			{Key: "durations", Value: mongo.Pipeline{
+				bson.D{{Key: "$unwind", Value: "$pairs"}},
+				bson.D{{Key: "$project", Value: bson.D{
+					{Key: "status", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$pairs.new_status", 0}}}},
+					{Key: "ms", Value: bson.D{{Key: "$subtract", Value: bson.A{
+						bson.D{{Key: "$arrayElemAt", Value: bson.A{"$pairs.created_at", 1}}},
+						bson.D{{Key: "$arrayElemAt", Value: bson.A{"$pairs.created_at", 0}}},
+					}}}},
+				}}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: "$status"},
+					{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+					{Key: "average_ms", Value: bson.D{{Key: "$avg", Value: "$ms"}}},
+				}}},
+			}},
+		}}},
+	}, &history); err != nil {
+		return nil, err
+	}
+
+	stats := &RegistrationStats{Durations: map[string]StatusDuration{}}
+	if len(counts) > 0 {
+		stats.ByStatus = bucketMap(counts[0].ByStatus)
+		stats.ByDivision1 = bucketMap(counts[0].ByDivision1)
+		stats.ByDivision2 = bucketMap(counts[0].ByDivision2)
+		for _, bucket := range counts[0].PerDay {
+			stats.PerDay = append(stats.PerDay, DailyCount{Date: bucket.ID, Count: bucket.Count})
+		}
+	}
+	for _, count := range stats.ByStatus {
+		stats.Total += count
+	}
+	if len(history) > 0 {
+		stats.Reached = bucketMap(history[0].Reached)
+		for _, duration := range history[0].Durations {
+			stats.Durations[duration.ID] = StatusDuration{Count: duration.Count, Average: time.Duration(duration.Average * float64(time.Millisecond))}
+		}
+	}
+	return stats, nil
+}
+
+// aggregate menjalankan pipeline dan mendekode seluruh hasilnya ke results
+func (r *mongoRegistrationRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
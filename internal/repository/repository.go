@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrNotFound dikembalikan ketika dokumen yang dicari tidak ada
+var ErrNotFound = errors.New("document not found")
+
+// ErrSlotFull dikembalikan ketika slot wawancara sudah mencapai kapasitasnya
+var ErrSlotFull = errors.New("interview slot is full")
+
+// ErrSlotInUse dikembalikan ketika kapasitas slot diubah menjadi lebih kecil dari jumlah pemesannya
+var ErrSlotInUse = errors.New("interview slot has more bookings than the new capacity")
+
+// ErrDuplicate dikembalikan ketika dokumen baru melanggar index unik
+var ErrDuplicate = errors.New("duplicate document")
+
+// UserRepository mengelola data pada koleksi 'users'
+type UserRepository interface {
+	// Create menyimpan user baru. ErrDuplicate dikembalikan jika NIM sudah dipakai.
+	Create(ctx context.Context, user *model.User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error)
+	FindByNIM(ctx context.Context, nim string) (*model.User, error)
+	// ExistsByNIM mengecek apakah NIM sudah dipakai user lain selain excludeID
+	ExistsByNIM(ctx context.Context, nim string, excludeID primitive.ObjectID) (bool, error)
+	// List mengembalikan semua user tanpa field password
+	List(ctx context.Context) ([]model.User, error)
+	// Update memperbarui data profil dan role user (tanpa password). ErrDuplicate dikembalikan jika
+	// NIM baru sudah dipakai user lain.
+	Update(ctx context.Context, user *model.User) error
+	// UpdatePassword mengganti password dan menaikkan token version sehingga semua sesi lama berakhir
+	UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error
+	// SetDisabled menonaktifkan/mengaktifkan akun dan menaikkan token version
+	SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) error
+	// SetEmailVerified menandai email user sudah diverifikasi; verifiedAt nil menghapus tanda tersebut
+	SetEmailVerified(ctx context.Context, id primitive.ObjectID, verifiedAt *time.Time) error
+	// Search mencari pendaftar (role user) berdasarkan kata utuh lewat text index maupun potongan
+	// nama, NIM, email, atau nomor telepon, diurutkan dari yang paling relevan. Password tidak disertakan.
+	// Jika scope tidak nil, hanya user yang memiliki pendaftaran sesuai scope yang dihitung dalam limit.
+	Search(ctx context.Context, query string, scope *RegistrationFilter, limit int) ([]UserMatch, error)
+	// CountByRole menghitung user dengan role tertentu
+	CountByRole(ctx context.Context, role string) (int64, error)
+	// IncrementTokenVersion membuat semua token user yang sudah terbit tidak berlaku lagi
+	IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// RegistrationUpdate berisi field pendaftaran yang akan diperbarui, field nil tidak diubah
+type RegistrationUpdate struct {
+	Status            *string
+	InterviewSchedule *string
+	InterviewLocation *string
+	UpdatedAt         time.Time
+}
+
+// RegistrationFilter membatasi daftar pendaftaran, field kosong berarti tidak difilter
+type RegistrationFilter struct {
+	PeriodID primitive.ObjectID
+	// IDs dan UserIDs membatasi hasil ke pendaftaran atau user tertentu
+	IDs     []primitive.ObjectID
+	UserIDs []primitive.ObjectID
+	// Statuses berisi status yang disertakan
+	Statuses []string
+	// Division cocok dengan pilihan divisi 1 maupun 2
+	Division string
+	// Search mencari nama atau NIM pendaftar (tidak membedakan huruf besar/kecil)
+	Search string
+	// SubmittedFrom dan SubmittedBefore membatasi waktu kirim formulir (waktu pada ID pendaftaran)
+	SubmittedFrom   time.Time
+	SubmittedBefore time.Time
+	// Sort adalah urutan hasil (lihat RegistrationSort...), kosong berarti RegistrationSortSubmitted
+	Sort       string
+	Descending bool
+	// AfterID melanjutkan hasil setelah pendaftaran ini (paginasi keyset untuk RegistrationSortSubmitted):
+	// hanya ID yang lebih besar, atau lebih kecil jika Descending. Tidak terpengaruh data yang
+	// ditambah atau dihapus di antara halaman, tidak seperti Page.
+	AfterID primitive.ObjectID
+	// Page dimulai dari 1. PerPage 0 berarti semua hasil dikembalikan tanpa halaman.
+	Page    int
+	PerPage int
+}
+
+// Urutan yang didukung ListDetails. Urutan yang sama diurutkan lagi berdasarkan ID.
+const (
+	RegistrationSortSubmitted = "submitted"
+	RegistrationSortUpdated   = "updated_at"
+	RegistrationSortName      = "name"
+	RegistrationSortNIM       = "nim"
+	RegistrationSortStatus    = "status"
+	// RegistrationSortScore mengurutkan berdasarkan rata-rata nilai wawancara; pendaftaran yang belum
+	// dinilai berada di akhir saat urutan menurun dan di awal saat urutan menaik
+	RegistrationSortScore = "score"
+)
+
+// RegistrationSorts adalah semua nilai RegistrationFilter.Sort yang didukung
+var RegistrationSorts = []string{
+	RegistrationSortSubmitted, RegistrationSortUpdated, RegistrationSortName,
+	RegistrationSortNIM, RegistrationSortStatus, RegistrationSortScore,
+}
+
+// RegistrationMatch adalah pendaftaran hasil pencarian beserta skor relevansinya
+type RegistrationMatch struct {
+	Registration model.Registration
+	Score        float64
+}
+
+// UserMatch adalah user hasil pencarian beserta skor relevansinya
+type UserMatch struct {
+	User  model.User
+	Score float64
+}
+
+// RegistrationRepository mengelola data pada koleksi 'registrations'
+type RegistrationRepository interface {
+	// Create menyimpan pendaftaran baru. ErrDuplicate dikembalikan jika user sudah mendaftar pada periode yang sama.
+	Create(ctx context.Context, registration *model.Registration) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Registration, error)
+	// FindByIDs mengembalikan pendaftaran dengan ID yang ada; ID yang tidak ditemukan dilewati
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]model.Registration, error)
+	// FindByUserID mengembalikan pendaftaran terbaru milik user
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) (*model.Registration, error)
+	FindByUserAndPeriod(ctx context.Context, userID, periodID primitive.ObjectID) (*model.Registration, error)
+	// ListByUser mengembalikan semua pendaftaran milik user dari semua periode, diurutkan dari yang terlama
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Registration, error)
+	// SearchAnswers mencari kata pada motivasi dan visi misi memakai text index, diurutkan dari yang
+	// paling relevan. Hanya PeriodID, IDs, UserIDs, Statuses, Division, dan rentang waktu kirim pada filter yang dipakai.
+	SearchAnswers(ctx context.Context, query string, filter RegistrationFilter, limit int) ([]RegistrationMatch, error)
+	// ListDetails mengembalikan satu halaman pendaftaran sesuai filter yang digabung dengan data user-nya
+	// dan ringkasan nilai wawancaranya, beserta jumlah seluruh pendaftaran yang cocok
+	ListDetails(ctx context.Context, filter RegistrationFilter) ([]model.RegistrationDetail, int64, error)
+	Update(ctx context.Context, id primitive.ObjectID, update RegistrationUpdate) error
+	// UpdateSubmission menyimpan jawaban dan URL berkas dari registration selama statusnya masih pending.
+	// ErrNotFound dikembalikan jika pendaftaran tidak ada atau sudah diproses.
+	UpdateSubmission(ctx context.Context, registration *model.Registration) error
+	// SetInterviewSlot mengisi slot wawancara pendaftaran beserta teks jadwal dan lokasinya.
+	// slotID nil mengosongkan slot, jadwal, dan lokasi.
+	SetInterviewSlot(ctx context.Context, id primitive.ObjectID, slotID *primitive.ObjectID, schedule, location string, updatedAt time.Time) error
+	// BulkUpdateStatus mengubah status beberapa pendaftaran sekaligus, hanya yang statusnya masih salah satu
+	// dari allowedFrom. Data sebelum diubah dikembalikan untuk setiap pendaftaran yang benar-benar berubah.
+	BulkUpdateStatus(ctx context.Context, ids []primitive.ObjectID, allowedFrom []string, status string, updatedAt time.Time) ([]model.Registration, error)
+	// SetPlacements menyimpan divisi penempatan (kosong untuk menghapus) pendaftaran pada periode tersebut.
+	// Pendaftaran lain pada periode yang sama yang masih memiliki divisi penempatan dikosongkan.
+	SetPlacements(ctx context.Context, periodID primitive.ObjectID, placements map[primitive.ObjectID]string, updatedAt time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// CountByDivision menghitung pendaftaran yang memilih divisi tersebut sebagai pilihan 1 atau 2
+	CountByDivision(ctx context.Context, division string) (int64, error)
+	// RenameDivision mengganti nama divisi pada semua pendaftaran yang memilihnya
+	RenameDivision(ctx context.Context, oldName, newName string) error
+	// CountByPeriod menghitung pendaftaran pada periode tertentu
+	CountByPeriod(ctx context.Context, periodID primitive.ObjectID) (int64, error)
+	// Stats merangkum pendaftaran yang cocok dengan filter beserta riwayat statusnya untuk dasbor admin.
+	// Hanya field filter yang juga dipakai SearchAnswers yang berlaku.
+	Stats(ctx context.Context, filter RegistrationFilter) (*RegistrationStats, error)
+}
+
+// RegistrationStats adalah ringkasan jumlah pendaftaran untuk dasbor admin
+type RegistrationStats struct {
+	Total       int64
+	ByStatus    map[string]int64
+	ByDivision1 map[string]int64
+	ByDivision2 map[string]int64
+	// PerDay berisi jumlah pendaftaran per tanggal kirim (YYYY-MM-DD dalam WIB), urut dari yang terlama
+	PerDay []DailyCount
+	// Reached berisi jumlah pendaftaran yang pernah berstatus tertentu, menurut riwayat maupun status saat ini
+	Reached map[string]int64
+	// Durations berisi lama rata-rata pendaftaran berada pada suatu status sebelum berpindah ke status lain
+	Durations map[string]StatusDuration
+}
+
+// DailyCount adalah jumlah pendaftaran pada satu tanggal
+type DailyCount struct {
+	Date  string
+	Count int64
+}
+
+// StatusDuration adalah rata-rata lama berada pada satu status dari Count kali perpindahan
+type StatusDuration struct {
+	Count   int64
+	Average time.Duration
+}
+
+// RegistrationEventRepository mencatat riwayat pendaftaran pada koleksi 'registration_events' (append-only)
+type RegistrationEventRepository interface {
+	Create(ctx context.Context, event *model.RegistrationEvent) error
+	CreateMany(ctx context.Context, events []model.RegistrationEvent) error
+	// ListByRegistration mengembalikan riwayat satu pendaftaran, diurutkan dari yang terlama
+	ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.RegistrationEvent, error)
+	// DeleteByRegistration menghapus riwayat pendaftaran, hanya dipakai saat pendaftarannya ikut dihapus
+	DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error
+}
+
+// RegistrationNoteRepository mengelola catatan pendaftaran pada koleksi 'registration_notes'
+type RegistrationNoteRepository interface {
+	Create(ctx context.Context, note *model.RegistrationNote) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.RegistrationNote, error)
+	// ListByRegistration mengembalikan semua catatan satu pendaftaran, diurutkan dari yang terlama
+	ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.RegistrationNote, error)
+	// Update memperbarui isi dan visibilitas catatan
+	Update(ctx context.Context, note *model.RegistrationNote) error
+	// SetThreadVisibility mengubah visibilitas catatan utama beserta semua balasannya
+	SetThreadVisibility(ctx context.Context, rootID primitive.ObjectID, visibility string) error
+	// Delete menghapus catatan beserta semua balasannya
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error
+}
+
+// InterviewSlotFilter membatasi daftar slot wawancara, field kosong berarti tidak difilter
+type InterviewSlotFilter struct {
+	PeriodID      primitive.ObjectID
+	StartsAfter   time.Time
+	AvailableOnly bool // hanya slot yang masih memiliki kursi
+}
+
+// InterviewSlotRepository mengelola slot wawancara pada koleksi 'interview_slots'
+type InterviewSlotRepository interface {
+	CreateMany(ctx context.Context, slots []model.InterviewSlot) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.InterviewSlot, error)
+	// List mengembalikan slot sesuai filter, diurutkan dari waktu mulai paling awal
+	List(ctx context.Context, filter InterviewSlotFilter) ([]model.InterviewSlot, error)
+	// FindInterviewerConflicts mengembalikan slot lain yang beririsan dengan rentang waktu tersebut
+	// dan memakai salah satu pewawancara yang sama
+	FindInterviewerConflicts(ctx context.Context, interviewers []primitive.ObjectID, startsAt, endsAt time.Time, excludeID primitive.ObjectID) ([]model.InterviewSlot, error)
+	// Update memperbarui waktu, tempat, pewawancara, dan kapasitas slot (tanpa daftar pemesan).
+	// ErrSlotInUse dikembalikan jika kapasitas baru lebih kecil dari jumlah pemesan saat update berjalan.
+	Update(ctx context.Context, slot *model.InterviewSlot) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// Book memasukkan pendaftaran ke slot secara atomik: syarat booked < capacity dan penambahan booked
+	// dijalankan dalam satu update. ErrSlotFull dikembalikan jika slot sudah penuh; memesan slot yang sama
+	// dua kali tidak dianggap error.
+	Book(ctx context.Context, slotID, registrationID primitive.ObjectID, updatedAt time.Time) error
+	// Release mengeluarkan pendaftaran dari slot tersebut
+	Release(ctx context.Context, slotID, registrationID primitive.ObjectID, updatedAt time.Time) error
+}
+
+// InterviewScoreRepository mengelola penilaian wawancara pada koleksi 'interview_scores'
+type InterviewScoreRepository interface {
+	// Upsert menyimpan penilaian, menggantikan penilaian sebelumnya dari pewawancara yang sama
+	// untuk pendaftaran dan divisi yang sama. ID dan CreatedAt score diisi dari data yang tersimpan.
+	Upsert(ctx context.Context, score *model.InterviewScore) error
+	// ListByRegistration mengembalikan penilaian satu pendaftaran, diurutkan dari yang terlama
+	ListByRegistration(ctx context.Context, registrationID primitive.ObjectID) ([]model.InterviewScore, error)
+	// ListByRegistrations mengembalikan penilaian beberapa pendaftaran sekaligus
+	ListByRegistrations(ctx context.Context, registrationIDs []primitive.ObjectID) ([]model.InterviewScore, error)
+	DeleteByRegistration(ctx context.Context, registrationID primitive.ObjectID) error
+}
+
+// InformationRepository mengelola data pada koleksi 'informations'
+type InformationRepository interface {
+	Create(ctx context.Context, info *model.Information) error
+	// List mengembalikan semua informasi, diurutkan dari yang terbaru
+	List(ctx context.Context) ([]model.Information, error)
+	Update(ctx context.Context, id primitive.ObjectID, title, content string, updatedAt time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// DivisionRepository mengelola katalog divisi pada koleksi 'divisions'
+type DivisionRepository interface {
+	Create(ctx context.Context, division *model.Division) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Division, error)
+	// FindByName mencari divisi berdasarkan nama tanpa membedakan huruf besar/kecil
+	FindByName(ctx context.Context, name string) (*model.Division, error)
+	// List mengembalikan divisi diurutkan berdasarkan nama; activeOnly hanya menyertakan divisi aktif
+	List(ctx context.Context, activeOnly bool) ([]model.Division, error)
+	// Update memperbarui data divisi tanpa mengubah rubriknya
+	Update(ctx context.Context, division *model.Division) error
+	// SetRubric mengganti rubrik penilaian wawancara divisi
+	SetRubric(ctx context.Context, id primitive.ObjectID, rubric *model.InterviewRubric, updatedAt time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// RecruitmentPeriodRepository mengelola periode rekrutmen pada koleksi 'recruitment_periods'
+type RecruitmentPeriodRepository interface {
+	Create(ctx context.Context, period *model.RecruitmentPeriod) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.RecruitmentPeriod, error)
+	// FindActive mengembalikan periode berstatus active, ErrNotFound jika tidak ada
+	FindActive(ctx context.Context) (*model.RecruitmentPeriod, error)
+	// List mengembalikan semua periode, diurutkan dari waktu buka terbaru
+	List(ctx context.Context) ([]model.RecruitmentPeriod, error)
+	Update(ctx context.Context, period *model.RecruitmentPeriod) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// TokenRepository mengelola refresh token dan daftar access token yang sudah dicabut
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	// FindRefreshToken mencari refresh token berdasarkan hash-nya, termasuk yang sudah dicabut
+	FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	// RevokeRefreshToken mencabut refresh token yang masih aktif. ErrNotFound dikembalikan
+	// jika token sudah dicabut sebelumnya, sehingga rotasi ganda bisa dideteksi.
+	RevokeRefreshToken(ctx context.Context, id, replacedBy primitive.ObjectID, revokedAt time.Time) error
+	// RevokeUserRefreshTokens mencabut semua refresh token aktif milik user
+	RevokeUserRefreshTokens(ctx context.Context, userID primitive.ObjectID, revokedAt time.Time) error
+	// DeleteUserRefreshTokens menghapus semua refresh token milik user, termasuk yang sudah dicabut
+	DeleteUserRefreshTokens(ctx context.Context, userID primitive.ObjectID) error
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// PasswordResetRepository mengelola token reset password pada koleksi 'password_resets'
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *model.PasswordReset) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	// MarkUsed menandai token sudah dipakai. ErrNotFound dikembalikan jika token sudah pernah dipakai.
+	MarkUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// LoginAttemptRepository mencatat percobaan login yang gagal per NIM dan per IP
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*model.LoginAttempt, error)
+	// Save menyimpan (upsert) data percobaan login berdasarkan key-nya
+	Save(ctx context.Context, attempt *model.LoginAttempt) error
+	Delete(ctx context.Context, key string) error
+}
+
+// RateLimitRepository menyimpan token bucket untuk rate limiting
+type RateLimitRepository interface {
+	// Take mengambil satu token dari bucket milik key. Bucket berisi maksimal capacity token
+	// dan terisi ulang refillPerSecond token per detik. Jika token habis, retryAfter berisi
+	// waktu tunggu sampai token berikutnya tersedia.
+	Take(ctx context.Context, key string, capacity int, refillPerSecond float64, now time.Time) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// Repositories mengumpulkan semua repository yang dibutuhkan handler
+type Repositories struct {
+	Users          UserRepository
+	Registrations  RegistrationRepository
+	Events         RegistrationEventRepository
+	Notes          RegistrationNoteRepository
+	InterviewSlots InterviewSlotRepository
+	Scores         InterviewScoreRepository
+	Informations   InformationRepository
+	Divisions      DivisionRepository
+	Periods        RecruitmentPeriodRepository
+	Tokens         TokenRepository
+	PasswordResets PasswordResetRepository
+	LoginAttempts  LoginAttemptRepository
+}
+
+// NewMongoRepositories membuat semua repository yang tersimpan di MongoDB
+func NewMongoRepositories(db *mongo.Database) *Repositories {
+	return &Repositories{
+		Users:          NewMongoUserRepository(db),
+		Registrations:  NewMongoRegistrationRepository(db),
+		Events:         NewMongoRegistrationEventRepository(db),
+		Notes:          NewMongoRegistrationNoteRepository(db),
+		InterviewSlots: NewMongoInterviewSlotRepository(db),
+		Scores:         NewMongoInterviewScoreRepository(db),
+		Informations:   NewMongoInformationRepository(db),
+		Divisions:      NewMongoDivisionRepository(db),
+		Periods:        NewMongoRecruitmentPeriodRepository(db),
+		Tokens:         NewMongoTokenRepository(db),
+		PasswordResets: NewMongoPasswordResetRepository(db),
+		LoginAttempts:  NewMongoLoginAttemptRepository(db),
+	}
+}
+
+// NewMemoryRepositories membuat semua repository in-memory, berguna untuk pengujian tanpa MongoDB
+func NewMemoryRepositories() *Repositories {
+	users := NewMemoryUserRepository()
+	scores := NewMemoryInterviewScoreRepository()
+	events := NewMemoryRegistrationEventRepository()
+	registrations := NewMemoryRegistrationRepository(users, scores, events)
+	users.registrations = registrations
+	return &Repositories{
+		Users:          users,
+		Registrations:  registrations,
+		Events:         events,
+		Notes:          NewMemoryRegistrationNoteRepository(),
+		InterviewSlots: NewMemoryInterviewSlotRepository(),
+		Scores:         scores,
+		Informations:   NewMemoryInformationRepository(),
+		Divisions:      NewMemoryDivisionRepository(),
+		Periods:        NewMemoryRecruitmentPeriodRepository(),
+		Tokens:         NewMemoryTokenRepository(),
+		PasswordResets: NewMemoryPasswordResetRepository(),
+		LoginAttempts:  NewMemoryLoginAttemptRepository(),
+	}
+}
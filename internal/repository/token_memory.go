@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryTokenRepository adalah TokenRepository in-memory untuk pengujian
+type MemoryTokenRepository struct {
+	mu            sync.RWMutex
+	refreshTokens []model.RefreshToken
+	revokedTokens map[string]time.Time
+}
+
+// NewMemoryTokenRepository membuat MemoryTokenRepository kosong
+func NewMemoryTokenRepository() *MemoryTokenRepository {
+	return &MemoryTokenRepository{revokedTokens: make(map[string]time.Time)}
+}
+
+func (r *MemoryTokenRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	r.refreshTokens = append(r.refreshTokens, *token)
+	return nil
+}
+
+func (r *MemoryTokenRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.refreshTokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryTokenRepository) RevokeRefreshToken(ctx context.Context, id, replacedBy primitive.ObjectID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.refreshTokens {
+		token := &r.refreshTokens[i]
+		if token.ID != id || token.RevokedAt != nil {
+			continue
+		}
+		at := primitive.NewDateTimeFromTime(revokedAt)
+		token.RevokedAt = &at
+		token.ReplacedBy = replacedBy
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID primitive.ObjectID, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	at := primitive.NewDateTimeFromTime(revokedAt)
+	for i := range r.refreshTokens {
+		if r.refreshTokens[i].UserID == userID && r.refreshTokens[i].RevokedAt == nil {
+			r.refreshTokens[i].RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *MemoryTokenRepository) DeleteUserRefreshTokens(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.refreshTokens[:0]
+	for _, token := range r.refreshTokens {
+		if token.UserID != userID {
+			kept = append(kept, token)
+		}
+	}
+	r.refreshTokens = kept
+	return nil
+}
+
+func (r *MemoryTokenRepository) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokedTokens[tokenID] = expiresAt
+	return nil
+}
+
+func (r *MemoryTokenRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.revokedTokens[tokenID]
+	return ok, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoTokenRepository struct {
+	refreshTokens *mongo.Collection
+	revokedTokens *mongo.Collection
+}
+
+// NewMongoTokenRepository membuat TokenRepository yang memakai koleksi 'refresh_tokens' dan 'revoked_tokens'
+func NewMongoTokenRepository(db *mongo.Database) TokenRepository {
+	return &mongoTokenRepository{
+		refreshTokens: db.Collection("refresh_tokens"),
+		revokedTokens: db.Collection("revoked_tokens"),
+	}
+}
+
+func (r *mongoTokenRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	_, err := r.refreshTokens.InsertOne(ctx, token)
+	return err
+}
+
+func (r *mongoTokenRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.refreshTokens.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *mongoTokenRepository) RevokeRefreshToken(ctx context.Context, id, replacedBy primitive.ObjectID, revokedAt time.Time) error {
+	fields := bson.M{"revoked_at": primitive.NewDateTimeFromTime(revokedAt)}
+	if !replacedBy.IsZero() {
+		fields["replaced_by"] = replacedBy
+	}
+
+	result, err := r.refreshTokens.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": nil},
+		bson.M{"$set": fields},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID primitive.ObjectID, revokedAt time.Time) error {
+	_, err := r.refreshTokens.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": primitive.NewDateTimeFromTime(revokedAt)}},
+	)
+	return err
+}
+
+func (r *mongoTokenRepository) DeleteUserRefreshTokens(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.refreshTokens.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *mongoTokenRepository) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	revoked := model.RevokedToken{
+		TokenID:   tokenID,
+		ExpiresAt: primitive.NewDateTimeFromTime(expiresAt),
+	}
+	_, err := r.revokedTokens.ReplaceOne(ctx, bson.M{"_id": tokenID}, revoked, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *mongoTokenRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	count, err := r.revokedTokens.CountDocuments(ctx, bson.M{"_id": tokenID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
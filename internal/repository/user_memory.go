@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryUserRepository adalah UserRepository in-memory untuk pengujian
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users []model.User
+	// registrations dipakai Search untuk membatasi user ke cakupan pendaftaran, diisi NewMemoryRepositories
+	registrations *MemoryRegistrationRepository
+}
+
+// NewMemoryUserRepository membuat MemoryUserRepository kosong
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sama seperti index unik nim pada MongoDB
+	if r.nimTaken(user.NIM, primitive.NilObjectID) {
+		return ErrDuplicate
+	}
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.users = append(r.users, *user)
+	return nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.ID == id })
+}
+
+func (r *MemoryUserRepository) FindByNIM(ctx context.Context, nim string) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.NIM == nim })
+}
+
+func (r *MemoryUserRepository) find(match func(*model.User) bool) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := range r.users {
+		if match(&r.users[i]) {
+			user := r.users[i]
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) ExistsByNIM(ctx context.Context, nim string, excludeID primitive.ObjectID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.nimTaken(nim, excludeID), nil
+}
+
+func (r *MemoryUserRepository) List(ctx context.Context) ([]model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]model.User, len(r.users))
+	copy(users, r.users)
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, nil
+}
+
+func (r *MemoryUserRepository) Search(ctx context.Context, query string, scope *RegistrationFilter, limit int) ([]UserMatch, error) {
+	// Diambil sebelum mengunci users karena repository pendaftaran juga membaca users
+	var scoped map[primitive.ObjectID]bool
+	if scope != nil {
+		scoped = map[primitive.ObjectID]bool{}
+		if r.registrations != nil {
+			scoped = r.registrations.userIDs(*scope)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := textTerms(query)
+	partial := strings.ToLower(query)
+	matches := []UserMatch{}
+	for _, user := range r.users {
+		if user.Role != "user" || (scoped != nil && !scoped[user.ID]) {
+			continue
+		}
+		// Bobot mengikuti text index users_search
+		score := 5*textScore(terms, user.Name, user.NIM) + 2*textScore(terms, user.Email) + textScore(terms, user.PhoneNumber)
+		for _, field := range []string{user.Name, user.NIM, user.Email, user.PhoneNumber} {
+			if strings.Contains(strings.ToLower(field), partial) {
+				score += partialMatchScore
+				break
+			}
+		}
+		if score > 0 {
+			user.Password = ""
+			matches = append(matches, UserMatch{User: user, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (r *MemoryUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, user := range r.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *model.User) error {
+	r.mu.RLock()
+	taken := r.nimTaken(user.NIM, user.ID)
+	r.mu.RUnlock()
+	if taken {
+		return ErrDuplicate
+	}
+	return r.update(user.ID, func(u *model.User) {
+		u.Name = user.Name
+		u.NIM = user.NIM
+		u.BirthPlace = user.BirthPlace
+		u.BirthDate = user.BirthDate
+		u.Email = user.Email
+		u.PhoneNumber = user.PhoneNumber
+		u.Role = user.Role
+	})
+}
+
+func (r *MemoryUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error {
+	return r.update(id, func(u *model.User) {
+		u.Password = hashedPassword
+		u.TokenVersion++
+	})
+}
+
+func (r *MemoryUserRepository) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) error {
+	return r.update(id, func(u *model.User) {
+		u.Disabled = disabled
+		u.TokenVersion++
+	})
+}
+
+func (r *MemoryUserRepository) SetEmailVerified(ctx context.Context, id primitive.ObjectID, verifiedAt *time.Time) error {
+	return r.update(id, func(u *model.User) {
+		u.EmailVerifiedAt = nil
+		if verifiedAt != nil {
+			at := primitive.NewDateTimeFromTime(*verifiedAt)
+			u.EmailVerifiedAt = &at
+		}
+	})
+}
+
+func (r *MemoryUserRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	return r.update(id, func(u *model.User) {
+		u.TokenVersion++
+	})
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.users {
+		if r.users[i].ID == id {
+			r.users = append(r.users[:i], r.users[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryUserRepository) update(id primitive.ObjectID, apply func(*model.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.users {
+		if r.users[i].ID == id {
+			apply(&r.users[i])
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// nimTaken mengecek apakah NIM dipakai user selain excludeID; pemanggil harus memegang r.mu
+func (r *MemoryUserRepository) nimTaken(nim string, excludeID primitive.ObjectID) bool {
+	for _, u := range r.users {
+		if u.NIM == nim && u.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
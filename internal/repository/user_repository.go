@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoUserRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoUserRepository membuat UserRepository yang memakai koleksi 'users'
+func NewMongoUserRepository(db *mongo.Database) UserRepository {
+	return &mongoUserRepository{collection: db.Collection("users")}
+}
+
+func (r *mongoUserRepository) Create(ctx context.Context, user *model.User) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	_, err := r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *mongoUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoUserRepository) FindByNIM(ctx context.Context, nim string) (*model.User, error) {
+	return r.findOne(ctx, bson.M{"nim": nim})
+}
+
+func (r *mongoUserRepository) findOne(ctx context.Context, filter bson.M) (*model.User, error) {
+	var user model.User
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mongoUserRepository) ExistsByNIM(ctx context.Context, nim string, excludeID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"nim": nim}
+	if !excludeID.IsZero() {
+		filter["_id"] = bson.M{"$ne": excludeID}
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *mongoUserRepository) List(ctx context.Context) ([]model.User, error) {
+	// Opsi untuk tidak menyertakan field password demi keamanan
+	opts := options.Find().SetProjection(bson.M{"password": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []model.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// partialMatchScore adalah skor tambahan untuk user yang cocok sebagian (regex), setara satu kata pada text index
+const partialMatchScore = 1.0
+
+func (r *mongoUserRepository) Search(ctx context.Context, query string, scope *RegistrationFilter, limit int) ([]UserMatch, error) {
+	// $text hanya mencocokkan kata utuh, jadi potongan NIM atau nomor telepon dicari terpisah dengan regex
+	textMatches, err := r.search(ctx, bson.M{"role": "user", "$text": bson.M{"$search": query}}, scope, true, limit)
+	if err != nil {
+		return nil, err
+	}
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+	partialMatches, err := r.search(ctx, bson.M{"role": "user", "$or": bson.A{
+		bson.M{"name": pattern}, bson.M{"nim": pattern}, bson.M{"email": pattern}, bson.M{"phone_number": pattern},
+	}}, scope, false, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := textMatches
+	for _, partial := range partialMatches {
+		found := false
+		for i := range matches {
+			if matches[i].User.ID == partial.User.ID {
+				matches[i].Score += partialMatchScore
+				found = true
+				break
+			}
+		}
+		if !found {
+			matches = append(matches, partial)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (r *mongoUserRepository) search(ctx context.Context, filter bson.M, scope *RegistrationFilter, textScore bool, limit int) ([]UserMatch, error) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if textScore {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	if scope != nil {
+		// User tanpa pendaftaran dalam cakupan dibuang sebelum $limit agar hasil tidak berkurang setelahnya
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from": "registrations",
+				"let":  bson.M{"user_id": "$_id"},
+				"pipeline": mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$user_id", "$$user_id"}}}}},
+					{{Key: "$match", Value: registrationMatch(*scope)}},
+					{{Key: "$limit", Value: 1}},
+					{{Key: "$project", Value: bson.M{"_id": 1}}},
+				},
+				"as": "scoped_registrations",
+			}}},
+			bson.D{{Key: "$match", Value: bson.M{"scoped_registrations.0": bson.M{"$exists": true}}}},
+		)
+	}
+	if textScore {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$limit", Value: limit}},
+		bson.D{{Key: "$project", Value: bson.M{"password": 0, "scoped_registrations": 0}}},
+	)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		model.User `bson:",inline"`
+		Score      float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	matches := make([]UserMatch, 0, len(docs))
+	for _, doc := range docs {
+		if !textScore {
+			doc.Score = partialMatchScore
+		}
+		matches = append(matches, UserMatch{User: doc.User, Score: doc.Score})
+	}
+	return matches, nil
+}
+
+func (r *mongoUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"role": role})
+}
+
+func (r *mongoUserRepository) Update(ctx context.Context, user *model.User) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":         user.Name,
+			"nim":          user.NIM,
+			"birth_place":  user.BirthPlace,
+			"birth_date":   user.BirthDate,
+			"email":        user.Email,
+			"phone_number": user.PhoneNumber,
+			"role":         user.Role,
+		},
+	}
+	return r.updateOne(ctx, user.ID, update)
+}
+
+func (r *mongoUserRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, hashedPassword string) error {
+	return r.updateOne(ctx, id, bson.M{
+		"$set": bson.M{"password": hashedPassword},
+		"$inc": bson.M{"token_version": 1},
+	})
+}
+
+func (r *mongoUserRepository) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool) error {
+	return r.updateOne(ctx, id, bson.M{
+		"$set": bson.M{"disabled": disabled},
+		"$inc": bson.M{"token_version": 1},
+	})
+}
+
+func (r *mongoUserRepository) SetEmailVerified(ctx context.Context, id primitive.ObjectID, verifiedAt *time.Time) error {
+	if verifiedAt == nil {
+		return r.updateOne(ctx, id, bson.M{"$unset": bson.M{"email_verified_at": ""}})
+	}
+	return r.updateOne(ctx, id, bson.M{"$set": bson.M{"email_verified_at": primitive.NewDateTimeFromTime(*verifiedAt)}})
+}
+
+func (r *mongoUserRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	return r.updateOne(ctx, id, bson.M{"$inc": bson.M{"token_version": 1}})
+}
+
+func (r *mongoUserRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) updateOne(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package response
+
+// Code adalah kode error yang stabil dan bisa dipakai frontend untuk lokalisasi dan percabangan.
+// Jangan mengubah nilai kode yang sudah ada; tambahkan kode baru jika perlu.
+type Code string
+
+// Umum
+const (
+	CodeInvalidRequestBody Code = "INVALID_REQUEST_BODY"
+	CodeValidationFailed   Code = "VALIDATION_FAILED"
+	CodeInvalidID          Code = "INVALID_ID"
+	CodeRouteNotFound      Code = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed   Code = "METHOD_NOT_ALLOWED"
+	CodeRateLimited        Code = "RATE_LIMITED"
+	CodeInternalError      Code = "INTERNAL_ERROR"
+	CodeUploadFailed       Code = "UPLOAD_FAILED"
+	CodeNotificationFailed Code = "NOTIFICATION_FAILED"
+	CodePayloadTooLarge    Code = "PAYLOAD_TOO_LARGE"
+)
+
+// Otentikasi dan otorisasi
+const (
+	CodeAuthHeaderMissing    Code = "AUTH_HEADER_MISSING"
+	CodeAuthHeaderInvalid    Code = "AUTH_HEADER_INVALID"
+	CodeTokenInvalid         Code = "TOKEN_INVALID"
+	CodeTokenRevoked         Code = "TOKEN_REVOKED"
+	CodeSessionInvalidated   Code = "SESSION_INVALIDATED"
+	CodeAdminRequired        Code = "ADMIN_REQUIRED"
+	CodeSuperAdminRequired   Code = "SUPER_ADMIN_REQUIRED"
+	CodeInvalidCredentials   Code = "INVALID_CREDENTIALS"
+	CodeAccountDisabled      Code = "ACCOUNT_DISABLED"
+	CodeLoginLocked          Code = "LOGIN_LOCKED"
+	CodeRefreshTokenRequired Code = "REFRESH_TOKEN_REQUIRED"
+	CodeRefreshTokenInvalid  Code = "REFRESH_TOKEN_INVALID"
+	CodeRefreshTokenExpired  Code = "REFRESH_TOKEN_EXPIRED"
+	CodeAccountNotDeletable  Code = "ACCOUNT_NOT_DELETABLE"
+)
+
+// Password dan email
+const (
+	CodeCurrentPasswordIncorrect Code = "CURRENT_PASSWORD_INCORRECT"
+	CodeResetTokenInvalid        Code = "RESET_TOKEN_INVALID"
+	CodeResetTokenExpired        Code = "RESET_TOKEN_EXPIRED"
+	CodeVerificationTokenInvalid Code = "VERIFICATION_TOKEN_INVALID"
+	CodeEmailAlreadyVerified     Code = "EMAIL_ALREADY_VERIFIED"
+	CodeEmailNotVerified         Code = "EMAIL_NOT_VERIFIED"
+)
+
+// Data
+const (
+	CodeNIMAlreadyRegistered Code = "NIM_ALREADY_REGISTERED"
+	CodeUserNotFound         Code = "USER_NOT_FOUND"
+	CodeRegistrationNotFound Code = "REGISTRATION_NOT_FOUND"
+	CodeInformationNotFound  Code = "INFORMATION_NOT_FOUND"
+	CodeDivisionNotFound     Code = "DIVISION_NOT_FOUND"
+	CodeDivisionNameTaken    Code = "DIVISION_NAME_TAKEN"
+	CodeDivisionInUse        Code = "DIVISION_IN_USE"
+	CodePeriodNotFound       Code = "PERIOD_NOT_FOUND"
+	CodePeriodAlreadyActive  Code = "PERIOD_ALREADY_ACTIVE"
+	CodePeriodInUse          Code = "PERIOD_IN_USE"
+	CodeRegistrationClosed   Code = "REGISTRATION_CLOSED"
+	CodeAlreadyRegistered    Code = "ALREADY_REGISTERED"
+	CodeRegistrationLocked   Code = "REGISTRATION_LOCKED"
+	CodeInvalidTransition    Code = "INVALID_STATUS_TRANSITION"
+	CodeNoteNotFound         Code = "NOTE_NOT_FOUND"
+	CodeNoteNotAuthor        Code = "NOTE_NOT_AUTHOR"
+	CodeSlotNotFound         Code = "INTERVIEW_SLOT_NOT_FOUND"
+	CodeSlotFull             Code = "INTERVIEW_SLOT_FULL"
+	CodeSlotInUse            Code = "INTERVIEW_SLOT_IN_USE"
+	CodeSlotPassed           Code = "INTERVIEW_SLOT_PASSED"
+	CodeInterviewerConflict  Code = "INTERVIEWER_CONFLICT"
+	CodeAlreadyBooked        Code = "INTERVIEW_ALREADY_BOOKED"
+	CodeNotInInterviewStage  Code = "NOT_IN_INTERVIEW_STAGE"
+	CodeRubricNotConfigured  Code = "RUBRIC_NOT_CONFIGURED"
+	CodeNotInterviewer       Code = "NOT_INTERVIEWER"
+	CodePlacementChanged     Code = "PLACEMENT_CHANGED"
+	CodeStatusChanged        Code = "STATUS_CHANGED"
+)
@@ -0,0 +1,65 @@
+// Package response menulis respons JSON API dengan format yang seragam.
+//
+// Semua error dikirim dalam envelope:
+//
+//	{"error": {"code": "NIM_ALREADY_REGISTERED", "message": "...", "details": ..., "request_id": "..."}}
+//
+// Frontend sebaiknya bercabang berdasarkan "code" (lihat codes.go), bukan "message".
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// ErrorBody adalah isi envelope error
+type ErrorBody struct {
+	Code      Code        `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// ErrorEnvelope adalah bentuk lengkap respons error
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type requestIDKey struct{}
+
+// WithRequestID menyimpan request ID ke context agar ikut tercantum di respons error
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID mengambil request ID dari context, kosong jika tidak ada
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// JSON menulis v sebagai JSON dengan status code yang diberikan
+func JSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+// Error menulis envelope error tanpa detail
+func Error(w http.ResponseWriter, r *http.Request, status int, code Code, message string) {
+	ErrorWithDetails(w, r, status, code, message, nil)
+}
+
+// ErrorWithDetails menulis envelope error beserta detail tambahan (misal daftar kesalahan per field)
+func ErrorWithDetails(w http.ResponseWriter, r *http.Request, status int, code Code, message string, details interface{}) {
+	JSON(w, status, ErrorEnvelope{Error: ErrorBody{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: RequestID(r.Context()),
+	}})
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/handler"
+	"github.com/ulbithebest/BE-pendaftaran/internal/middleware"
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/storage"
+
+	"github.com/go-chi/chi/v5"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+)
+
+// AllowedOrigins adalah daftar origin frontend yang diizinkan oleh CORS
+var AllowedOrigins = []string{
+	"https://ulbithebest.github.io", // GitHub Pages frontend
+	"http://localhost:5500",         // Local development
+	"http://127.0.0.1:5500",
+	"http://127.0.0.1:5501",
+	"http://localhost:5501",
+}
+
+var (
+	allowedMethods = []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"}
+	allowedHeaders = []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With"}
+)
+
+const corsMaxAge = 300
+
+// Options menampung perbedaan konfigurasi antar environment (server lokal vs Cloud Function)
+type Options struct {
+	// Repositories adalah sumber data handler (MongoDB di produksi, in-memory saat pengujian)
+	Repositories *repository.Repositories
+	// Storage adalah tempat penyimpanan berkas unggahan pendaftaran
+	Storage storage.FileStorage
+	// Notifier mengirim pesan ke user (reset password, dsb)
+	Notifier notify.Notifier
+	// RateLimitStore menyimpan token bucket rate limit. Jika nil, dipakai store in-process.
+	RateLimitStore middleware.RateLimitStore
+	// UploadsDir adalah folder yang disajikan di /api/uploads/*.
+	// Route hanya didaftarkan jika folder tersebut ada.
+	UploadsDir string
+}
+
+// IsAllowedOrigin mengecek apakah origin termasuk dalam allowlist CORS
+func IsAllowedOrigin(origin string) bool {
+	for _, allowed := range AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCORSHeaders menulis header CORS secara manual, dipakai oleh entry point
+// Cloud Function untuk menjawab preflight sebelum router diinisialisasi
+func SetCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if !IsAllowedOrigin(origin) {
+		return
+	}
+	// Mirror the request origin (required when credentials are used)
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Vary", "Origin")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+}
+
+// NewRouter membangun router Chi beserta middleware, CORS, dan seluruh route aplikasi.
+// Dipakai oleh run/main.go dan entry point Cloud Function agar keduanya selalu sama.
+func NewRouter(opts Options) *chi.Mux {
+	h := handler.New(handler.Dependencies{
+		Repositories: opts.Repositories,
+		Storage:      opts.Storage,
+		Notifier:     opts.Notifier,
+	})
+
+	cfg := config.GetConfig()
+	rateLimitStore := opts.RateLimitStore
+	if rateLimitStore == nil {
+		rateLimitStore = repository.NewMemoryRateLimitRepository()
+	}
+	authRateLimit := middleware.RateLimit(rateLimitStore, middleware.RateLimitRule{
+		Name: "auth", Requests: cfg.RateLimitAuth.Requests, Per: cfg.RateLimitAuth.Per, By: middleware.RateLimitByIP,
+	})
+	apiRateLimit := middleware.RateLimit(rateLimitStore, middleware.RateLimitRule{
+		Name: "api", Requests: cfg.RateLimitAPI.Requests, Per: cfg.RateLimitAPI.Per, By: middleware.RateLimitByUser,
+	})
+	uploadRateLimit := middleware.RateLimit(rateLimitStore, middleware.RateLimitRule{
+		Name: "upload", Requests: cfg.RateLimitUpload.Requests, Per: cfg.RateLimitUpload.Per, By: middleware.RateLimitByUser,
+	})
+
+	r := chi.NewRouter()
+
+	// Middleware global
+	r.Use(middleware.RequestIDMiddleware)
+	r.Use(middleware.RequestLogMiddleware)
+	r.Use(chiMiddleware.Logger)    // Middleware untuk mencatat (log) setiap request yang masuk
+	r.Use(chiMiddleware.Recoverer) // Middleware untuk menangani panic dan menjaga server tetap hidup
+
+	// CORS (Cross-Origin Resource Sharing)
+	r.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   AllowedOrigins,
+		AllowedMethods:   allowedMethods,
+		AllowedHeaders:   allowedHeaders,
+		ExposedHeaders:   []string{"Link", "Retry-After", middleware.RequestIDHeader},
+		AllowCredentials: true,
+		MaxAge:           corsMaxAge,
+	}))
+
+	// Route/method yang tidak dikenal tetap dijawab dengan envelope error JSON
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		response.Error(w, r, http.StatusNotFound, response.CodeRouteNotFound, "Endpoint tidak ditemukan")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		response.Error(w, r, http.StatusMethodNotAllowed, response.CodeMethodNotAllowed, "Method tidak diizinkan untuk endpoint ini")
+	})
+
+	// Health check
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		response.JSON(w, http.StatusOK, map[string]string{"status": "healthy", "service": "pendaftaran-api"})
+	})
+
+	// Routes publik yang bisa diakses tanpa login/token (dibatasi per IP)
+	r.Group(func(r chi.Router) {
+		r.Use(authRateLimit)
+
+		r.Post("/register", h.RegisterHandler)
+		r.Post("/login", h.LoginHandler)
+		r.Post("/auth/refresh", h.RefreshTokenHandler)
+		r.Post("/auth/logout", h.LogoutHandler)
+		r.Post("/forgot-password", h.ForgotPasswordHandler)
+		r.Post("/reset-password", h.ResetPasswordHandler)
+		r.Post("/verify-email", h.VerifyEmailHandler)
+	})
+
+	// Group routes yang memerlukan otentikasi (wajib ada token Paseto, dibatasi per user)
+	r.Route("/api", func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware(opts.Repositories.Tokens, opts.Repositories.Users))
+		r.Use(apiRateLimit)
+
+		// --- Routes untuk user biasa ---
+		r.Get("/user/profile", h.GetUserProfileHandler)
+		r.Patch("/user/password", h.ChangePasswordHandler)
+		r.Post("/user/verify-email/resend", h.ResendVerificationEmailHandler)
+		r.With(uploadRateLimit).Post("/user/registration", h.SubmitRegistrationHandler)
+		r.With(uploadRateLimit).Put("/user/registration", h.UpdateRegistrationHandler)
+		r.Post("/user/registration/withdraw", h.WithdrawRegistrationHandler)
+		r.Get("/user/my-registration", h.GetUserRegistrationHandler)
+		r.Delete("/user/account", h.DeleteAccountHandler)
+		r.Get("/user/data-export", h.ExportUserDataHandler)
+		r.Get("/user/interview-slots", h.GetAvailableInterviewSlotsHandler)
+		r.Put("/user/interview-slot", h.BookInterviewSlotHandler)
+		r.Get("/info", h.GetAllInfoHandler)
+		r.Get("/divisions", h.GetDivisionsHandler)
+		r.Get("/periods/active", h.GetActivePeriodHandler)
+
+		// File server untuk berkas lokal (dilindungi otentikasi)
+		if opts.UploadsDir != "" {
+			if _, err := os.Stat(opts.UploadsDir); err == nil {
+				fileServer := http.FileServer(http.Dir(opts.UploadsDir))
+				r.Handle("/uploads/*", http.StripPrefix("/api/uploads/", fileServer))
+			}
+		}
+
+		// --- Routes khusus admin ---
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.AdminOnlyMiddleware)
+
+			r.Get("/registrations-with-details", h.GetAllRegistrationsDetailHandler)
+			r.Get("/registrations/export", h.ExportRegistrationsHandler)
+			r.Get("/registration-statuses", h.GetRegistrationStatusesHandler)
+			r.Get("/search", h.SearchHandler)
+			r.Get("/stats", h.GetStatsHandler)
+			r.Get("/placements/preview", h.PreviewPlacementHandler)
+			r.Post("/placements/apply", h.ApplyPlacementHandler)
+			r.Patch("/registrations/{id}", h.UpdateRegistrationDetailsHandler)
+			r.Get("/registrations/{id}/history", h.GetRegistrationHistoryHandler)
+			r.Get("/registrations/{id}/scores", h.GetRegistrationScoresHandler)
+			r.Put("/registrations/{id}/scores", h.SubmitInterviewScoreHandler)
+			r.Get("/registrations/{id}/notes", h.GetRegistrationNotesHandler)
+			r.Post("/registrations/{id}/notes", h.CreateRegistrationNoteHandler)
+			r.Put("/registrations/{id}/notes/{noteId}", h.UpdateRegistrationNoteHandler)
+			r.Delete("/registrations/{id}/notes/{noteId}", h.DeleteRegistrationNoteHandler)
+			r.Patch("/registrations/bulk-update", h.BulkUpdateStatusHandler)
+			r.Delete("/registrations/{id}", h.DeleteRegistrationHandler)
+			r.Get("/users", h.GetAllUsersHandler)
+			r.Post("/info", h.CreateInfoHandler)
+			r.Put("/info/{id}", h.UpdateInfoHandler)
+			r.Delete("/info/{id}", h.DeleteInfoHandler)
+			r.Get("/divisions", h.GetAllDivisionsHandler)
+			r.Post("/divisions", h.CreateDivisionHandler)
+			r.Put("/divisions/{id}", h.UpdateDivisionHandler)
+			r.Delete("/divisions/{id}", h.DeleteDivisionHandler)
+			r.Put("/divisions/{id}/rubric", h.SetDivisionRubricHandler)
+			r.Get("/periods", h.GetAllPeriodsHandler)
+			r.Post("/periods", h.CreatePeriodHandler)
+			r.Put("/periods/{id}", h.UpdatePeriodHandler)
+			r.Delete("/periods/{id}", h.DeletePeriodHandler)
+			r.Get("/interview-slots", h.GetInterviewSlotsHandler)
+			r.Post("/interview-slots/generate", h.GenerateInterviewSlotsHandler)
+			r.Put("/interview-slots/{id}", h.UpdateInterviewSlotHandler)
+			r.Delete("/interview-slots/{id}", h.DeleteInterviewSlotHandler)
+			r.Post("/interview-slots/{id}/registrations", h.AssignInterviewSlotHandler)
+			r.Delete("/interview-slots/{id}/registrations/{registrationId}", h.UnassignInterviewSlotHandler)
+
+			// --- Routes khusus super admin ---
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.SuperAdminOnlyMiddleware)
+
+				r.Patch("/users/{id}", h.UpdateUserHandler)
+				r.Patch("/users/{id}/password", h.ResetUserPasswordHandler)
+				r.Patch("/users/{id}/status", h.SetUserDisabledHandler)
+				r.Post("/users/{id}/unlock", h.UnlockUserLoginHandler)
+				r.Get("/logs", h.GetAppLogsHandler)
+				r.Post("/import", h.ImportUsersHandler)
+			})
+		})
+	})
+
+	return r
+}
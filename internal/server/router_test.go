@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ulbithebest/BE-pendaftaran/internal/auth"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+	"github.com/ulbithebest/BE-pendaftaran/internal/model"
+	"github.com/ulbithebest/BE-pendaftaran/internal/notify"
+	"github.com/ulbithebest/BE-pendaftaran/internal/repository"
+	"github.com/ulbithebest/BE-pendaftaran/internal/response"
+	"github.com/ulbithebest/BE-pendaftaran/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("PASETO_SECRET_KEY", "R4nd0mS3cr3tK3yF0rP4s3t0Appl1c4t")
+	os.Setenv("RATE_LIMIT_AUTH", "off")
+	os.Setenv("RATE_LIMIT_API", "off")
+	os.Setenv("RATE_LIMIT_UPLOAD", "off")
+	config.GetConfig()
+	config.LoadDatabaseCredentials(map[string]string{})
+	os.Exit(m.Run())
+}
+
+// Role minimum yang dibutuhkan sebuah endpoint
+const (
+	rolePublic     = ""
+	roleUser       = "user"
+	roleAdmin      = "admin"
+	roleSuperAdmin = "super_admin"
+)
+
+// lowerRole adalah role tepat di bawah role yang dibutuhkan, untuk memastikan endpoint menolaknya
+var lowerRole = map[string]string{
+	roleAdmin:      roleUser,
+	roleSuperAdmin: roleAdmin,
+}
+
+// authCodes adalah kode error dari middleware otentikasi dan otorisasi. Handler sendiri bisa saja
+// menjawab 401/403 karena aturan bisnis (misal pendaftaran ditutup), jadi yang dicek adalah kodenya.
+var authCodes = map[response.Code]bool{
+	response.CodeAuthHeaderMissing:  true,
+	response.CodeAuthHeaderInvalid:  true,
+	response.CodeTokenInvalid:       true,
+	response.CodeTokenRevoked:       true,
+	response.CodeSessionInvalidated: true,
+	response.CodeAdminRequired:      true,
+	response.CodeSuperAdminRequired: true,
+}
+
+// forbiddenCode adalah kode yang diharapkan saat role di bawah role yang dibutuhkan
+var forbiddenCode = map[string]response.Code{
+	roleAdmin:      response.CodeAdminRequired,
+	roleSuperAdmin: response.CodeSuperAdminRequired,
+}
+
+type routeCase struct {
+	method string
+	path   string
+	role   string
+}
+
+// routeTable berisi semua endpoint yang terdokumentasi di README beserta role minimumnya
+func routeTable() []routeCase {
+	id := primitive.NewObjectID().Hex()
+	other := primitive.NewObjectID().Hex()
+	return []routeCase{
+		{"GET", "/health", rolePublic},
+		{"POST", "/register", rolePublic},
+		{"POST", "/login", rolePublic},
+		{"POST", "/auth/refresh", rolePublic},
+		{"POST", "/auth/logout", rolePublic},
+		{"POST", "/forgot-password", rolePublic},
+		{"POST", "/reset-password", rolePublic},
+		{"POST", "/verify-email", rolePublic},
+
+		{"GET", "/api/user/profile", roleUser},
+		{"PATCH", "/api/user/password", roleUser},
+		{"POST", "/api/user/verify-email/resend", roleUser},
+		{"POST", "/api/user/registration", roleUser},
+		{"PUT", "/api/user/registration", roleUser},
+		{"POST", "/api/user/registration/withdraw", roleUser},
+		{"GET", "/api/user/my-registration", roleUser},
+		{"DELETE", "/api/user/account", roleUser},
+		{"GET", "/api/user/data-export", roleUser},
+		{"GET", "/api/user/interview-slots", roleUser},
+		{"PUT", "/api/user/interview-slot", roleUser},
+		{"GET", "/api/info", roleUser},
+		{"GET", "/api/divisions", roleUser},
+		{"GET", "/api/periods/active", roleUser},
+
+		{"GET", "/api/admin/registrations-with-details", roleAdmin},
+		{"GET", "/api/admin/registrations/export", roleAdmin},
+		{"GET", "/api/admin/registration-statuses", roleAdmin},
+		{"GET", "/api/admin/search", roleAdmin},
+		{"GET", "/api/admin/stats", roleAdmin},
+		{"GET", "/api/admin/placements/preview", roleAdmin},
+		{"POST", "/api/admin/placements/apply", roleAdmin},
+		{"PATCH", "/api/admin/registrations/" + id, roleAdmin},
+		{"GET", "/api/admin/registrations/" + id + "/history", roleAdmin},
+		{"GET", "/api/admin/registrations/" + id + "/scores", roleAdmin},
+		{"PUT", "/api/admin/registrations/" + id + "/scores", roleAdmin},
+		{"GET", "/api/admin/registrations/" + id + "/notes", roleAdmin},
+		{"POST", "/api/admin/registrations/" + id + "/notes", roleAdmin},
+		{"PUT", "/api/admin/registrations/" + id + "/notes/" + other, roleAdmin},
+		{"DELETE", "/api/admin/registrations/" + id + "/notes/" + other, roleAdmin},
+		{"PATCH", "/api/admin/registrations/bulk-update", roleAdmin},
+		{"DELETE", "/api/admin/registrations/" + id, roleAdmin},
+		{"GET", "/api/admin/users", roleAdmin},
+		{"POST", "/api/admin/info", roleAdmin},
+		{"PUT", "/api/admin/info/" + id, roleAdmin},
+		{"DELETE", "/api/admin/info/" + id, roleAdmin},
+		{"GET", "/api/admin/divisions", roleAdmin},
+		{"POST", "/api/admin/divisions", roleAdmin},
+		{"PUT", "/api/admin/divisions/" + id, roleAdmin},
+		{"DELETE", "/api/admin/divisions/" + id, roleAdmin},
+		{"PUT", "/api/admin/divisions/" + id + "/rubric", roleAdmin},
+		{"GET", "/api/admin/periods", roleAdmin},
+		{"POST", "/api/admin/periods", roleAdmin},
+		{"PUT", "/api/admin/periods/" + id, roleAdmin},
+		{"DELETE", "/api/admin/periods/" + id, roleAdmin},
+		{"GET", "/api/admin/interview-slots", roleAdmin},
+		{"POST", "/api/admin/interview-slots/generate", roleAdmin},
+		{"PUT", "/api/admin/interview-slots/" + id, roleAdmin},
+		{"DELETE", "/api/admin/interview-slots/" + id, roleAdmin},
+		{"POST", "/api/admin/interview-slots/" + id + "/registrations", roleAdmin},
+		{"DELETE", "/api/admin/interview-slots/" + id + "/registrations/" + other, roleAdmin},
+
+		{"PATCH", "/api/admin/users/" + id, roleSuperAdmin},
+		{"PATCH", "/api/admin/users/" + id + "/password", roleSuperAdmin},
+		{"PATCH", "/api/admin/users/" + id + "/status", roleSuperAdmin},
+		{"POST", "/api/admin/users/" + id + "/unlock", roleSuperAdmin},
+		{"GET", "/api/admin/logs", roleSuperAdmin},
+		{"POST", "/api/admin/import", roleSuperAdmin},
+	}
+}
+
+type routeTester struct {
+	t      *testing.T
+	srv    *httptest.Server
+	tokens map[string]string
+}
+
+func newRouteTester(t *testing.T) *routeTester {
+	t.Helper()
+	repos := repository.NewMemoryRepositories()
+	router := NewRouter(Options{
+		Repositories: repos,
+		Storage:      storage.NewMemoryStorage(),
+		Notifier:     notify.NewMemoryNotifier(),
+	})
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	tokens := map[string]string{}
+	for i, role := range []string{roleUser, roleAdmin, roleSuperAdmin} {
+		user := &model.User{Name: "Test " + role, NIM: "71422000" + string(rune('1'+i)), Role: role}
+		if err := repos.Users.Create(context.Background(), user); err != nil {
+			t.Fatalf("create %s: %v", role, err)
+		}
+		token, _, err := auth.GenerateToken(user.ID, user.NIM, role, user.TokenVersion)
+		if err != nil {
+			t.Fatalf("token %s: %v", role, err)
+		}
+		tokens[role] = token
+	}
+	return &routeTester{t: t, srv: srv, tokens: tokens}
+}
+
+// do mengirim request tanpa body dan mengembalikan status serta kode error (jika ada)
+func (rt *routeTester) do(method, path, role string) (int, response.Code) {
+	rt.t.Helper()
+	req, err := http.NewRequest(method, rt.srv.URL+path, strings.NewReader(""))
+	if err != nil {
+		rt.t.Fatal(err)
+	}
+	if role != rolePublic {
+		req.Header.Set("Authorization", "Bearer "+rt.tokens[role])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		rt.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Error struct {
+			Code response.Code `json:"code"`
+		} `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body.Error.Code
+}
+
+func TestRouteTable(t *testing.T) {
+	rt := newRouteTester(t)
+
+	for _, tc := range routeTable() {
+		tc := tc
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			rt.t = t
+			if tc.role != rolePublic {
+				if status, code := rt.do(tc.method, tc.path, rolePublic); status != http.StatusUnauthorized || code != response.CodeAuthHeaderMissing {
+					t.Errorf("tanpa token: %d %s, ingin 401 %s", status, code, response.CodeAuthHeaderMissing)
+				}
+			}
+			if lower, ok := lowerRole[tc.role]; ok {
+				if status, code := rt.do(tc.method, tc.path, lower); status != http.StatusForbidden || code != forbiddenCode[tc.role] {
+					t.Errorf("role %s: %d %s, ingin 403 %s", lower, status, code, forbiddenCode[tc.role])
+				}
+			}
+
+			status, code := rt.do(tc.method, tc.path, tc.role)
+			if code == response.CodeRouteNotFound || code == response.CodeMethodNotAllowed {
+				t.Errorf("role %s: route tidak ditemukan (%d %s)", tc.role, status, code)
+			}
+			if authCodes[code] {
+				t.Errorf("role %s: %d %s, route seharusnya bisa diakses", tc.role, status, code)
+			}
+		})
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	rt := newRouteTester(t)
+
+	if status, code := rt.do("GET", "/api/admin/does-not-exist", roleSuperAdmin); status != http.StatusNotFound || code != response.CodeRouteNotFound {
+		t.Errorf("status %d %s, ingin 404 %s", status, code, response.CodeRouteNotFound)
+	}
+	if status, code := rt.do("DELETE", "/health", rolePublic); status != http.StatusMethodNotAllowed || code != response.CodeMethodNotAllowed {
+		t.Errorf("status %d %s, ingin 405 %s", status, code, response.CodeMethodNotAllowed)
+	}
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/ulbithebest/BE-pendaftaran/internal/config"
+)
+
+// FileStorage menyimpan berkas unggahan dan mengembalikan URL publiknya
+type FileStorage interface {
+	// Upload mengunggah berkas dengan publicID tertentu. resourceType mengikuti
+	// Cloudinary ("auto", "image", "raw").
+	Upload(ctx context.Context, file io.Reader, publicID, resourceType string) (string, error)
+	// Delete menghapus berkas berdasarkan URL yang dikembalikan Upload. Berkas yang sudah tidak ada
+	// tidak dianggap error.
+	Delete(ctx context.Context, url string) error
+}
+
+// CloudinaryStorage mengunggah berkas ke Cloudinary memakai credentials dari config
+type CloudinaryStorage struct{}
+
+// NewCloudinaryStorage membuat CloudinaryStorage. Client dibuat per upload karena
+// credentials baru dimuat setelah koneksi database terbentuk.
+func NewCloudinaryStorage() *CloudinaryStorage {
+	return &CloudinaryStorage{}
+}
+
+func (s *CloudinaryStorage) Upload(ctx context.Context, file io.Reader, publicID, resourceType string) (string, error) {
+	cfg := config.GetConfig()
+	cld, err := cloudinary.NewFromParams(cfg.CloudinaryCloudName, cfg.CloudinaryApiKey, cfg.CloudinaryApiSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Cloudinary: %w", err)
+	}
+
+	result, err := cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		PublicID:      publicID,
+		ResourceType:  resourceType,
+		Overwrite:     api.Bool(true),
+		AccessControl: []api.AccessControlRule{{AccessType: "anonymous"}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.Error.Message != "" {
+		return "", fmt.Errorf("cloudinary: %s", result.Error.Message)
+	}
+
+	return result.SecureURL, nil
+}
+
+func (s *CloudinaryStorage) Delete(ctx context.Context, url string) error {
+	publicID, resourceType, ok := cloudinaryAsset(url)
+	if !ok {
+		return fmt.Errorf("bukan URL Cloudinary: %s", url)
+	}
+
+	cfg := config.GetConfig()
+	cld, err := cloudinary.NewFromParams(cfg.CloudinaryCloudName, cfg.CloudinaryApiKey, cfg.CloudinaryApiSecret)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Cloudinary: %w", err)
+	}
+
+	result, err := cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     publicID,
+		ResourceType: resourceType,
+		Invalidate:   api.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error.Message != "" {
+		return fmt.Errorf("cloudinary: %s", result.Error.Message)
+	}
+	if result.Result != "ok" && result.Result != "not found" {
+		return fmt.Errorf("cloudinary: gagal menghapus %s: %s", publicID, result.Result)
+	}
+	return nil
+}
+
+// cloudinaryURLPattern membaca resource type dan path berkas dari URL seperti
+// https://res.cloudinary.com/<cloud>/image/upload/v1700000000/himatif-registrations/123_cv_1700000000.png
+var cloudinaryURLPattern = regexp.MustCompile(`^https?://[^/]+/[^/]+/(image|video|raw)/upload/(?:v\d+/)?(.+)$`)
+
+// cloudinaryAsset mengambil Public ID dan resource type dari URL Cloudinary. Ekstensi berkas
+// bukan bagian dari Public ID, kecuali untuk resource type raw.
+func cloudinaryAsset(url string) (publicID, resourceType string, ok bool) {
+	match := cloudinaryURLPattern.FindStringSubmatch(url)
+	if match == nil {
+		return "", "", false
+	}
+	resourceType, publicID = match[1], match[2]
+	if resourceType != "raw" {
+		publicID = strings.TrimSuffix(publicID, path.Ext(publicID))
+	}
+	return publicID, resourceType, true
+}
+
+// MemoryStorage menyimpan berkas di memori, berguna untuk pengujian tanpa Cloudinary
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemoryStorage membuat MemoryStorage kosong
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{files: make(map[string][]byte)}
+}
+
+func (s *MemoryStorage) Upload(ctx context.Context, file io.Reader, publicID, resourceType string) (string, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[publicID] = content
+
+	return "memory://" + publicID, nil
+}
+
+// File mengembalikan isi berkas yang sudah diunggah
+func (s *MemoryStorage) File(publicID string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	content, ok := s.files[publicID]
+	return content, ok
+}
+
+func (s *MemoryStorage) Delete(ctx context.Context, url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.files, strings.TrimPrefix(url, "memory://"))
+	return nil
+}
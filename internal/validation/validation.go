@@ -0,0 +1,253 @@
+// Package validation memvalidasi struct berdasarkan tag `validate` dan `label`.
+//
+// Contoh:
+//
+//	Email string `json:"email" validate:"required,email" label:"Email"`
+//
+// Aturan yang didukung:
+//   - required        : tidak boleh kosong
+//   - min=N / max=N   : panjang minimal/maksimal (karakter), atau nilai minimal/maksimal untuk field int
+//   - email           : format alamat email
+//   - nim             : angka 6 hingga 15 digit
+//   - phone           : nomor telepon 10 hingga 13 digit (boleh diawali +)
+//   - date            : tanggal YYYY-MM-DD yang tidak di masa depan
+//   - oneof=a b c     : salah satu dari nilai yang disebutkan
+//   - nefield=Field   : tidak boleh sama dengan field lain pada struct yang sama
+//   - notrim          : nilai divalidasi apa adanya tanpa membuang spasi di awal/akhir (untuk password)
+//
+// Selain notrim, nilai di-trim sebelum aturan dijalankan.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Kode kesalahan per field yang stabil, dipakai frontend untuk lokalisasi
+const (
+	CodeRequired      = "REQUIRED"
+	CodeMinLength     = "MIN_LENGTH"
+	CodeMaxLength     = "MAX_LENGTH"
+	CodeInvalidEmail  = "INVALID_EMAIL"
+	CodeInvalidNIM    = "INVALID_NIM"
+	CodeInvalidPhone  = "INVALID_PHONE"
+	CodeInvalidDate   = "INVALID_DATE"
+	CodeDateInFuture  = "DATE_IN_FUTURE"
+	CodeInvalidOption = "INVALID_OPTION"
+	CodeMustDiffer    = "MUST_DIFFER"
+	CodeMinValue      = "MIN_VALUE"
+	CodeMaxValue      = "MAX_VALUE"
+)
+
+// FieldError adalah kesalahan validasi pada satu field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors berisi semua kesalahan validasi sekaligus
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add menambahkan kesalahan untuk field tertentu
+func (e *Errors) Add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Has mengecek apakah field tertentu sudah memiliki kesalahan
+func (e Errors) Has(field string) bool {
+	for _, fieldErr := range e {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Struct memvalidasi semua field string dan int pada struct v (atau pointer ke struct)
+// dan mengembalikan seluruh kesalahan yang ditemukan
+func Struct(v interface{}) Errors {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+
+		var code, message string
+		switch field.Type.Kind() {
+		case reflect.String:
+			code, message = apply(rules, labelOf(field), value.Field(i).String(), value)
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			code, message = applyInt(rules, labelOf(field), value.Field(i).Int())
+		}
+		if code != "" {
+			errs.Add(jsonName(field), code, message)
+		}
+	}
+	return errs
+}
+
+// Var memvalidasi satu nilai dengan aturan yang sama seperti tag `validate`
+// (kecuali nefield) dan menambahkan kesalahannya ke errs
+func Var(errs *Errors, field, label, value, rules string) {
+	if code, message := apply(rules, label, value, reflect.Value{}); code != "" {
+		errs.Add(field, code, message)
+	}
+}
+
+// apply menjalankan aturan secara berurutan dan mengembalikan kesalahan pertama
+func apply(rules, label, value string, parent reflect.Value) (string, string) {
+	ruleList := strings.Split(rules, ",")
+	if !hasRule(ruleList, "notrim") {
+		value = strings.TrimSpace(value)
+	}
+	for _, rule := range ruleList {
+		ruleName, param, _ := strings.Cut(rule, "=")
+		if ruleName != "required" && value == "" {
+			continue
+		}
+		if code, message := check(ruleName, param, label, value, parent); code != "" {
+			return code, message
+		}
+	}
+	return "", ""
+}
+
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyInt menjalankan aturan min/max pada field bilangan bulat
+func applyInt(rules, label string, value int64) (string, string) {
+	for _, rule := range strings.Split(rules, ",") {
+		ruleName, param, _ := strings.Cut(rule, "=")
+		n, _ := strconv.ParseInt(param, 10, 64)
+		switch {
+		case ruleName == "min" && value < n:
+			return CodeMinValue, fmt.Sprintf("%s tidak boleh kurang dari %d", label, n)
+		case ruleName == "max" && value > n:
+			return CodeMaxValue, fmt.Sprintf("%s tidak boleh lebih dari %d", label, n)
+		}
+	}
+	return "", ""
+}
+
+func check(rule, param, label, value string, parent reflect.Value) (string, string) {
+	switch rule {
+	case "required":
+		if value == "" {
+			return CodeRequired, fmt.Sprintf("%s wajib diisi", label)
+		}
+	case "min":
+		if n, _ := strconv.Atoi(param); utf8.RuneCountInString(value) < n {
+			return CodeMinLength, fmt.Sprintf("%s minimal %d karakter", label, n)
+		}
+	case "max":
+		if n, _ := strconv.Atoi(param); utf8.RuneCountInString(value) > n {
+			return CodeMaxLength, fmt.Sprintf("%s maksimal %d karakter", label, n)
+		}
+	case "email":
+		if !IsEmail(value) {
+			return CodeInvalidEmail, fmt.Sprintf("%s tidak valid", label)
+		}
+	case "nim":
+		if !isDigits(value) || len(value) < 6 || len(value) > 15 {
+			return CodeInvalidNIM, fmt.Sprintf("%s harus berupa angka 6 hingga 15 digit", label)
+		}
+	case "phone":
+		digits := strings.TrimPrefix(value, "+")
+		if !isDigits(digits) || len(digits) < 10 || len(digits) > 13 {
+			return CodeInvalidPhone, "Nomor telepon harus antara 10 hingga 13 digit."
+		}
+	case "date":
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return CodeInvalidDate, fmt.Sprintf("%s harus berformat YYYY-MM-DD", label)
+		}
+		if date.After(time.Now()) {
+			return CodeDateInFuture, fmt.Sprintf("%s tidak boleh di masa depan", label)
+		}
+	case "oneof":
+		for _, option := range strings.Fields(param) {
+			if value == option {
+				return "", ""
+			}
+		}
+		return CodeInvalidOption, fmt.Sprintf("%s tidak valid", label)
+	case "nefield":
+		if !parent.IsValid() {
+			return "", ""
+		}
+		other, ok := parent.Type().FieldByName(param)
+		if !ok {
+			return "", ""
+		}
+		if strings.EqualFold(value, strings.TrimSpace(parent.FieldByName(param).String())) {
+			return CodeMustDiffer, fmt.Sprintf("%s tidak boleh sama dengan %s", label, labelOf(other))
+		}
+	}
+	return "", ""
+}
+
+// IsEmail mengecek format alamat email sederhana (tanpa nama tampilan)
+func IsEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return false
+	}
+	at := strings.LastIndex(value, "@")
+	return at > 0 && strings.Contains(value[at:], ".")
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func labelOf(field reflect.StructField) string {
+	if label := field.Tag.Get("label"); label != "" {
+		return label
+	}
+	return field.Name
+}
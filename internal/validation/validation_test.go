@@ -0,0 +1,34 @@
+package validation
+
+import "testing"
+
+func TestVarTrimsValues(t *testing.T) {
+	var errs Errors
+	Var(&errs, "name", "Nama", "   ", "required")
+	if !errs.Has("name") || errs[0].Code != CodeRequired {
+		t.Errorf("nilai berisi spasi harus dianggap kosong: %v", errs)
+	}
+}
+
+func TestVarNoTrim(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"      ", ""},
+		{"  abcd  ", ""},
+		{" ab ", CodeMinLength},
+		{"", CodeRequired},
+	}
+	for _, tc := range cases {
+		var errs Errors
+		Var(&errs, "password", "Password", tc.value, "notrim,required,min=6")
+		got := ""
+		if len(errs) > 0 {
+			got = errs[0].Code
+		}
+		if got != tc.want {
+			t.Errorf("password %q: kode %q, ingin %q", tc.value, got, tc.want)
+		}
+	}
+}